@@ -0,0 +1,96 @@
+// Command fakegraph runs the fake WhatsApp Cloud API server for local
+// development. Point the API server at it with WHATSAPP_API_BASE_URL and
+// drive inbound webhooks through its /_fake control endpoints.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp/fake"
+	"github.com/ashok/vibecoded-wa-client/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func main() {
+	addr := flag.String("addr", envOr("FAKEGRAPH_ADDR", ":9080"), "listen address")
+	publicURL := flag.String("url", envOr("FAKEGRAPH_URL", "http://localhost:9080"), "base URL the fake is reachable at, used in media download URLs")
+	phoneNumberID := flag.String("phone-number-id", envOr("WHATSAPP_PHONE_NUMBER_ID", fake.DefaultPhoneNumberID), "phone number ID to serve")
+	displayPhoneNumber := flag.String("display-phone-number", envOr("WHATSAPP_PHONE_NUMBER", fake.DefaultDisplayPhoneNumber), "display number of the phone number")
+	businessAccountID := flag.String("business-account-id", envOr("WHATSAPP_BUSINESS_ACCOUNT_ID", fake.DefaultBusinessAccountID), "business account ID to serve")
+	apiVersion := flag.String("api-version", envOr("WHATSAPP_API_VERSION", fake.DefaultAPIVersion), "Graph API version prefix")
+	accessToken := flag.String("access-token", os.Getenv("WHATSAPP_ACCESS_TOKEN"), "bearer token to require, any token is accepted when empty")
+	appSecret := flag.String("app-secret", os.Getenv("WHATSAPP_WEBHOOK_SECRET"), "secret used to sign emitted webhooks")
+	webhookURL := flag.String("webhook-url", envOr("FAKEGRAPH_WEBHOOK_URL", "http://localhost:8080/webhooks/whatsapp"), "URL emitted webhooks are delivered to")
+	latency := flag.Duration("latency", 0, "delay added to every Graph API request")
+	autoStatuses := flag.Bool("auto-statuses", true, "emit sent and delivered statuses for each sent message")
+	flag.Parse()
+
+	log, err := logger.InitLogger(logger.Config{Level: "info", Format: "console"})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer log.Sync()
+
+	srv := fake.NewServer(fake.Options{
+		PhoneNumberID:      *phoneNumberID,
+		DisplayPhoneNumber: *displayPhoneNumber,
+		BusinessAccountID:  *businessAccountID,
+		APIVersion:         *apiVersion,
+		AccessToken:        *accessToken,
+		AppSecret:          *appSecret,
+		WebhookURL:         *webhookURL,
+		Latency:            *latency,
+		AutoStatuses:       *autoStatuses,
+	})
+	srv.URL = strings.TrimSuffix(*publicURL, "/")
+
+	httpServer := &http.Server{
+		Addr:    *addr,
+		Handler: srv,
+	}
+
+	serverErrors := make(chan error, 1)
+	go func() {
+		log.Info("Fake Graph API starting",
+			zap.String("addr", *addr),
+			zap.String("base_url", srv.URL),
+			zap.String("phone_number_id", *phoneNumberID),
+			zap.String("webhook_url", *webhookURL),
+		)
+		serverErrors <- httpServer.ListenAndServe()
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErrors:
+		log.Fatal("Server error", zap.Error(err))
+	case sig := <-quit:
+		log.Info("Shutdown signal received", zap.String("signal", sig.String()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Error("Server shutdown error", zap.Error(err))
+	}
+	srv.Close()
+}
+
+// envOr returns the environment variable or the fallback when it is unset
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,289 @@
+// Command simulate posts signed WhatsApp webhooks to a running instance, for
+// testing the webhook handler without Meta.
+//
+//	simulate [flags] <event type> [event flags]
+//	simulate [flags] replay <script.yaml>
+//
+// Defaults for the webhook URL, app secret and phone number are read from the
+// same .env/environment as the server.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/ashok/vibecoded-wa-client/internal/config"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp/simulator"
+)
+
+func main() {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load configuration, using flags only: %v\n", err)
+		cfg = &config.Config{}
+	}
+
+	sim := &simulator.Simulator{}
+	var dryRun bool
+
+	global := flag.NewFlagSet("simulate", flag.ExitOnError)
+	global.StringVar(&sim.WebhookURL, "url", defaultWebhookURL(cfg), "webhook URL of the running instance")
+	global.StringVar(&sim.Secret, "secret", cfg.WhatsApp.WebhookSecret, "app secret used to sign payloads (WHATSAPP_WEBHOOK_SECRET); unsigned when empty")
+	global.BoolVar(&sim.InvalidSignature, "invalid-signature", false, "sign with a wrong secret, to test rejection")
+	global.StringVar(&sim.PhoneNumberID, "phone-number-id", defaultSender(cfg).PhoneNumberID, "business phone number ID that receives the messages")
+	global.StringVar(&sim.DisplayPhoneNumber, "display-phone-number", defaultSender(cfg).PhoneNumber, "display number of the business phone number")
+	global.StringVar(&sim.BusinessAccountID, "business-account-id", cfg.WhatsApp.BusinessAccountID, "WhatsApp Business Account ID")
+	global.BoolVar(&dryRun, "dry-run", false, "print the payload and signature instead of posting")
+	global.Usage = func() { usage(global) }
+	global.Parse(os.Args[1:])
+
+	args := global.Args()
+	if len(args) == 0 {
+		usage(global)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	switch args[0] {
+	case "replay":
+		if len(args) != 2 {
+			fatalf("usage: simulate [flags] replay <script.yaml>")
+		}
+		script, err := simulator.LoadScript(args[1])
+		if err != nil {
+			fatalf("%v", err)
+		}
+		if dryRun {
+			for i := range script.Steps {
+				webhook, err := sim.Build(&script.Steps[i])
+				if err != nil {
+					fatalf("step %d: %v", i+1, err)
+				}
+				printWebhook(webhook)
+			}
+			return
+		}
+		err = sim.Replay(ctx, script, func(step int, event *simulator.Event, result *simulator.Result) error {
+			fmt.Printf("%d. %-12s %s -> %d %s\n", step, event.Type, result.Webhook.MessageID, result.StatusCode, strings.TrimSpace(result.Body))
+			if result.StatusCode >= 300 {
+				return fmt.Errorf("step %d was rejected with status %d", step, result.StatusCode)
+			}
+			return nil
+		})
+		if err != nil {
+			fatalf("%v", err)
+		}
+
+	case "types":
+		fmt.Println(strings.Join(simulator.EventTypes, "\n"))
+
+	default:
+		event, err := parseEvent(args[0], args[1:])
+		if err != nil {
+			fatalf("%v", err)
+		}
+		webhook, err := sim.Build(event)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		if dryRun {
+			printWebhook(webhook)
+			return
+		}
+		result, err := sim.Post(ctx, webhook)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		fmt.Printf("%s %s -> %d %s\n", event.Type, webhook.MessageID, result.StatusCode, strings.TrimSpace(result.Body))
+		if result.StatusCode >= 300 {
+			os.Exit(1)
+		}
+	}
+}
+
+// parseEvent builds an event of the given type from its flags
+func parseEvent(eventType string, args []string) (*simulator.Event, error) {
+	if !isEventType(eventType) {
+		return nil, fmt.Errorf("unknown event type %q, run 'simulate types' to list them", eventType)
+	}
+
+	event := &simulator.Event{Type: eventType}
+	fs := flag.NewFlagSet(eventType, flag.ExitOnError)
+
+	var (
+		location    simulator.Location
+		contact     simulator.Contact
+		reply       simulator.Reply
+		flow        simulator.Flow
+		response    string
+		order       simulator.Order
+		items       itemsFlag
+		hasLocation bool
+	)
+
+	if eventType == simulator.EventStatus {
+		fs.StringVar(&event.MessageID, "message-id", "", "ID of the outbound message (required)")
+		fs.StringVar(&event.Status, "status", "delivered", "status: "+strings.Join(simulator.Statuses, ", "))
+		fs.StringVar(&event.RecipientID, "recipient", "", "phone number the message was sent to")
+		fs.IntVar(&event.ErrorCode, "error-code", 0, "error code of a failed status, e.g. 131047")
+		fs.StringVar(&event.ErrorTitle, "error-title", "", "error title, defaults to WhatsApp's title for the code")
+		fs.StringVar(&event.ErrorMessage, "error-message", "", "error details")
+		fs.StringVar(&event.Category, "category", "", "conversation category: service, utility, marketing, authentication")
+	} else {
+		fs.StringVar(&event.From, "from", "", "phone number of the sender (required)")
+		fs.StringVar(&event.Name, "name", "", "profile name of the sender")
+		fs.StringVar(&event.ID, "id", "", "WhatsApp message ID, generated when empty")
+		fs.StringVar(&event.ReplyTo, "reply-to", "", "ID of the message being replied to")
+		fs.BoolVar(&event.Forwarded, "forwarded", false, "mark the message as forwarded")
+	}
+
+	switch eventType {
+	case simulator.EventText:
+		fs.StringVar(&event.Text, "text", "", "message body (required)")
+	case simulator.EventImage, simulator.EventVideo, simulator.EventAudio, simulator.EventDocument, simulator.EventSticker:
+		fs.StringVar(&event.MediaID, "media-id", "", "media ID, random when empty")
+		fs.StringVar(&event.MimeType, "mime-type", "", "MIME type, defaults per media type")
+		fs.StringVar(&event.Caption, "caption", "", "caption (image, video and document)")
+		fs.StringVar(&event.Filename, "filename", "", "file name (document)")
+		fs.BoolVar(&event.Voice, "voice", false, "voice note (audio)")
+	case simulator.EventLocation:
+		fs.Float64Var(&location.Latitude, "lat", 0, "latitude")
+		fs.Float64Var(&location.Longitude, "lng", 0, "longitude")
+		fs.StringVar(&location.Name, "location-name", "", "place name")
+		fs.StringVar(&location.Address, "address", "", "place address")
+		hasLocation = true
+	case simulator.EventContacts:
+		fs.StringVar(&contact.Name, "contact-name", "", "name of the shared contact (required)")
+		fs.StringVar(&contact.Phone, "contact-phone", "", "phone number of the shared contact (required)")
+	case simulator.EventReaction:
+		fs.StringVar(&event.MessageID, "message-id", "", "ID of the message reacted to (required)")
+		fs.StringVar(&event.Emoji, "emoji", "👍", "reaction emoji, empty to remove a reaction")
+	case simulator.EventButton, simulator.EventList, simulator.EventQuickReply:
+		fs.StringVar(&reply.ID, "reply-id", "", "button or row ID, or the quick reply payload (required)")
+		fs.StringVar(&reply.Title, "title", "", "title of the selected option (required)")
+		fs.StringVar(&reply.Description, "description", "", "row description (list_reply)")
+	case simulator.EventFlow:
+		fs.StringVar(&flow.Name, "flow-name", "", "flow name")
+		fs.StringVar(&flow.Body, "body", "", "reply body shown in the chat")
+		fs.StringVar(&flow.Token, "flow-token", "", "flow token of the sent flow message")
+		fs.StringVar(&response, "response", "{}", "submitted flow data as a JSON object")
+	case simulator.EventOrder:
+		fs.StringVar(&order.CatalogID, "catalog-id", "", "catalog ID (required)")
+		fs.StringVar(&order.Text, "order-text", "", "message sent with the order")
+		fs.Var(&items, "item", "line item as retailer_id:quantity:price:currency, repeatable (required)")
+	}
+
+	fs.Parse(args)
+
+	switch {
+	case hasLocation:
+		event.Location = &location
+	case eventType == simulator.EventContacts:
+		event.Contact = &contact
+	case eventType == simulator.EventButton, eventType == simulator.EventList, eventType == simulator.EventQuickReply:
+		event.Reply = &reply
+	case eventType == simulator.EventFlow:
+		if err := json.Unmarshal([]byte(response), &flow.Response); err != nil {
+			return nil, fmt.Errorf("invalid -response: %w", err)
+		}
+		event.Flow = &flow
+	case eventType == simulator.EventOrder:
+		order.Items = items
+		event.Order = &order
+	}
+
+	return event, nil
+}
+
+// itemsFlag collects repeated -item flags
+type itemsFlag []simulator.OrderItem
+
+func (f *itemsFlag) String() string {
+	return fmt.Sprintf("%d items", len(*f))
+}
+
+func (f *itemsFlag) Set(value string) error {
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 {
+		return fmt.Errorf("expected retailer_id:quantity:price:currency")
+	}
+	quantity, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return fmt.Errorf("invalid quantity: %w", err)
+	}
+	price, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return fmt.Errorf("invalid price: %w", err)
+	}
+	*f = append(*f, simulator.OrderItem{
+		ProductRetailerID: parts[0],
+		Quantity:          quantity,
+		ItemPrice:         price,
+		Currency:          parts[3],
+	})
+	return nil
+}
+
+func isEventType(eventType string) bool {
+	for _, t := range simulator.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultWebhookURL(cfg *config.Config) string {
+	if cfg.Server.BaseURL != "" {
+		return strings.TrimSuffix(cfg.Server.BaseURL, "/") + "/webhooks/whatsapp"
+	}
+	port := cfg.Server.Port
+	if port == 0 {
+		port = 8080
+	}
+	return fmt.Sprintf("http://localhost:%d/webhooks/whatsapp", port)
+}
+
+func defaultSender(cfg *config.Config) config.SenderConfig {
+	senders := cfg.WhatsApp.SenderConfigs()
+	for _, sender := range senders {
+		if sender.Name == cfg.WhatsApp.DefaultSender {
+			return sender
+		}
+	}
+	return senders[0]
+}
+
+func printWebhook(webhook *simulator.Webhook) {
+	if webhook.Signature != "" {
+		fmt.Printf("%s: %s\n", simulator.SignatureHeader, webhook.Signature)
+	}
+	fmt.Println(string(webhook.Body))
+}
+
+func usage(fs *flag.FlagSet) {
+	fmt.Fprintf(os.Stderr, `Usage:
+  simulate [flags] <event type> [event flags]   post one webhook
+  simulate [flags] replay <script.yaml>         replay a scripted conversation
+  simulate types                                list event types
+
+Event types: %s
+Run 'simulate <event type> -h' for the flags of an event type.
+
+Flags:
+`, strings.Join(simulator.EventTypes, ", "))
+	fs.PrintDefaults()
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -1,7 +1,8 @@
-import { type HTMLAttributes } from 'react';
+import { type HTMLAttributes, useEffect, useState } from 'react';
 import { Check, CheckCheck } from 'lucide-react';
 import { cn } from '../lib/utils';
 import { formatTimestamp } from '../lib/utils';
+import { apiClient } from '../services/api';
 import type { Message } from '../types';
 
 export interface MessageBubbleProps extends HTMLAttributes<HTMLDivElement> {
@@ -10,6 +11,34 @@ export interface MessageBubbleProps extends HTMLAttributes<HTMLDivElement> {
 
 export default function MessageBubble({ message, className, ...props }: MessageBubbleProps) {
   const isOutbound = message.direction === 'outbound';
+  const [mediaSrc, setMediaSrc] = useState<string | undefined>(message.media_url);
+
+  // Stored media is served by the API and needs the API key, so load it as a blob
+  useEffect(() => {
+    if (!message.media_id) {
+      setMediaSrc(message.media_url);
+      return;
+    }
+
+    let objectURL: string | undefined;
+    let cancelled = false;
+
+    apiClient
+      .getMediaBlob(message.media_id)
+      .then((blob) => {
+        if (cancelled) return;
+        objectURL = URL.createObjectURL(blob);
+        setMediaSrc(objectURL);
+      })
+      .catch(() => {
+        if (!cancelled) setMediaSrc(message.media_url);
+      });
+
+    return () => {
+      cancelled = true;
+      if (objectURL) URL.revokeObjectURL(objectURL);
+    };
+  }, [message.media_id, message.media_url]);
 
   const getStatusIcon = () => {
     if (message.direction === 'inbound') return null;
@@ -37,17 +66,17 @@ export default function MessageBubble({ message, className, ...props }: MessageB
             isOutbound ? 'bg-leaf-100 rounded-tr-sm' : 'bg-white rounded-tl-sm'
           )}
         >
-          {message.media_url && (
+          {mediaSrc && (
             <div className="mb-2">
               {message.message_type === 'image' ? (
                 <img
-                  src={message.media_url}
+                  src={mediaSrc}
                   alt="Media"
                   className="rounded-lg max-w-full h-auto"
                 />
               ) : (
                 <a
-                  href={message.media_url}
+                  href={mediaSrc}
                   target="_blank"
                   rel="noopener noreferrer"
                   className="text-strawberry-600 hover:underline text-sm"
@@ -1,4 +1,104 @@
+import { useState, useEffect, useRef } from 'react';
+import { Upload } from 'lucide-react';
+import Card from '../components/Card';
+import Input from '../components/Input';
+import Button from '../components/Button';
+import Avatar from '../components/Avatar';
+import LoadingSpinner from '../components/LoadingSpinner';
+import { useToast } from '../hooks/useToast';
+import { apiClient } from '../services/api';
+import type { BusinessProfile } from '../types';
+
+const VERTICALS = [
+  'UNDEFINED', 'OTHER', 'AUTO', 'BEAUTY', 'APPAREL', 'EDU', 'ENTERTAIN',
+  'EVENT_PLAN', 'FINANCE', 'GROCERY', 'GOVT', 'HOTEL', 'HEALTH', 'NONPROFIT',
+  'PROF_SERVICES', 'RETAIL', 'TRAVEL', 'RESTAURANT', 'NOT_A_BIZ',
+];
+
+const emptyProfile: BusinessProfile = {
+  about: '',
+  address: '',
+  description: '',
+  email: '',
+  websites: [],
+  vertical: '',
+};
+
 export default function SettingsPage() {
+  const [profile, setProfile] = useState<BusinessProfile>(emptyProfile);
+  const [isLoading, setIsLoading] = useState(true);
+  const [isSaving, setIsSaving] = useState(false);
+  const [isUploading, setIsUploading] = useState(false);
+  const fileInput = useRef<HTMLInputElement>(null);
+  const toast = useToast();
+
+  useEffect(() => {
+    loadProfile();
+  }, []);
+
+  const loadProfile = async () => {
+    try {
+      setIsLoading(true);
+      setProfile(await apiClient.getBusinessProfile());
+    } catch (error) {
+      toast.error('Failed to load business profile');
+      console.error('Error loading business profile:', error);
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  const setField = (field: keyof BusinessProfile, value: string) => {
+    setProfile((prev) => ({ ...prev, [field]: value }));
+  };
+
+  const setWebsite = (index: number, value: string) => {
+    setProfile((prev) => {
+      const websites = [...prev.websites];
+      websites[index] = value;
+      return { ...prev, websites };
+    });
+  };
+
+  const handleSave = async (e: React.FormEvent) => {
+    e.preventDefault();
+    try {
+      setIsSaving(true);
+      const updated = await apiClient.updateBusinessProfile({
+        about: profile.about,
+        address: profile.address,
+        description: profile.description,
+        email: profile.email,
+        websites: profile.websites.filter((w) => w.trim() !== ''),
+        vertical: profile.vertical,
+      });
+      setProfile(updated);
+      toast.success('Business profile saved');
+    } catch (error) {
+      toast.error('Failed to save business profile');
+      console.error('Error saving business profile:', error);
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
+  const handlePhoto = async (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0];
+    e.target.value = '';
+    if (!file) return;
+
+    try {
+      setIsUploading(true);
+      setProfile(await apiClient.uploadProfilePicture(file));
+      toast.success('Profile picture updated');
+    } catch (error) {
+      toast.error('Failed to upload profile picture');
+      console.error('Error uploading profile picture:', error);
+    } finally {
+      setIsUploading(false);
+    }
+  };
+
   return (
     <div className="h-full flex flex-col">
       <div className="bg-white border-b px-6 py-4">
@@ -6,9 +106,108 @@ export default function SettingsPage() {
       </div>
       <div className="flex-1 overflow-y-auto p-6">
         <div className="max-w-2xl">
-          <div className="text-center text-neutral-500 py-12">
-            Settings page coming soon...
-          </div>
+          {isLoading ? (
+            <div className="flex justify-center items-center h-64">
+              <LoadingSpinner size="lg" />
+            </div>
+          ) : (
+            <Card>
+              <h2 className="text-lg font-semibold text-neutral-900 mb-4">Business Profile</h2>
+
+              <div className="flex items-center gap-4 mb-6">
+                <Avatar name="Business" src={profile.profile_picture_url} size="lg" />
+                <input
+                  ref={fileInput}
+                  type="file"
+                  accept="image/jpeg,image/png"
+                  className="hidden"
+                  onChange={handlePhoto}
+                />
+                <Button
+                  type="button"
+                  variant="secondary"
+                  size="sm"
+                  isLoading={isUploading}
+                  onClick={() => fileInput.current?.click()}
+                >
+                  <Upload className="w-4 h-4 mr-2" />
+                  Change photo
+                </Button>
+              </div>
+
+              <form onSubmit={handleSave} className="space-y-4">
+                <div>
+                  <label className="block text-sm font-medium text-neutral-700 mb-1">About</label>
+                  <Input
+                    maxLength={139}
+                    value={profile.about}
+                    onChange={(e) => setField('about', e.target.value)}
+                  />
+                </div>
+                <div>
+                  <label className="block text-sm font-medium text-neutral-700 mb-1">Description</label>
+                  <textarea
+                    maxLength={512}
+                    rows={3}
+                    value={profile.description}
+                    onChange={(e) => setField('description', e.target.value)}
+                    className="px-3 py-2 w-full border border-neutral-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-strawberry-500"
+                  />
+                </div>
+                <div>
+                  <label className="block text-sm font-medium text-neutral-700 mb-1">Address</label>
+                  <Input
+                    maxLength={256}
+                    value={profile.address}
+                    onChange={(e) => setField('address', e.target.value)}
+                  />
+                </div>
+                <div>
+                  <label className="block text-sm font-medium text-neutral-700 mb-1">Email</label>
+                  <Input
+                    type="email"
+                    maxLength={128}
+                    value={profile.email}
+                    onChange={(e) => setField('email', e.target.value)}
+                  />
+                </div>
+                <div>
+                  <label className="block text-sm font-medium text-neutral-700 mb-1">Websites</label>
+                  <div className="space-y-2">
+                    {[0, 1].map((index) => (
+                      <Input
+                        key={index}
+                        type="url"
+                        placeholder="https://"
+                        maxLength={256}
+                        value={profile.websites[index] ?? ''}
+                        onChange={(e) => setWebsite(index, e.target.value)}
+                      />
+                    ))}
+                  </div>
+                </div>
+                <div>
+                  <label className="block text-sm font-medium text-neutral-700 mb-1">Industry</label>
+                  <select
+                    value={profile.vertical}
+                    onChange={(e) => setField('vertical', e.target.value)}
+                    className="px-3 py-2 w-full border border-neutral-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-strawberry-500"
+                  >
+                    {VERTICALS.map((vertical) => (
+                      <option key={vertical} value={vertical}>
+                        {vertical}
+                      </option>
+                    ))}
+                  </select>
+                </div>
+                <div className="flex justify-end">
+                  <Button type="submit" isLoading={isSaving}>
+                    Save
+                  </Button>
+                </div>
+              </form>
+            </Card>
+          )}
         </div>
       </div>
     </div>
@@ -3,6 +3,12 @@ import type {
   Message,
   Contact,
   Template,
+  BusinessProfile,
+  BusinessProfileUpdate,
+  Sender,
+  FlowResponse,
+  Order,
+  OrderStatus,
   SendMessageRequest,
   PaginationParams,
   PaginatedResponse,
@@ -62,6 +68,12 @@ class APIClient {
     return data;
   }
 
+  // Media
+  async getMediaBlob(id: string): Promise<Blob> {
+    const { data } = await this.client.get<Blob>(`/media/${id}`, { responseType: 'blob' });
+    return data;
+  }
+
   // Contacts
   async getContact(id: string): Promise<Contact> {
     const { data } = await this.client.get<Contact>(`/contacts/${id}`);
@@ -109,6 +121,52 @@ class APIClient {
   async deleteTemplate(id: string): Promise<void> {
     await this.client.delete(`/templates/${id}`);
   }
+
+  // Business profile
+  // Orders
+  async listOrders(
+    params?: PaginationParams & { status?: OrderStatus; contact_id?: string; phone?: string }
+  ): Promise<PaginatedResponse<Order>> {
+    const { data } = await this.client.get<PaginatedResponse<Order>>('/orders', { params });
+    return data;
+  }
+
+  async updateOrderStatus(id: string, status: OrderStatus, note?: string): Promise<Order> {
+    const { data } = await this.client.patch<{ data: Order }>(`/orders/${id}`, { status, note });
+    return data.data;
+  }
+
+  // Flows
+  async listFlowResponses(
+    params?: PaginationParams & { contact_id?: string; flow_id?: string; flow_token?: string }
+  ): Promise<PaginatedResponse<FlowResponse>> {
+    const { data } = await this.client.get<PaginatedResponse<FlowResponse>>('/flow-responses', { params });
+    return data;
+  }
+
+  async getSenders(): Promise<Sender[]> {
+    const { data } = await this.client.get<{ data: Sender[] }>('/senders');
+    return data.data;
+  }
+
+  async getBusinessProfile(): Promise<BusinessProfile> {
+    const { data } = await this.client.get<{ data: BusinessProfile }>('/business-profile');
+    return data.data;
+  }
+
+  async updateBusinessProfile(updates: BusinessProfileUpdate): Promise<BusinessProfile> {
+    const { data } = await this.client.patch<{ data: BusinessProfile }>('/business-profile', updates);
+    return data.data;
+  }
+
+  async uploadProfilePicture(file: File): Promise<BusinessProfile> {
+    const form = new FormData();
+    form.append('file', file);
+    const { data } = await this.client.put<{ data: BusinessProfile }>('/business-profile/photo', form, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+    return data.data;
+  }
 }
 
 export const apiClient = new APIClient();
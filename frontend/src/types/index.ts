@@ -3,10 +3,12 @@ export interface Message {
   whatsapp_message_id: string;
   from_number: string;
   to_number: string;
+  phone_number_id?: string;
   direction: 'inbound' | 'outbound';
   message_type: 'text' | 'image' | 'document' | 'audio' | 'video' | 'template';
   content: string;
   media_url?: string;
+  media_id?: string;
   media_mime_type?: string;
   status: 'sent' | 'delivered' | 'read' | 'failed' | 'received';
   timestamp: string;
@@ -31,7 +33,20 @@ export interface Template {
   language: string;
   category: string;
   content: string;
-  status: 'approved' | 'pending' | 'rejected';
+  status:
+    | 'approved'
+    | 'pending'
+    | 'rejected'
+    | 'paused'
+    | 'disabled'
+    | 'in_appeal'
+    | 'pending_deletion'
+    | 'deleted'
+    | 'archived'
+    | 'limit_exceeded';
+  whatsapp_template_id?: string;
+  rejection_reason?: string;
+  last_synced_at?: string;
   created_at: string;
   updated_at: string;
 }
@@ -44,6 +59,76 @@ export interface SendMessageRequest {
   template_name?: string;
   template_language?: string;
   template_params?: string[];
+  from?: string;
+}
+
+export interface Sender {
+  name: string;
+  phone_number_id: string;
+  phone_number: string;
+  default: boolean;
+}
+
+export interface BusinessProfile {
+  about: string;
+  address: string;
+  description: string;
+  email: string;
+  profile_picture_url?: string;
+  websites: string[];
+  vertical: string;
+}
+
+export type BusinessProfileUpdate = Partial<Omit<BusinessProfile, 'profile_picture_url'>>;
+
+export interface FlowResponse {
+  id: string;
+  message_id: string;
+  flow_message_id?: string;
+  contact_id: string;
+  phone_number: string;
+  phone_number_id?: string;
+  flow_id?: string;
+  flow_name?: string;
+  flow_token?: string;
+  name: string;
+  body: string;
+  response: Record<string, unknown>;
+  received_at: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export type OrderStatus = 'pending' | 'confirmed' | 'processing' | 'shipped' | 'delivered' | 'cancelled';
+
+export interface OrderItem {
+  id: string;
+  order_id: string;
+  product_retailer_id: string;
+  quantity: number;
+  item_price: number;
+  currency: string;
+}
+
+export interface Order {
+  id: string;
+  message_id: string;
+  reply_to_id?: string;
+  contact_id: string;
+  phone_number: string;
+  phone_number_id?: string;
+  catalog_id: string;
+  text?: string;
+  status: OrderStatus;
+  currency: string;
+  total: number;
+  item_count: number;
+  note?: string;
+  items?: OrderItem[];
+  placed_at: string;
+  status_updated_at?: string;
+  created_at: string;
+  updated_at: string;
 }
 
 export interface PaginationParams {
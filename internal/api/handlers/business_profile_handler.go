@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxProfilePictureBodySize caps profile picture uploads at the WhatsApp limit plus form overhead
+const maxProfilePictureBodySize = whatsapp.MaxProfilePictureSize + 1<<20
+
+// BusinessProfileHandler handles business profile requests
+type BusinessProfileHandler struct {
+	profileService *services.BusinessProfileService
+}
+
+// NewBusinessProfileHandler creates a new business profile handler
+func NewBusinessProfileHandler(profileService *services.BusinessProfileService) *BusinessProfileHandler {
+	return &BusinessProfileHandler{
+		profileService: profileService,
+	}
+}
+
+// UpdateBusinessProfileRequest represents a partial business profile update.
+// Omitted fields are left unchanged; empty values clear the field.
+type UpdateBusinessProfileRequest struct {
+	About       *string  `json:"about"`
+	Address     *string  `json:"address"`
+	Description *string  `json:"description"`
+	Email       *string  `json:"email"`
+	Websites    []string `json:"websites"`
+	Vertical    *string  `json:"vertical"`
+}
+
+// GetBusinessProfile handles GET /api/v1/business-profile
+func (h *BusinessProfileHandler) GetBusinessProfile(c *gin.Context) {
+	profile, err := h.profileService.GetProfile(c.Request.Context())
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, http.StatusOK, profile)
+}
+
+// UpdateBusinessProfile handles PATCH /api/v1/business-profile
+func (h *BusinessProfileHandler) UpdateBusinessProfile(c *gin.Context) {
+	var req UpdateBusinessProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorJSON(c, errors.NewBadRequest("Invalid request body: "+err.Error()))
+		return
+	}
+
+	profile, err := h.profileService.UpdateProfile(c.Request.Context(), &whatsapp.BusinessProfileUpdate{
+		About:       req.About,
+		Address:     req.Address,
+		Description: req.Description,
+		Email:       req.Email,
+		Websites:    req.Websites,
+		Vertical:    req.Vertical,
+	})
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, http.StatusOK, profile)
+}
+
+// UploadProfilePicture handles PUT /api/v1/business-profile/photo
+func (h *BusinessProfileHandler) UploadProfilePicture(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxProfilePictureBodySize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorJSON(c, errors.NewBadRequest("Multipart field 'file' is required: "+err.Error()))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorJSON(c, errors.NewBadRequest("Failed to open uploaded file"))
+		return
+	}
+	defer file.Close()
+
+	profile, err := h.profileService.UpdateProfilePicture(c.Request.Context(), fileHeader.Filename, file)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, http.StatusOK, profile)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/internal/storage"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CallHandler handles call-related requests
+type CallHandler struct {
+	recordingService *services.RecordingService
+}
+
+// NewCallHandler creates a new call handler
+func NewCallHandler(recordingService *services.RecordingService) *CallHandler {
+	return &CallHandler{
+		recordingService: recordingService,
+	}
+}
+
+// GetRecording handles GET /api/v1/calls/:id/recording and streams the recording.
+// With ?redirect=true it redirects to a presigned URL when the storage backend supports it.
+func (h *CallHandler) GetRecording(c *gin.Context) {
+	callID := c.Param("id")
+
+	if c.Query("redirect") == "true" {
+		url, err := h.recordingService.PresignRecordingURL(c.Request.Context(), callID, presignedURLExpiry)
+		if err == nil {
+			c.Redirect(http.StatusFound, url)
+			return
+		}
+		if err != storage.ErrPresignNotSupported {
+			if appErr, ok := err.(*errors.AppError); ok {
+				utils.ErrorJSON(c, appErr)
+			} else {
+				utils.ErrorJSON(c, errors.NewInternalError(err))
+			}
+			return
+		}
+	}
+
+	reader, info, err := h.recordingService.OpenRecording(c.Request.Context(), callID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+	defer reader.Close()
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.DataFromReader(http.StatusOK, info.Size, contentType, reader, map[string]string{
+		"Cache-Control": "private, max-age=86400",
+	})
+}
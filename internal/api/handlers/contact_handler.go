@@ -12,12 +12,14 @@ import (
 // ContactHandler handles contact-related requests
 type ContactHandler struct {
 	contactService *services.ContactService
+	messageService *services.MessageService
 }
 
 // NewContactHandler creates a new contact handler
-func NewContactHandler(contactService *services.ContactService) *ContactHandler {
+func NewContactHandler(contactService *services.ContactService, messageService *services.MessageService) *ContactHandler {
 	return &ContactHandler{
 		contactService: contactService,
+		messageService: messageService,
 	}
 }
 
@@ -25,7 +27,7 @@ func NewContactHandler(contactService *services.ContactService) *ContactHandler
 func (h *ContactHandler) GetContact(c *gin.Context) {
 	contactID := c.Param("id")
 
-	contact, err := h.contactService.GetContact(contactID)
+	contact, err := h.contactService.GetContact(c.Request.Context(), contactID)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			utils.ErrorJSON(c, appErr)
@@ -51,10 +53,17 @@ func (h *ContactHandler) ListContacts(c *gin.Context) {
 	if order := c.Query("order"); order != "" {
 		filters["order"] = order
 	}
+	if from := c.Query("from"); from != "" {
+		filters["from"] = from
+	}
 
-	contacts, err := h.contactService.ListContacts(filters, pagination)
+	contacts, err := h.contactService.ListContacts(c.Request.Context(), filters, pagination)
 	if err != nil {
-		utils.ErrorJSON(c, errors.NewInternalError(err))
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
 		return
 	}
 
@@ -71,7 +80,32 @@ func (h *ContactHandler) UpdateContact(c *gin.Context) {
 		return
 	}
 
-	contact, err := h.contactService.UpdateContact(contactID, updates)
+	contact, err := h.contactService.UpdateContact(c.Request.Context(), contactID, updates)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, contact)
+}
+
+// MarkRead handles POST /api/v1/contacts/:id/read
+func (h *ContactHandler) MarkRead(c *gin.Context) {
+	contactID := c.Param("id")
+
+	var req MarkReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorJSON(c, errors.NewBadRequest("Invalid request body: "+err.Error()))
+			return
+		}
+	}
+
+	contact, err := h.messageService.MarkConversationRead(c.Request.Context(), contactID, c.GetString("api_key_id"), req.Typing)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			utils.ErrorJSON(c, appErr)
@@ -96,9 +130,18 @@ func (h *ContactHandler) SearchContacts(c *gin.Context) {
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	pagination := utils.NewPagination(limit, offset)
 
-	contacts, err := h.contactService.SearchContacts(query, pagination)
+	filters := make(map[string]interface{})
+	if from := c.Query("from"); from != "" {
+		filters["from"] = from
+	}
+
+	contacts, err := h.contactService.SearchContacts(c.Request.Context(), query, filters, pagination)
 	if err != nil {
-		utils.ErrorJSON(c, errors.NewInternalError(err))
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
 		return
 	}
 
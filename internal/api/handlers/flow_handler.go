@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// responseFieldPrefix marks query parameters that filter on submitted flow fields,
+// e.g. ?response.email=jane@example.com
+const responseFieldPrefix = "response."
+
+// FlowHandler handles WhatsApp Flow requests
+type FlowHandler struct {
+	flowService *services.FlowService
+}
+
+// NewFlowHandler creates a new flow handler
+func NewFlowHandler(flowService *services.FlowService) *FlowHandler {
+	return &FlowHandler{
+		flowService: flowService,
+	}
+}
+
+// ListFlowResponses handles GET /api/v1/flow-responses
+func (h *FlowHandler) ListFlowResponses(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	pagination := utils.NewPagination(limit, offset)
+
+	filters := make(map[string]interface{})
+	for _, key := range []string{"contact_id", "flow_id", "flow_name", "flow_token", "flow_message_id", "from"} {
+		if value := c.Query(key); value != "" {
+			filters[key] = value
+		}
+	}
+	if phone := c.Query("phone"); phone != "" {
+		filters["phone_number"] = phone
+	}
+
+	fields := make(map[string]string)
+	for key, values := range c.Request.URL.Query() {
+		if field := strings.TrimPrefix(key, responseFieldPrefix); field != key && field != "" {
+			fields[field] = values[0]
+		}
+	}
+	if len(fields) > 0 {
+		filters["response"] = fields
+	}
+
+	responses, err := h.flowService.ListFlowResponses(c.Request.Context(), filters, pagination)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.ListJSON(c, responses, pagination)
+}
+
+// GetFlowResponse handles GET /api/v1/flow-responses/:id
+func (h *FlowHandler) GetFlowResponse(c *gin.Context) {
+	response, err := h.flowService.GetFlowResponse(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, response)
+}
@@ -25,7 +25,7 @@ func NewHealthHandler(db *gorm.DB) *HealthHandler {
 // HealthCheck handles GET /health
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	// Check database health
-	dbHealth := database.HealthCheck(h.db)
+	dbHealth := database.HealthCheckWithContext(c.Request.Context(), h.db, 5*time.Second)
 
 	uptime := time.Since(h.startTime)
 
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/internal/storage"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxUploadBodySize caps multipart uploads at the largest WhatsApp media size plus form overhead
+	maxUploadBodySize = whatsapp.MaxDocumentSize + 1<<20
+
+	// presignedURLExpiry is how long redirect URLs for stored objects stay valid
+	presignedURLExpiry = 15 * time.Minute
+)
+
+// MediaHandler handles media-related requests
+type MediaHandler struct {
+	mediaService *services.MediaService
+}
+
+// NewMediaHandler creates a new media handler
+func NewMediaHandler(mediaService *services.MediaService) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+	}
+}
+
+// UploadMedia handles POST /api/v1/media
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadBodySize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorJSON(c, errors.NewBadRequest("Multipart field 'file' is required: "+err.Error()))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorJSON(c, errors.NewBadRequest("Failed to open uploaded file"))
+		return
+	}
+	defer file.Close()
+
+	media, err := h.mediaService.UploadMedia(
+		c.Request.Context(),
+		c.PostForm("from"),
+		fileHeader.Filename,
+		fileHeader.Header.Get("Content-Type"),
+		file,
+		c.GetString("api_key_id"),
+	)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.CreatedJSON(c, media)
+}
+
+// GetMedia handles GET /api/v1/media/:id and streams the stored content.
+// With ?redirect=true it redirects to a presigned URL when the storage backend supports it.
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	mediaID := c.Param("id")
+
+	if c.Query("redirect") == "true" {
+		url, err := h.mediaService.PresignMediaURL(c.Request.Context(), mediaID, presignedURLExpiry)
+		if err == nil {
+			c.Redirect(http.StatusFound, url)
+			return
+		}
+		if err != storage.ErrPresignNotSupported {
+			if appErr, ok := err.(*errors.AppError); ok {
+				utils.ErrorJSON(c, appErr)
+			} else {
+				utils.ErrorJSON(c, errors.NewInternalError(err))
+			}
+			return
+		}
+	}
+
+	media, reader, info, err := h.mediaService.OpenMediaContent(c.Request.Context(), mediaID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+	defer reader.Close()
+
+	headers := map[string]string{
+		"Cache-Control": "private, max-age=86400",
+	}
+	if media.Filename != "" {
+		headers["Content-Disposition"] = mime.FormatMediaType("inline", map[string]string{"filename": media.Filename})
+	}
+
+	c.DataFromReader(http.StatusOK, info.Size, media.MimeType, reader, headers)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
 	"github.com/ashok/vibecoded-wa-client/pkg/errors"
 	"github.com/ashok/vibecoded-wa-client/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -28,11 +29,56 @@ type SendMessageRequest struct {
 	Type             string   `json:"type" binding:"required"`
 	Content          string   `json:"content"`
 	MediaURL         string   `json:"media_url"`
+	MediaID          string   `json:"media_id"`
 	Caption          string   `json:"caption"`
 	Filename         string   `json:"filename"`
 	TemplateName     string   `json:"template_name"`
 	TemplateLanguage string   `json:"template_language"`
 	Parameters       []string `json:"parameters"`
+
+	// Components carries typed template parameters for headers, body and buttons
+	Components []whatsapp.TemplateComponent `json:"components"`
+
+	Interactive *whatsapp.Interactive  `json:"interactive"`
+	Location    *LocationRequest       `json:"location"`
+	Contacts    []whatsapp.ContactCard `json:"contacts"`
+	Reaction    *ReactionRequest       `json:"reaction"`
+
+	// ReplyTo is the internal ID of the message being quoted
+	ReplyTo string `json:"reply_to"`
+
+	// From selects the sender by name or phone number ID
+	From string `json:"from"`
+}
+
+// LocationRequest represents a location to send. The coordinates are pointers so
+// a missing field can be told apart from a real zero.
+type LocationRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"required"`
+	Longitude *float64 `json:"longitude" binding:"required"`
+	Name      string   `json:"name"`
+	Address   string   `json:"address"`
+	URL       string   `json:"url"`
+}
+
+// toLocation converts the request into a WhatsApp location
+func (r *LocationRequest) toLocation() *whatsapp.Location {
+	if r == nil {
+		return nil
+	}
+	return &whatsapp.Location{
+		Latitude:  *r.Latitude,
+		Longitude: *r.Longitude,
+		Name:      r.Name,
+		Address:   r.Address,
+		URL:       r.URL,
+	}
+}
+
+// ReactionRequest represents an emoji reaction to a stored message
+type ReactionRequest struct {
+	MessageID string `json:"message_id"`
+	Emoji     string `json:"emoji"`
 }
 
 // SendMessage handles POST /api/v1/messages
@@ -46,15 +92,44 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 	var message interface{}
 	var err error
 
+	opts := services.SendOptions{ReplyTo: req.ReplyTo, From: req.From}
+
 	switch req.Type {
 	case "text":
-		message, err = h.messageService.SendTextMessage(req.Phone, req.Content)
+		message, err = h.messageService.SendTextMessage(c.Request.Context(), req.Phone, req.Content, opts)
 
 	case "image", "video", "audio", "document":
-		message, err = h.messageService.SendMediaMessage(req.Phone, req.MediaURL, req.Caption, req.Type)
+		message, err = h.messageService.SendMediaMessage(c.Request.Context(), req.Phone, services.MediaMessageInput{
+			Type:     req.Type,
+			URL:      req.MediaURL,
+			MediaID:  req.MediaID,
+			Caption:  req.Caption,
+			Filename: req.Filename,
+		}, opts)
 
 	case "template":
-		message, err = h.messageService.SendTemplateMessage(req.Phone, req.TemplateName, req.TemplateLanguage, req.Parameters)
+		message, err = h.messageService.SendTemplateMessage(c.Request.Context(), req.Phone, services.TemplateMessageInput{
+			Name:       req.TemplateName,
+			Language:   req.TemplateLanguage,
+			Parameters: req.Parameters,
+			Components: req.Components,
+		}, opts)
+
+	case "interactive":
+		message, err = h.messageService.SendInteractiveMessage(c.Request.Context(), req.Phone, req.Interactive, opts)
+
+	case "location":
+		message, err = h.messageService.SendLocationMessage(c.Request.Context(), req.Phone, req.Location.toLocation(), opts)
+
+	case "contacts":
+		message, err = h.messageService.SendContactsMessage(c.Request.Context(), req.Phone, req.Contacts, opts)
+
+	case "reaction":
+		if req.Reaction == nil {
+			utils.ErrorJSON(c, errors.NewBadRequest("reaction is required for reaction messages"))
+			return
+		}
+		message, err = h.messageService.SendReaction(c.Request.Context(), req.Phone, req.Reaction.MessageID, req.Reaction.Emoji, opts)
 
 	default:
 		utils.ErrorJSON(c, errors.NewBadRequest("Invalid message type: "+req.Type))
@@ -77,7 +152,37 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 func (h *MessageHandler) GetMessage(c *gin.Context) {
 	messageID := c.Param("id")
 
-	message, err := h.messageService.GetMessage(messageID)
+	message, err := h.messageService.GetMessage(c.Request.Context(), messageID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, message)
+}
+
+// MarkReadRequest represents the optional request body for read receipts
+type MarkReadRequest struct {
+	Typing bool `json:"typing"`
+}
+
+// MarkRead handles POST /api/v1/messages/:id/read
+func (h *MessageHandler) MarkRead(c *gin.Context) {
+	messageID := c.Param("id")
+
+	var req MarkReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorJSON(c, errors.NewBadRequest("Invalid request body: "+err.Error()))
+			return
+		}
+	}
+
+	message, err := h.messageService.MarkMessageRead(c.Request.Context(), messageID, c.GetString("api_key_id"), req.Typing)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			utils.ErrorJSON(c, appErr)
@@ -90,6 +195,40 @@ func (h *MessageHandler) GetMessage(c *gin.Context) {
 	utils.SuccessJSON(c, 200, message)
 }
 
+// ListReplies handles GET /api/v1/messages/:id/replies
+func (h *MessageHandler) ListReplies(c *gin.Context) {
+	messageID := c.Param("id")
+
+	replies, err := h.messageService.ListReplies(c.Request.Context(), messageID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, replies)
+}
+
+// GetStatusHistory handles GET /api/v1/messages/:id/status-history
+func (h *MessageHandler) GetStatusHistory(c *gin.Context) {
+	messageID := c.Param("id")
+
+	history, err := h.messageService.GetStatusHistory(c.Request.Context(), messageID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, history)
+}
+
 // ListMessages handles GET /api/v1/messages
 func (h *MessageHandler) ListMessages(c *gin.Context) {
 	// Parse query parameters
@@ -104,6 +243,9 @@ func (h *MessageHandler) ListMessages(c *gin.Context) {
 	if phone := c.Query("phone"); phone != "" {
 		filters["phone"] = phone
 	}
+	if from := c.Query("from"); from != "" {
+		filters["from"] = from
+	}
 	if direction := c.Query("direction"); direction != "" {
 		filters["direction"] = direction
 	}
@@ -124,9 +266,13 @@ func (h *MessageHandler) ListMessages(c *gin.Context) {
 		}
 	}
 
-	messages, err := h.messageService.ListMessages(filters, pagination)
+	messages, err := h.messageService.ListMessages(c.Request.Context(), filters, pagination)
 	if err != nil {
-		utils.ErrorJSON(c, errors.NewInternalError(err))
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
 		return
 	}
 
@@ -149,10 +295,17 @@ func (h *MessageHandler) SearchMessages(c *gin.Context) {
 	if phone := c.Query("phone"); phone != "" {
 		filters["phone"] = phone
 	}
+	if from := c.Query("from"); from != "" {
+		filters["from"] = from
+	}
 
-	messages, err := h.messageService.SearchMessages(query, filters, pagination)
+	messages, err := h.messageService.SearchMessages(c.Request.Context(), query, filters, pagination)
 	if err != nil {
-		utils.ErrorJSON(c, errors.NewInternalError(err))
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
 		return
 	}
 
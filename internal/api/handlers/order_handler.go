@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// OrderHandler handles order requests
+type OrderHandler struct {
+	orderService *services.OrderService
+}
+
+// NewOrderHandler creates a new order handler
+func NewOrderHandler(orderService *services.OrderService) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+	}
+}
+
+// UpdateOrderStatusRequest represents the request body for updating an order
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
+
+// ListOrders handles GET /api/v1/orders
+func (h *OrderHandler) ListOrders(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	pagination := utils.NewPagination(limit, offset)
+
+	filters := make(map[string]interface{})
+	for _, key := range []string{"status", "contact_id", "catalog_id", "from"} {
+		if value := c.Query(key); value != "" {
+			filters[key] = value
+		}
+	}
+	if phone := c.Query("phone"); phone != "" {
+		filters["phone_number"] = phone
+	}
+
+	orders, err := h.orderService.ListOrders(c.Request.Context(), filters, pagination)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.ListJSON(c, orders, pagination)
+}
+
+// GetOrder handles GET /api/v1/orders/:id
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, order)
+}
+
+// UpdateOrderStatus handles PATCH /api/v1/orders/:id
+func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
+	var req UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorJSON(c, errors.NewBadRequest("Invalid request body: "+err.Error()))
+		return
+	}
+
+	order, err := h.orderService.UpdateOrderStatus(c.Request.Context(), c.Param("id"), req.Status, req.Note)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, order)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// PhoneNumberHandler handles phone number requests
+type PhoneNumberHandler struct {
+	phoneNumberService *services.PhoneNumberService
+}
+
+// NewPhoneNumberHandler creates a new phone number handler
+func NewPhoneNumberHandler(phoneNumberService *services.PhoneNumberService) *PhoneNumberHandler {
+	return &PhoneNumberHandler{
+		phoneNumberService: phoneNumberService,
+	}
+}
+
+// ListPhoneNumbers handles GET /api/v1/phone-numbers
+func (h *PhoneNumberHandler) ListPhoneNumbers(c *gin.Context) {
+	phoneNumbers, err := h.phoneNumberService.ListPhoneNumbers(c.Request.Context())
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, phoneNumbers)
+}
+
+// GetPhoneNumber handles GET /api/v1/phone-numbers/:id
+func (h *PhoneNumberHandler) GetPhoneNumber(c *gin.Context) {
+	phoneNumber, err := h.phoneNumberService.GetPhoneNumber(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, phoneNumber)
+}
+
+// ListHistory handles GET /api/v1/phone-numbers/:id/history
+func (h *PhoneNumberHandler) ListHistory(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	pagination := utils.NewPagination(limit, offset)
+
+	snapshots, err := h.phoneNumberService.ListHistory(c.Request.Context(), c.Param("id"), pagination)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.ListJSON(c, snapshots, pagination)
+}
+
+// SyncPhoneNumbers handles POST /api/v1/phone-numbers/sync
+func (h *PhoneNumberHandler) SyncPhoneNumbers(c *gin.Context) {
+	result, err := h.phoneNumberService.SyncPhoneNumbers(c.Request.Context())
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, result)
+}
+
+// ListSenders handles GET /api/v1/senders
+func (h *PhoneNumberHandler) ListSenders(c *gin.Context) {
+	utils.SuccessJSON(c, 200, h.phoneNumberService.ListSenders(c.Request.Context()))
+}
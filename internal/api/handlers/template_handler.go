@@ -30,7 +30,7 @@ func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
 		return
 	}
 
-	if err := h.templateService.CreateTemplate(&template); err != nil {
+	if err := h.templateService.CreateTemplate(c.Request.Context(), &template); err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			utils.ErrorJSON(c, appErr)
 		} else {
@@ -46,7 +46,7 @@ func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
 func (h *TemplateHandler) GetTemplate(c *gin.Context) {
 	templateID := c.Param("id")
 
-	template, err := h.templateService.GetTemplate(templateID)
+	template, err := h.templateService.GetTemplate(c.Request.Context(), templateID)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			utils.ErrorJSON(c, appErr)
@@ -65,7 +65,7 @@ func (h *TemplateHandler) ListTemplates(c *gin.Context) {
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	pagination := utils.NewPagination(limit, offset)
 
-	templates, err := h.templateService.ListTemplates(pagination)
+	templates, err := h.templateService.ListTemplates(c.Request.Context(), pagination)
 	if err != nil {
 		utils.ErrorJSON(c, errors.NewInternalError(err))
 		return
@@ -84,7 +84,7 @@ func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
 		return
 	}
 
-	template, err := h.templateService.UpdateTemplate(templateID, updates)
+	template, err := h.templateService.UpdateTemplate(c.Request.Context(), templateID, updates)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			utils.ErrorJSON(c, appErr)
@@ -101,7 +101,7 @@ func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
 func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
 	templateID := c.Param("id")
 
-	if err := h.templateService.DeleteTemplate(templateID); err != nil {
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), templateID); err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			utils.ErrorJSON(c, appErr)
 		} else {
@@ -112,3 +112,18 @@ func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
 
 	utils.NoContentJSON(c)
 }
+
+// SyncTemplates handles POST /api/v1/templates/sync
+func (h *TemplateHandler) SyncTemplates(c *gin.Context) {
+	result, err := h.templateService.SyncTemplates(c.Request.Context())
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, result)
+}
@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"crypto/subtle"
+	stderrors "errors"
 	"io"
+	"net/http"
+	"strconv"
 
+	"github.com/ashok/vibecoded-wa-client/internal/models"
 	"github.com/ashok/vibecoded-wa-client/internal/services"
 	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
 	"github.com/ashok/vibecoded-wa-client/pkg/errors"
@@ -13,23 +18,30 @@ import (
 
 // WebhookHandler handles webhook-related requests
 type WebhookHandler struct {
-	messageService *services.MessageService
+	webhookService *services.WebhookService
 	verifyToken    string
-	webhookSecret  string
+	secrets        []string // app secrets accepted for signatures, the current one first
+	allowUnsigned  bool
+	maxBodyBytes   int64
 	logger         *zap.Logger
 }
 
-// NewWebhookHandler creates a new webhook handler
+// NewWebhookHandler creates a new webhook handler. When secrets are given every
+// delivery must carry a signature made with one of them, unless allowUnsigned is set.
 func NewWebhookHandler(
-	messageService *services.MessageService,
+	webhookService *services.WebhookService,
 	verifyToken string,
-	webhookSecret string,
+	secrets []string,
+	allowUnsigned bool,
+	maxBodyBytes int64,
 	logger *zap.Logger,
 ) *WebhookHandler {
 	return &WebhookHandler{
-		messageService: messageService,
+		webhookService: webhookService,
 		verifyToken:    verifyToken,
-		webhookSecret:  webhookSecret,
+		secrets:        secrets,
+		allowUnsigned:  allowUnsigned,
+		maxBodyBytes:   maxBodyBytes,
 		logger:         logger,
 	}
 }
@@ -40,75 +52,184 @@ func (h *WebhookHandler) VerifyWebhook(c *gin.Context) {
 	token := c.Query("hub.verify_token")
 	challenge := c.Query("hub.challenge")
 
-	if mode == "subscribe" && token == h.verifyToken {
+	if mode == "subscribe" && h.verifyToken != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(h.verifyToken)) == 1 {
 		h.logger.Info("Webhook verified successfully")
 		c.String(200, challenge)
 		return
 	}
 
-	h.logger.Warn("Webhook verification failed",
-		zap.String("mode", mode),
-		zap.String("token", token),
-	)
+	h.reject(c, models.WebhookRejectionVerificationFailed, 0)
 	utils.ErrorJSON(c, errors.NewUnauthorized("Webhook verification failed"))
 }
 
-// ReceiveWebhook handles POST /webhooks/whatsapp for receiving events
+// ReceiveWebhook handles POST /webhooks/whatsapp for receiving events. The payload
+// is stored and acknowledged straight away; messages and statuses in it are
+// processed in the background. If it cannot be stored the request fails so that
+// WhatsApp delivers it again. Oversized, unsigned and wrongly signed requests are
+// refused and recorded in the security log.
 func (h *WebhookHandler) ReceiveWebhook(c *gin.Context) {
-	// Read body
-	body, err := io.ReadAll(c.Request.Body)
+	// Read body, up to the limit
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBodyBytes))
 	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if stderrors.As(err, &tooLarge) {
+			h.reject(c, models.WebhookRejectionBodyTooLarge, c.Request.ContentLength)
+			utils.ErrorJSON(c, errors.NewPayloadTooLarge(h.maxBodyBytes))
+			return
+		}
 		h.logger.Error("Failed to read webhook body", zap.Error(err))
 		utils.ErrorJSON(c, errors.NewBadRequest("Failed to read request body"))
 		return
 	}
 
 	// Verify signature
-	signature := c.GetHeader("X-Hub-Signature-256")
-	if signature != "" && !whatsapp.VerifySignature(body, signature, h.webhookSecret) {
-		h.logger.Warn("Webhook signature verification failed")
-		utils.ErrorJSON(c, errors.NewUnauthorized("Invalid signature"))
+	if len(h.secrets) > 0 {
+		signature := c.GetHeader("X-Hub-Signature-256")
+		switch {
+		case signature == "" && !h.allowUnsigned:
+			h.reject(c, models.WebhookRejectionMissingSignature, int64(len(body)))
+			utils.ErrorJSON(c, errors.NewUnauthorized("Missing signature"))
+			return
+		case signature != "" && !whatsapp.VerifySignatureAny(body, signature, h.secrets):
+			h.reject(c, models.WebhookRejectionInvalidSignature, int64(len(body)))
+			utils.ErrorJSON(c, errors.NewUnauthorized("Invalid signature"))
+			return
+		}
+	}
+
+	// Store the payload for processing
+	event, err := h.webhookService.Receive(c.Request.Context(), body)
+	if err != nil {
+		h.logger.Error("Failed to store webhook", zap.Error(err))
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	c.JSON(200, gin.H{"status": "received", "event_id": event.ID})
+}
+
+// reject records a refused webhook request in the security log
+func (h *WebhookHandler) reject(c *gin.Context, reason string, bodySize int64) {
+	h.webhookService.RecordRejection(c.Request.Context(), &models.WebhookRejection{
+		Reason:    reason,
+		SourceIP:  c.ClientIP(),
+		RemoteIP:  c.RemoteIP(),
+		UserAgent: c.Request.UserAgent(),
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		BodySize:  bodySize,
+	})
+}
+
+// ListEvents handles GET /api/v1/webhook-events
+func (h *WebhookHandler) ListEvents(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	pagination := utils.NewPagination(limit, offset)
+
+	filters := make(map[string]interface{})
+	for _, key := range []string{"status", "object"} {
+		if value := c.Query(key); value != "" {
+			filters[key] = value
+		}
+	}
+
+	events, err := h.webhookService.ListEvents(c.Request.Context(), filters, pagination)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.ListJSON(c, events, pagination)
+}
+
+// GetEvent handles GET /api/v1/webhook-events/:id
+func (h *WebhookHandler) GetEvent(c *gin.Context) {
+	event, err := h.webhookService.GetEvent(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
 		return
 	}
 
-	// Parse webhook payload
-	payload, err := whatsapp.ParseWebhook(body)
+	utils.SuccessJSON(c, 200, event)
+}
+
+// ReprocessEvent handles POST /api/v1/webhook-events/:id/reprocess
+func (h *WebhookHandler) ReprocessEvent(c *gin.Context) {
+	event, err := h.webhookService.ReprocessEvent(c.Request.Context(), c.Param("id"))
 	if err != nil {
-		h.logger.Error("Failed to parse webhook", zap.Error(err))
-		utils.ErrorJSON(c, errors.NewBadRequest("Invalid webhook payload"))
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
+		}
+		return
+	}
+
+	utils.SuccessJSON(c, 200, event)
+}
+
+// ReprocessEventsRequest represents the request body for reprocessing events in bulk
+type ReprocessEventsRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// ReprocessEvents handles POST /api/v1/webhook-events/reprocess
+func (h *WebhookHandler) ReprocessEvents(c *gin.Context) {
+	var req ReprocessEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorJSON(c, errors.NewBadRequest("Invalid request body: "+err.Error()))
 		return
 	}
 
-	// Process message events
-	messageEvents, err := whatsapp.ParseMessageEvent(payload)
+	count, err := h.webhookService.ReprocessEvents(c.Request.Context(), req.Status)
 	if err != nil {
-		h.logger.Error("Failed to parse message events", zap.Error(err))
-	} else {
-		for _, event := range messageEvents {
-			if err := h.messageService.ProcessIncomingMessage(event); err != nil {
-				h.logger.Error("Failed to process incoming message",
-					zap.Error(err),
-					zap.String("message_id", event.MessageID),
-				)
-			}
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
 		}
+		return
 	}
 
-	// Process status events
-	statusEvents, err := whatsapp.ParseStatusEvent(payload)
+	utils.SuccessJSON(c, 200, gin.H{"requeued": count})
+}
+
+// ListRejections handles GET /api/v1/webhook-rejections
+func (h *WebhookHandler) ListRejections(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	pagination := utils.NewPagination(limit, offset)
+
+	filters := make(map[string]interface{})
+	for _, key := range []string{"reason", "source_ip"} {
+		if value := c.Query(key); value != "" {
+			filters[key] = value
+		}
+	}
+
+	rejections, err := h.webhookService.ListRejections(c.Request.Context(), filters, pagination)
 	if err != nil {
-		h.logger.Error("Failed to parse status events", zap.Error(err))
-	} else {
-		for _, event := range statusEvents {
-			if err := h.messageService.UpdateMessageStatus(event.MessageID, event.Status); err != nil {
-				h.logger.Error("Failed to update message status",
-					zap.Error(err),
-					zap.String("message_id", event.MessageID),
-				)
-			}
+		if appErr, ok := err.(*errors.AppError); ok {
+			utils.ErrorJSON(c, appErr)
+		} else {
+			utils.ErrorJSON(c, errors.NewInternalError(err))
 		}
+		return
 	}
 
-	// Return success
-	c.JSON(200, gin.H{"status": "received"})
+	utils.ListJSON(c, rejections, pagination)
 }
@@ -0,0 +1,195 @@
+package handlers_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/api/handlers"
+	"github.com/ashok/vibecoded-wa-client/internal/database"
+	"github.com/ashok/vibecoded-wa-client/internal/events"
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	testSecret         = "current-secret"
+	testPreviousSecret = "previous-secret"
+	testMaxBodyBytes   = 1024
+	testPayload        = `{"object":"whatsapp_business_account","entry":[]}`
+)
+
+// webhookTestEnv serves the webhook endpoint backed by an in-memory database
+type webhookTestEnv struct {
+	router *gin.Engine
+	db     *gorm.DB
+}
+
+func newWebhookTestEnv(t *testing.T, allowUnsigned bool) *webhookTestEnv {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := database.AutoMigrate(db); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	log := zap.NewNop()
+	webhookService := services.NewWebhookService(
+		repositories.NewWebhookEventRepository(db),
+		repositories.NewWebhookRejectionRepository(db),
+		nil, nil, nil,
+		events.NewBus(log),
+		log,
+	)
+	handler := handlers.NewWebhookHandler(
+		webhookService,
+		"verify-token",
+		[]string{testSecret, testPreviousSecret},
+		allowUnsigned,
+		testMaxBodyBytes,
+		log,
+	)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/webhooks/whatsapp", handler.ReceiveWebhook)
+
+	return &webhookTestEnv{router: router, db: db}
+}
+
+// post sends a webhook body with the given signature header, if any
+func (e *webhookTestEnv) post(body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/whatsapp", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	return w
+}
+
+func (e *webhookTestEnv) rejections(t *testing.T) []models.WebhookRejection {
+	t.Helper()
+	var rejections []models.WebhookRejection
+	if err := e.db.Order("created_at ASC").Find(&rejections).Error; err != nil {
+		t.Fatalf("Failed to list rejections: %v", err)
+	}
+	return rejections
+}
+
+func (e *webhookTestEnv) storedEvents(t *testing.T) int64 {
+	t.Helper()
+	var count int64
+	if err := e.db.Model(&models.WebhookEvent{}).Count(&count).Error; err != nil {
+		t.Fatalf("Failed to count webhook events: %v", err)
+	}
+	return count
+}
+
+func sign(body, secret string) string {
+	return utils.ComputeHMAC([]byte(body), []byte(secret))
+}
+
+func TestReceiveWebhookSignatures(t *testing.T) {
+	tests := []struct {
+		name          string
+		allowUnsigned bool
+		signature     string
+		status        int
+		reason        string // expected rejection, if any
+	}{
+		{name: "current secret", signature: sign(testPayload, testSecret), status: http.StatusOK},
+		{name: "previous secret", signature: sign(testPayload, testPreviousSecret), status: http.StatusOK},
+		{name: "missing signature", status: http.StatusUnauthorized, reason: models.WebhookRejectionMissingSignature},
+		{name: "wrong signature", signature: sign(testPayload, "other-secret"), status: http.StatusUnauthorized, reason: models.WebhookRejectionInvalidSignature},
+		{name: "unsigned allowed", allowUnsigned: true, status: http.StatusOK},
+		{name: "wrong signature with unsigned allowed", allowUnsigned: true, signature: sign(testPayload, "other-secret"), status: http.StatusUnauthorized, reason: models.WebhookRejectionInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newWebhookTestEnv(t, tt.allowUnsigned)
+
+			w := env.post(testPayload, tt.signature)
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+
+			rejections := env.rejections(t)
+			stored := env.storedEvents(t)
+			if tt.reason == "" {
+				if len(rejections) != 0 || stored != 1 {
+					t.Errorf("Expected the webhook to be stored without rejections, got %d events and %+v", stored, rejections)
+				}
+				return
+			}
+			if stored != 0 {
+				t.Errorf("Expected a rejected webhook not to be stored, got %d events", stored)
+			}
+			if len(rejections) != 1 || rejections[0].Reason != tt.reason {
+				t.Errorf("Expected a %s rejection, got %+v", tt.reason, rejections)
+			}
+		})
+	}
+}
+
+func TestReceiveWebhookBodyTooLarge(t *testing.T) {
+	env := newWebhookTestEnv(t, false)
+
+	body := `{"object":"` + strings.Repeat("x", testMaxBodyBytes) + `"}`
+	w := env.post(body, sign(body, testSecret))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status 413, got %d: %s", w.Code, w.Body.String())
+	}
+
+	rejections := env.rejections(t)
+	if len(rejections) != 1 || rejections[0].Reason != models.WebhookRejectionBodyTooLarge {
+		t.Errorf("Expected a body_too_large rejection, got %+v", rejections)
+	}
+	if stored := env.storedEvents(t); stored != 0 {
+		t.Errorf("Expected an oversized webhook not to be stored, got %d events", stored)
+	}
+}
+
+func TestReceiveWebhookRejectionsThrottled(t *testing.T) {
+	env := newWebhookTestEnv(t, false)
+
+	for i := 0; i < 5; i++ {
+		if w := env.post(testPayload, sign(testPayload, "other-secret")); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status 401, got %d", w.Code)
+		}
+	}
+	if w := env.post(testPayload, ""); w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status 401, got %d", w.Code)
+	}
+
+	// One entry per source and reason within the window
+	rejections := env.rejections(t)
+	if len(rejections) != 2 {
+		t.Fatalf("Expected 2 stored rejections, got %d", len(rejections))
+	}
+}
@@ -36,7 +36,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 		}
 
 		// Validate API key
-		keyInfo, err := authService.ValidateAPIKey(apiKey)
+		keyInfo, err := authService.ValidateAPIKey(c.Request.Context(), apiKey)
 		if err != nil {
 			utils.ErrorJSON(c, err.(*errors.AppError))
 			c.Abort()
@@ -14,6 +14,12 @@ func SetupRoutes(
 	messageHandler *handlers.MessageHandler,
 	contactHandler *handlers.ContactHandler,
 	templateHandler *handlers.TemplateHandler,
+	mediaHandler *handlers.MediaHandler,
+	callHandler *handlers.CallHandler,
+	businessProfileHandler *handlers.BusinessProfileHandler,
+	phoneNumberHandler *handlers.PhoneNumberHandler,
+	flowHandler *handlers.FlowHandler,
+	orderHandler *handlers.OrderHandler,
 	webhookHandler *handlers.WebhookHandler,
 	healthHandler *handlers.HealthHandler,
 	authService *services.AuthService,
@@ -47,6 +53,9 @@ func SetupRoutes(
 			messages.GET("", messageHandler.ListMessages)
 			messages.GET("/search", messageHandler.SearchMessages)
 			messages.GET("/:id", messageHandler.GetMessage)
+			messages.GET("/:id/replies", messageHandler.ListReplies)
+			messages.GET("/:id/status-history", messageHandler.GetStatusHistory)
+			messages.POST("/:id/read", messageHandler.MarkRead)
 		}
 
 		// Contacts
@@ -56,6 +65,7 @@ func SetupRoutes(
 			contacts.GET("/search", contactHandler.SearchContacts)
 			contacts.GET("/:id", contactHandler.GetContact)
 			contacts.PATCH("/:id", contactHandler.UpdateContact)
+			contacts.POST("/:id/read", contactHandler.MarkRead)
 		}
 
 		// Templates
@@ -63,9 +73,70 @@ func SetupRoutes(
 		{
 			templates.GET("", templateHandler.ListTemplates)
 			templates.POST("", templateHandler.CreateTemplate)
+			templates.POST("/sync", templateHandler.SyncTemplates)
 			templates.GET("/:id", templateHandler.GetTemplate)
 			templates.PATCH("/:id", templateHandler.UpdateTemplate)
 			templates.DELETE("/:id", templateHandler.DeleteTemplate)
 		}
+
+		// Media
+		media := v1.Group("/media")
+		{
+			media.POST("", mediaHandler.UploadMedia)
+			media.GET("/:id", mediaHandler.GetMedia)
+		}
+
+		// Calls
+		calls := v1.Group("/calls")
+		{
+			calls.GET("/:id/recording", callHandler.GetRecording)
+		}
+
+		// Business profile
+		businessProfile := v1.Group("/business-profile")
+		{
+			businessProfile.GET("", businessProfileHandler.GetBusinessProfile)
+			businessProfile.PATCH("", businessProfileHandler.UpdateBusinessProfile)
+			businessProfile.PUT("/photo", businessProfileHandler.UploadProfilePicture)
+		}
+
+		// Phone numbers
+		phoneNumbers := v1.Group("/phone-numbers")
+		{
+			phoneNumbers.GET("", phoneNumberHandler.ListPhoneNumbers)
+			phoneNumbers.POST("/sync", phoneNumberHandler.SyncPhoneNumbers)
+			phoneNumbers.GET("/:id", phoneNumberHandler.GetPhoneNumber)
+			phoneNumbers.GET("/:id/history", phoneNumberHandler.ListHistory)
+		}
+
+		// Sender phone numbers
+		v1.GET("/senders", phoneNumberHandler.ListSenders)
+
+		// Flow responses
+		flowResponses := v1.Group("/flow-responses")
+		{
+			flowResponses.GET("", flowHandler.ListFlowResponses)
+			flowResponses.GET("/:id", flowHandler.GetFlowResponse)
+		}
+
+		// Orders
+		orders := v1.Group("/orders")
+		{
+			orders.GET("", orderHandler.ListOrders)
+			orders.GET("/:id", orderHandler.GetOrder)
+			orders.PATCH("/:id", orderHandler.UpdateOrderStatus)
+		}
+
+		// Stored webhook deliveries
+		webhookEvents := v1.Group("/webhook-events")
+		{
+			webhookEvents.GET("", webhookHandler.ListEvents)
+			webhookEvents.POST("/reprocess", webhookHandler.ReprocessEvents)
+			webhookEvents.GET("/:id", webhookHandler.GetEvent)
+			webhookEvents.POST("/:id/reprocess", webhookHandler.ReprocessEvent)
+		}
+
+		// Security log of refused webhook requests
+		v1.GET("/webhook-rejections", webhookHandler.ListRejections)
 	}
 }
@@ -3,26 +3,39 @@ package api
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/ashok/vibecoded-wa-client/internal/api/handlers"
 	"github.com/ashok/vibecoded-wa-client/internal/api/routes"
 	"github.com/ashok/vibecoded-wa-client/internal/config"
+	"github.com/ashok/vibecoded-wa-client/internal/events"
+	"github.com/ashok/vibecoded-wa-client/internal/metrics"
 	"github.com/ashok/vibecoded-wa-client/internal/repositories"
 	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/internal/storage"
 	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+// senderResolveTimeout bounds looking up sender phone numbers at startup
+const senderResolveTimeout = 30 * time.Second
+
 // Server represents the API server
 type Server struct {
-	router     *gin.Engine
-	httpServer *http.Server
-	config     *config.Config
-	logger     *zap.Logger
+	router             *gin.Engine
+	httpServer         *http.Server
+	metricsServer      *http.Server
+	baseCancel         context.CancelFunc
+	mediaService       *services.MediaService
+	webhookService     *services.WebhookService
+	templateService    *services.TemplateService
+	phoneNumberService *services.PhoneNumberService
+	config             *config.Config
+	logger             *zap.Logger
 }
 
 // NewServer creates a new API server
@@ -32,41 +45,104 @@ func NewServer(cfg *config.Config, db *gorm.DB, logger *zap.Logger) (*Server, er
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Create Gin router
+	// Create Gin router. Client IPs are only taken from X-Forwarded-For when the
+	// request comes through a configured proxy.
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid SERVER_TRUSTED_PROXIES: %w", err)
+	}
 
-	// Initialize WhatsApp client
-	waClient, err := whatsapp.NewClient(whatsapp.Config{
-		APIToken:      cfg.WhatsApp.APIToken,
-		PhoneNumberID: cfg.WhatsApp.PhoneNumberID,
-		APIBaseURL:    cfg.WhatsApp.APIBaseURL,
-		APIVersion:    cfg.WhatsApp.APIVersion,
-		Logger:        logger,
-	})
+	// Initialize a WhatsApp client for every sender phone number
+	var senders []*whatsapp.Sender
+	for _, sender := range cfg.WhatsApp.SenderConfigs() {
+		client, err := whatsapp.NewClient(whatsapp.Config{
+			APIToken:          sender.AccessToken,
+			PhoneNumberID:     sender.PhoneNumberID,
+			BusinessAccountID: sender.BusinessAccountID,
+			AppID:             cfg.WhatsApp.AppID,
+			APIBaseURL:        cfg.WhatsApp.APIBaseURL,
+			APIVersion:        cfg.WhatsApp.APIVersion,
+			RequestTimeout:    cfg.WhatsApp.RequestTimeout,
+			MediaTimeout:      cfg.WhatsApp.MediaTimeout,
+			Logger:            logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create WhatsApp client for sender %s: %w", sender.Name, err)
+		}
+		senders = append(senders, whatsapp.NewSender(sender.Name, sender.PhoneNumber, client))
+	}
+	registry, err := whatsapp.NewRegistry(senders, cfg.WhatsApp.DefaultSender)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create WhatsApp client: %w", err)
+		return nil, fmt.Errorf("invalid WhatsApp sender configuration: %w", err)
 	}
+	resolveCtx, cancel := context.WithTimeout(context.Background(), senderResolveTimeout)
+	registry.ResolvePhoneNumbers(resolveCtx)
+	cancel()
+
+	// Account-level features (templates, phone numbers, business profile) use the default sender
+	waClient := registry.Default().Client
 
 	// Initialize repositories
 	messageRepo := repositories.NewMessageRepository(db)
 	contactRepo := repositories.NewContactRepository(db)
 	templateRepo := repositories.NewTemplateRepository(db)
 	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	mediaRepo := repositories.NewMediaRepository(db)
+	callRepo := repositories.NewCallRepository(db)
+	phoneNumberRepo := repositories.NewPhoneNumberRepository(db)
+	flowResponseRepo := repositories.NewFlowResponseRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+	webhookEventRepo := repositories.NewWebhookEventRepository(db)
+	statusEventRepo := repositories.NewMessageStatusEventRepository(db)
+	webhookRejectionRepo := repositories.NewWebhookRejectionRepository(db)
+
+	// Initialize the event bus shared by services that publish notifications
+	eventBus := events.NewBus(logger)
+
+	// Initialize object storage
+	mediaStorage, err := storage.NewFromConfig(cfg.Storage, cfg.Storage.MediaPath, "media")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create media storage: %w", err)
+	}
+	recordingStorage, err := storage.NewFromConfig(cfg.Storage, cfg.Storage.RecordingsPath, "recordings")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording storage: %w", err)
+	}
 
 	// Initialize services
-	messageService := services.NewMessageService(messageRepo, contactRepo, waClient, logger)
-	contactService := services.NewContactService(contactRepo)
-	templateService := services.NewTemplateService(templateRepo)
+	mediaService := services.NewMediaService(mediaRepo, registry, mediaStorage, logger)
+	flowService := services.NewFlowService(flowResponseRepo, messageRepo, registry, logger)
+	orderService := services.NewOrderService(orderRepo, registry, logger)
+	messageService := services.NewMessageService(messageRepo, statusEventRepo, contactRepo, templateRepo, mediaService, flowService, orderService, registry, logger)
+	contactService := services.NewContactService(contactRepo, registry)
+	templateService := services.NewTemplateService(templateRepo, waClient, eventBus, cfg.WhatsApp.TemplateSyncInterval, logger)
 	authService := services.NewAuthService(apiKeyRepo)
+	recordingService := services.NewRecordingService(callRepo, recordingStorage, logger)
+	businessProfileService := services.NewBusinessProfileService(waClient, logger)
+	phoneNumberService := services.NewPhoneNumberService(phoneNumberRepo, waClient, registry, eventBus, cfg.WhatsApp.PhoneNumberSyncInterval, logger)
+	webhookService := services.NewWebhookService(webhookEventRepo, webhookRejectionRepo, messageService, templateService, phoneNumberService, eventBus, logger)
 
 	// Initialize handlers
 	messageHandler := handlers.NewMessageHandler(messageService)
-	contactHandler := handlers.NewContactHandler(contactService)
+	contactHandler := handlers.NewContactHandler(contactService, messageService)
 	templateHandler := handlers.NewTemplateHandler(templateService)
+	mediaHandler := handlers.NewMediaHandler(mediaService)
+	callHandler := handlers.NewCallHandler(recordingService)
+	businessProfileHandler := handlers.NewBusinessProfileHandler(businessProfileService)
+	phoneNumberHandler := handlers.NewPhoneNumberHandler(phoneNumberService)
+	flowHandler := handlers.NewFlowHandler(flowService)
+	orderHandler := handlers.NewOrderHandler(orderService)
+	if len(cfg.WhatsApp.WebhookSecrets()) == 0 {
+		logger.Warn("WHATSAPP_WEBHOOK_SECRET is not set, webhook signatures are not verified")
+	} else if cfg.WhatsApp.WebhookAllowUnsigned {
+		logger.Warn("WHATSAPP_WEBHOOK_ALLOW_UNSIGNED is set, unsigned webhooks are accepted")
+	}
 	webhookHandler := handlers.NewWebhookHandler(
-		messageService,
+		webhookService,
 		cfg.WhatsApp.WebhookVerifyToken,
-		cfg.WhatsApp.WebhookSecret,
+		cfg.WhatsApp.WebhookSecrets(),
+		cfg.WhatsApp.WebhookAllowUnsigned,
+		cfg.WhatsApp.WebhookMaxBodyBytes,
 		logger,
 	)
 	healthHandler := handlers.NewHealthHandler(db)
@@ -77,26 +153,55 @@ func NewServer(cfg *config.Config, db *gorm.DB, logger *zap.Logger) (*Server, er
 		messageHandler,
 		contactHandler,
 		templateHandler,
+		mediaHandler,
+		callHandler,
+		businessProfileHandler,
+		phoneNumberHandler,
+		flowHandler,
+		orderHandler,
 		webhookHandler,
 		healthHandler,
 		authService,
 		logger,
 	)
 
-	// Create HTTP server
+	// Create HTTP server. Every request context derives from baseCtx so that
+	// in-flight requests can be cancelled if graceful shutdown times out.
+	baseCtx, baseCancel := context.WithCancel(context.Background())
 	httpServer := &http.Server{
 		Addr:           fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
 		Handler:        router,
 		ReadTimeout:    30 * time.Second,
 		WriteTimeout:   30 * time.Second,
 		MaxHeaderBytes: 1 << 20, // 1 MB
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+
+	// Metrics are served on their own port so they are not exposed with the API
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{
+			Addr:        fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Metrics.Port),
+			Handler:     metricsMux,
+			ReadTimeout: 10 * time.Second,
+		}
 	}
 
 	return &Server{
-		router:     router,
-		httpServer: httpServer,
-		config:     cfg,
-		logger:     logger,
+		router:             router,
+		httpServer:         httpServer,
+		metricsServer:      metricsServer,
+		baseCancel:         baseCancel,
+		mediaService:       mediaService,
+		webhookService:     webhookService,
+		templateService:    templateService,
+		phoneNumberService: phoneNumberService,
+		config:             cfg,
+		logger:             logger,
 	}, nil
 }
 
@@ -107,6 +212,21 @@ func (s *Server) Start() error {
 		zap.String("environment", s.config.Server.Environment),
 	)
 
+	// Start background jobs
+	s.mediaService.StartWorkers()
+	s.webhookService.StartWorkers()
+	s.templateService.StartSync()
+	s.phoneNumberService.StartSync()
+
+	if s.metricsServer != nil {
+		s.logger.Info("Starting metrics server", zap.String("address", s.metricsServer.Addr))
+		go func() {
+			if err := s.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				s.logger.Error("Metrics server failed", zap.Error(err))
+			}
+		}()
+	}
+
 	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("failed to start server: %w", err)
 	}
@@ -118,7 +238,25 @@ func (s *Server) Start() error {
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down HTTP server...")
 
-	if err := s.httpServer.Shutdown(ctx); err != nil {
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		// Requests did not drain in time: cancel their contexts so pending
+		// database queries and WhatsApp calls return promptly
+		s.logger.Warn("Graceful shutdown timed out, cancelling in-flight requests", zap.Error(err))
+	}
+	s.baseCancel()
+
+	// Stop background jobs after the last request has been handled
+	s.webhookService.StopWorkers()
+	s.mediaService.StopWorkers()
+	s.templateService.StopSync()
+	s.phoneNumberService.StopSync()
+
+	if s.metricsServer != nil {
+		s.metricsServer.Close()
+	}
+
+	if err != nil {
 		return fmt.Errorf("server shutdown failed: %w", err)
 	}
 
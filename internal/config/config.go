@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -26,6 +27,7 @@ type ServerConfig struct {
 	Environment     string // development, staging, production
 	BaseURL         string
 	ShutdownTimeout time.Duration
+	TrustedProxies  []string // proxy addresses or CIDRs whose X-Forwarded-For is believed
 }
 
 // DatabaseConfig holds database configuration
@@ -45,13 +47,72 @@ type DatabaseConfig struct {
 
 // WhatsAppConfig holds WhatsApp API configuration
 type WhatsAppConfig struct {
-	APIToken            string
-	PhoneNumberID       string
-	BusinessAccountID   string
-	WebhookVerifyToken  string
-	WebhookSecret       string
-	APIBaseURL          string
-	APIVersion          string
+	APIToken                string
+	PhoneNumberID           string
+	PhoneNumber             string // display number of PhoneNumberID, resolved from Graph at startup if empty
+	BusinessAccountID       string
+	AppID                   string // Meta app ID, used for resumable uploads
+	WebhookVerifyToken      string
+	WebhookSecret           string   // current app secret used to verify webhook signatures
+	WebhookPreviousSecrets  []string // app secrets still accepted while rotating to WebhookSecret
+	WebhookAllowUnsigned    bool     // accept unsigned webhooks even when a secret is configured
+	WebhookMaxBodyBytes     int64    // largest webhook body accepted
+	APIBaseURL              string
+	APIVersion              string
+	TemplateSyncInterval    time.Duration // how often templates are reconciled with WhatsApp
+	PhoneNumberSyncInterval time.Duration // how often phone number quality and limits are refreshed
+	RequestTimeout          time.Duration // deadline for a Graph API call, including retries
+	MediaTimeout            time.Duration // deadline for media uploads and downloads
+	Senders                 []SenderConfig
+	DefaultSender           string // name of the sender used when a request does not pick one
+}
+
+// SenderConfig holds the credentials of one sender phone number. Empty token and
+// business account fields fall back to the top-level WhatsApp configuration.
+type SenderConfig struct {
+	Name              string `json:"name"`
+	PhoneNumberID     string `json:"phone_number_id"`
+	PhoneNumber       string `json:"phone_number"`
+	AccessToken       string `json:"access_token"`
+	BusinessAccountID string `json:"business_account_id"`
+}
+
+// SenderConfigs returns the configured sender phone numbers. Without
+// WHATSAPP_SENDERS a single "default" sender is built from the top-level settings.
+func (c WhatsAppConfig) SenderConfigs() []SenderConfig {
+	if len(c.Senders) == 0 {
+		return []SenderConfig{{
+			Name:              "default",
+			PhoneNumberID:     c.PhoneNumberID,
+			PhoneNumber:       c.PhoneNumber,
+			AccessToken:       c.APIToken,
+			BusinessAccountID: c.BusinessAccountID,
+		}}
+	}
+
+	senders := make([]SenderConfig, len(c.Senders))
+	for i, sender := range c.Senders {
+		if sender.AccessToken == "" {
+			sender.AccessToken = c.APIToken
+		}
+		if sender.BusinessAccountID == "" {
+			sender.BusinessAccountID = c.BusinessAccountID
+		}
+		senders[i] = sender
+	}
+	return senders
+}
+
+// WebhookSecrets returns every app secret a webhook signature is checked against,
+// the current one first
+func (c WhatsAppConfig) WebhookSecrets() []string {
+	var secrets []string
+	for _, secret := range append([]string{c.WebhookSecret}, c.WebhookPreviousSecrets...) {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
 }
 
 // SecurityConfig holds security configuration
@@ -106,6 +167,7 @@ func LoadConfig() (*Config, error) {
 			Environment:     viper.GetString("ENV"),
 			BaseURL:         viper.GetString("SERVER_BASE_URL"),
 			ShutdownTimeout: viper.GetDuration("SERVER_SHUTDOWN_TIMEOUT"),
+			TrustedProxies:  splitList(viper.GetString("SERVER_TRUSTED_PROXIES")),
 		},
 		Database: DatabaseConfig{
 			Driver:          viper.GetString("DB_DRIVER"),
@@ -121,13 +183,23 @@ func LoadConfig() (*Config, error) {
 			ConnMaxLifetime: viper.GetDuration("DB_CONN_MAX_LIFETIME"),
 		},
 		WhatsApp: WhatsAppConfig{
-			APIToken:            viper.GetString("WHATSAPP_ACCESS_TOKEN"),
-			PhoneNumberID:       viper.GetString("WHATSAPP_PHONE_NUMBER_ID"),
-			BusinessAccountID:   viper.GetString("WHATSAPP_BUSINESS_ACCOUNT_ID"),
-			WebhookVerifyToken:  viper.GetString("WHATSAPP_WEBHOOK_VERIFY_TOKEN"),
-			WebhookSecret:       viper.GetString("WHATSAPP_WEBHOOK_SECRET"),
-			APIBaseURL:          viper.GetString("WHATSAPP_API_BASE_URL"),
-			APIVersion:          viper.GetString("WHATSAPP_API_VERSION"),
+			APIToken:                viper.GetString("WHATSAPP_ACCESS_TOKEN"),
+			PhoneNumberID:           viper.GetString("WHATSAPP_PHONE_NUMBER_ID"),
+			PhoneNumber:             viper.GetString("WHATSAPP_PHONE_NUMBER"),
+			BusinessAccountID:       viper.GetString("WHATSAPP_BUSINESS_ACCOUNT_ID"),
+			AppID:                   viper.GetString("WHATSAPP_APP_ID"),
+			WebhookVerifyToken:      viper.GetString("WHATSAPP_WEBHOOK_VERIFY_TOKEN"),
+			WebhookSecret:           viper.GetString("WHATSAPP_WEBHOOK_SECRET"),
+			WebhookPreviousSecrets:  splitList(viper.GetString("WHATSAPP_WEBHOOK_PREVIOUS_SECRETS")),
+			WebhookAllowUnsigned:    viper.GetBool("WHATSAPP_WEBHOOK_ALLOW_UNSIGNED"),
+			WebhookMaxBodyBytes:     viper.GetInt64("WHATSAPP_WEBHOOK_MAX_BODY_BYTES"),
+			APIBaseURL:              viper.GetString("WHATSAPP_API_BASE_URL"),
+			APIVersion:              viper.GetString("WHATSAPP_API_VERSION"),
+			TemplateSyncInterval:    viper.GetDuration("WHATSAPP_TEMPLATE_SYNC_INTERVAL"),
+			PhoneNumberSyncInterval: viper.GetDuration("WHATSAPP_PHONE_NUMBER_SYNC_INTERVAL"),
+			RequestTimeout:          viper.GetDuration("WHATSAPP_REQUEST_TIMEOUT"),
+			MediaTimeout:            viper.GetDuration("WHATSAPP_MEDIA_TIMEOUT"),
+			DefaultSender:           viper.GetString("WHATSAPP_DEFAULT_SENDER"),
 		},
 		Security: SecurityConfig{
 			APIKeySalt:    viper.GetString("API_KEY_SALT"),
@@ -154,6 +226,13 @@ func LoadConfig() (*Config, error) {
 		},
 	}
 
+	// Additional sender phone numbers are configured as a JSON array
+	if senders := viper.GetString("WHATSAPP_SENDERS"); senders != "" {
+		if err := json.Unmarshal([]byte(senders), &config.WhatsApp.Senders); err != nil {
+			return nil, fmt.Errorf("invalid WHATSAPP_SENDERS: %w", err)
+		}
+	}
+
 	// Set defaults
 	setDefaults(config)
 
@@ -208,6 +287,21 @@ func setDefaults(config *Config) {
 	if config.WhatsApp.APIVersion == "" {
 		config.WhatsApp.APIVersion = "v18.0"
 	}
+	if config.WhatsApp.TemplateSyncInterval == 0 {
+		config.WhatsApp.TemplateSyncInterval = 15 * time.Minute
+	}
+	if config.WhatsApp.PhoneNumberSyncInterval == 0 {
+		config.WhatsApp.PhoneNumberSyncInterval = time.Hour
+	}
+	if config.WhatsApp.RequestTimeout == 0 {
+		config.WhatsApp.RequestTimeout = 30 * time.Second
+	}
+	if config.WhatsApp.MediaTimeout == 0 {
+		config.WhatsApp.MediaTimeout = 2 * time.Minute
+	}
+	if config.WhatsApp.WebhookMaxBodyBytes == 0 {
+		config.WhatsApp.WebhookMaxBodyBytes = 1 << 20 // 1 MiB
+	}
 
 	if config.Logging.Level == "" {
 		config.Logging.Level = "info"
@@ -237,12 +331,20 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
 
-	if c.Server.Environment == "production" {
-		if c.WhatsApp.APIToken == "" {
-			return fmt.Errorf("WHATSAPP_ACCESS_TOKEN is required in production")
+	for _, sender := range c.WhatsApp.Senders {
+		if sender.Name == "" || sender.PhoneNumberID == "" {
+			return fmt.Errorf("every entry in WHATSAPP_SENDERS needs a name and phone_number_id")
 		}
-		if c.WhatsApp.PhoneNumberID == "" {
-			return fmt.Errorf("WHATSAPP_PHONE_NUMBER_ID is required in production")
+	}
+
+	if c.Server.Environment == "production" {
+		for _, sender := range c.WhatsApp.SenderConfigs() {
+			if sender.AccessToken == "" {
+				return fmt.Errorf("WHATSAPP_ACCESS_TOKEN is required in production")
+			}
+			if sender.PhoneNumberID == "" {
+				return fmt.Errorf("WHATSAPP_PHONE_NUMBER_ID is required in production")
+			}
 		}
 		if c.Database.Password == "" {
 			return fmt.Errorf("DB_PASSWORD is required in production")
@@ -252,6 +354,17 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// splitList splits a comma-separated setting, dropping blank entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // GetDatabaseDSN returns the database connection string
 func (c *Config) GetDatabaseDSN() string {
 	if c.Database.Driver == "sqlite" {
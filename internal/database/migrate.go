@@ -7,19 +7,86 @@ import (
 	"gorm.io/gorm"
 )
 
+// renamedColumns are columns whose name changed. Older versions let GORM derive
+// the WhatsApp ID columns as whats_app_*, while the queries use whatsapp_*.
+var renamedColumns = []struct {
+	table    string
+	from, to string
+	index    string // index over the old column, if any
+}{
+	{"messages", "whats_app_message_id", "whatsapp_message_id", "idx_messages_whats_app_message_id"},
+	{"message_status_events", "whats_app_message_id", "whatsapp_message_id", "idx_message_status_events_unique"},
+	{"media", "whats_app_media_id", "whatsapp_media_id", "idx_media_whats_app_media_id"},
+	{"templates", "whats_app_template_id", "whatsapp_template_id", "idx_templates_whats_app_template_id"},
+	{"phone_numbers", "whats_app_phone_number_id", "whatsapp_phone_number_id", "idx_phone_numbers_whats_app_phone_number_id"},
+}
+
 // AutoMigrate runs auto migrations for all models
 func AutoMigrate(db *gorm.DB) error {
+	if err := renameColumns(db); err != nil {
+		return err
+	}
+
 	return db.AutoMigrate(
 		&models.Message{},
 		&models.Contact{},
 		&models.Template{},
 		&models.APIKey{},
+		&models.Media{},
 		&models.Call{},
 		&models.Transcript{},
 		&models.TranscriptSegment{},
+		&models.PhoneNumber{},
+		&models.PhoneNumberSnapshot{},
+		&models.FlowResponse{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.WebhookEvent{},
+		&models.MessageStatusEvent{},
+		&models.WebhookRejection{},
 	)
 }
 
+// renameColumns moves renamed columns to their new name with their data. It runs
+// before the auto migration, which would otherwise add the new column empty and
+// build its indexes over rows that all lack an ID. If an earlier run already
+// added the new column, the IDs are copied into it and the old column is dropped.
+func renameColumns(db *gorm.DB) error {
+	migrator := db.Migrator()
+
+	for _, c := range renamedColumns {
+		if !migrator.HasTable(c.table) || !migrator.HasColumn(c.table, c.from) {
+			continue
+		}
+
+		// The index moves with the column; AutoMigrate recreates it under the new name
+		if c.index != "" && migrator.HasIndex(c.table, c.index) {
+			if err := migrator.DropIndex(c.table, c.index); err != nil {
+				return fmt.Errorf("failed to drop index %s: %w", c.index, err)
+			}
+		}
+
+		if !migrator.HasColumn(c.table, c.to) {
+			if err := migrator.RenameColumn(c.table, c.from, c.to); err != nil {
+				return fmt.Errorf("failed to rename %s.%s: %w", c.table, c.from, err)
+			}
+			continue
+		}
+
+		if err := db.Exec(fmt.Sprintf(
+			"UPDATE %s SET %s = %s WHERE %s IS NULL OR %s = ''",
+			c.table, c.to, c.from, c.to, c.to,
+		)).Error; err != nil {
+			return fmt.Errorf("failed to copy %s.%s: %w", c.table, c.from, err)
+		}
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", c.table, c.from)).Error; err != nil {
+			return fmt.Errorf("failed to drop %s.%s: %w", c.table, c.from, err)
+		}
+	}
+
+	return nil
+}
+
 // DropAllTables drops all tables (use with caution!)
 func DropAllTables(db *gorm.DB) error {
 	return db.Migrator().DropTable(
@@ -27,9 +94,18 @@ func DropAllTables(db *gorm.DB) error {
 		&models.Contact{},
 		&models.Template{},
 		&models.APIKey{},
+		&models.Media{},
 		&models.Call{},
 		&models.Transcript{},
 		&models.TranscriptSegment{},
+		&models.PhoneNumber{},
+		&models.PhoneNumberSnapshot{},
+		&models.FlowResponse{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.WebhookEvent{},
+		&models.MessageStatusEvent{},
+		&models.WebhookRejection{},
 	)
 }
 
@@ -66,6 +142,22 @@ func CreateIndexes(db *gorm.DB) error {
 		return fmt.Errorf("failed to create full-text search index: %w", err)
 	}
 
+	// Flow responses are queried by the fields users submitted
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_flow_responses_response
+		ON flow_responses USING gin(response);
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create flow responses index: %w", err)
+	}
+
+	// Webhook workers poll for events that are due for another attempt
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_webhook_events_status_next_attempt
+		ON webhook_events(status, next_attempt_at);
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create webhook events index: %w", err)
+	}
+
 	return nil
 }
 
@@ -85,7 +177,7 @@ func CreateTriggers(db *gorm.DB) error {
 	}
 
 	// Apply trigger to all tables
-	tables := []string{"messages", "contacts", "templates", "api_keys", "calls", "transcripts", "transcript_segments"}
+	tables := []string{"messages", "contacts", "templates", "api_keys", "media", "calls", "transcripts", "transcript_segments", "phone_numbers", "phone_number_snapshots", "flow_responses", "orders", "order_items", "webhook_events", "message_status_events"}
 	for _, table := range tables {
 		if err := db.Exec(fmt.Sprintf(`
 			DROP TRIGGER IF EXISTS update_%s_updated_at ON %s;
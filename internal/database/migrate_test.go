@@ -0,0 +1,92 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func exec(t *testing.T, db *gorm.DB, statements ...string) {
+	t.Helper()
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+}
+
+func TestRenameColumnsKeepsStoredIDs(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup []string
+	}{
+		{
+			name: "old column only",
+			setup: []string{
+				`CREATE TABLE messages (id TEXT PRIMARY KEY, whats_app_message_id VARCHAR(255))`,
+				`CREATE UNIQUE INDEX idx_messages_whats_app_message_id ON messages(whats_app_message_id)`,
+				`INSERT INTO messages (id, whats_app_message_id) VALUES ('msg_1', 'wamid.1'), ('msg_2', 'wamid.2')`,
+			},
+		},
+		{
+			name: "new column added empty by an earlier run",
+			setup: []string{
+				`CREATE TABLE messages (id TEXT PRIMARY KEY, whats_app_message_id VARCHAR(255), whatsapp_message_id VARCHAR(255))`,
+				`CREATE UNIQUE INDEX idx_messages_whats_app_message_id ON messages(whats_app_message_id)`,
+				`INSERT INTO messages (id, whats_app_message_id) VALUES ('msg_1', 'wamid.1'), ('msg_2', 'wamid.2')`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			exec(t, db, tt.setup...)
+
+			if err := renameColumns(db); err != nil {
+				t.Fatalf("renameColumns failed: %v", err)
+			}
+
+			migrator := db.Migrator()
+			if migrator.HasColumn("messages", "whats_app_message_id") {
+				t.Errorf("Expected the old column to be gone")
+			}
+			if migrator.HasIndex("messages", "idx_messages_whats_app_message_id") {
+				t.Errorf("Expected the old index to be dropped")
+			}
+
+			var id string
+			if err := db.Raw(`SELECT whatsapp_message_id FROM messages WHERE id = 'msg_2'`).Scan(&id).Error; err != nil {
+				t.Fatalf("Failed to read the renamed column: %v", err)
+			}
+			if id != "wamid.2" {
+				t.Errorf("Expected the stored ID to be kept, got %q", id)
+			}
+
+			// Running again on the migrated table is a no-op
+			if err := renameColumns(db); err != nil {
+				t.Errorf("Second renameColumns failed: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Type identifies the kind of an event
+type Type string
+
+// Event types
+const (
+	PhoneNumberQualityChanged Type = "phone_number.quality_changed"
+	PhoneNumberLimitChanged   Type = "phone_number.limit_changed"
+	PhoneNumberNameReviewed   Type = "phone_number.name_reviewed"
+	TemplateStatusChanged     Type = "template.status_changed"
+	TemplateQualityChanged    Type = "template.quality_changed"
+	AccountUpdated            Type = "account.updated"
+	AccountAlertRaised        Type = "account.alert_raised"
+	AccountCapabilityUpdated  Type = "account.capability_updated"
+)
+
+// Event is a notification about something that happened in the service
+type Event struct {
+	Type       Type        `json:"type"`
+	Payload    interface{} `json:"payload"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+// Handler reacts to a published event
+type Handler func(ctx context.Context, event Event)
+
+// Bus delivers events to in-process subscribers
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[Type][]Handler
+	logger   *zap.Logger
+}
+
+// NewBus creates a new event bus
+func NewBus(logger *zap.Logger) *Bus {
+	return &Bus{
+		handlers: make(map[Type][]Handler),
+		logger:   logger,
+	}
+}
+
+// Subscribe registers a handler for events of the given type
+func (b *Bus) Subscribe(eventType Type, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish delivers an event synchronously to every subscriber of its type.
+// A panicking handler is logged and does not affect the others.
+func (b *Bus) Publish(ctx context.Context, eventType Type, payload interface{}) {
+	b.mu.RLock()
+	handlers := b.handlers[eventType]
+	b.mu.RUnlock()
+
+	event := Event{
+		Type:       eventType,
+		Payload:    payload,
+		OccurredAt: time.Now().UTC(),
+	}
+
+	for _, handler := range handlers {
+		b.deliver(ctx, handler, event)
+	}
+}
+
+func (b *Bus) deliver(ctx context.Context, handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("Event handler panicked",
+				zap.String("event", string(event.Type)),
+				zap.Any("panic", r),
+			)
+		}
+	}()
+	handler(ctx, event)
+}
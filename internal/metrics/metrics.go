@@ -0,0 +1,145 @@
+// Package metrics keeps process-wide counters and serves them in the
+// Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Webhook processing counters
+var (
+	// WebhookMessages counts inbound messages by result: processed or duplicate
+	WebhookMessages = NewCounter("wa_webhook_messages_total",
+		"Inbound messages received in webhooks, by result.", "result")
+
+	// WebhookStatuses counts status updates by result: applied, duplicate or unknown_message
+	WebhookStatuses = NewCounter("wa_webhook_statuses_total",
+		"Message status updates received in webhooks, by result.", "result")
+
+	// WebhookEvents counts processing attempts of stored webhook deliveries by outcome:
+	// processed, failed or dead
+	WebhookEvents = NewCounter("wa_webhook_events_total",
+		"Processing attempts of stored webhook deliveries, by outcome.", "outcome")
+
+	// WebhookRejections counts refused webhook requests by reason: missing_signature,
+	// invalid_signature, body_too_large or verification_failed
+	WebhookRejections = NewCounter("wa_webhook_rejections_total",
+		"Webhook requests refused, by reason.", "reason")
+)
+
+var (
+	registryMu sync.Mutex
+	registry   []*Counter
+)
+
+// Counter is a monotonically increasing counter, optionally partitioned by labels
+type Counter struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.RWMutex
+	values map[string]*uint64 // keyed by the joined label values
+}
+
+// NewCounter creates a counter and registers it for exposition
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{
+		name:   name,
+		help:   help,
+		labels: labels,
+		values: make(map[string]*uint64),
+	}
+
+	registryMu.Lock()
+	registry = append(registry, c)
+	registryMu.Unlock()
+
+	return c
+}
+
+// Inc increments the counter for the given label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds delta to the counter for the given label values
+func (c *Counter) Add(delta uint64, labelValues ...string) {
+	atomic.AddUint64(c.value(labelValues), delta)
+}
+
+// Value returns the current count for the given label values
+func (c *Counter) Value(labelValues ...string) uint64 {
+	return atomic.LoadUint64(c.value(labelValues))
+}
+
+func (c *Counter) value(labelValues []string) *uint64 {
+	if len(labelValues) != len(c.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", c.name, len(c.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+
+	c.mu.RLock()
+	v, ok := c.values[key]
+	c.mu.RUnlock()
+	if ok {
+		return v
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok = c.values[key]; !ok {
+		v = new(uint64)
+		c.values[key] = v
+	}
+	return v
+}
+
+// write renders the counter in the text exposition format
+func (c *Counter) write(b *strings.Builder) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+
+	c.mu.RLock()
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	c.mu.RUnlock()
+	sort.Strings(keys)
+
+	if len(c.labels) == 0 {
+		fmt.Fprintf(b, "%s %d\n", c.name, c.Value())
+		return
+	}
+
+	for _, key := range keys {
+		values := strings.Split(key, "\xff")
+		pairs := make([]string, len(values))
+		for i, value := range values {
+			pairs[i] = fmt.Sprintf("%s=%q", c.labels[i], value)
+		}
+		fmt.Fprintf(b, "%s{%s} %d\n", c.name, strings.Join(pairs, ","), c.Value(values...))
+	}
+}
+
+// Handler serves every registered counter
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		registryMu.Lock()
+		counters := append([]*Counter(nil), registry...)
+		registryMu.Unlock()
+
+		var b strings.Builder
+		for _, c := range counters {
+			c.write(&b)
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write([]byte(b.String()))
+	})
+}
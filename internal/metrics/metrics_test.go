@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	counter := NewCounter("test_deliveries_total", "Test deliveries, by result.", "result")
+	counter.Inc("processed")
+	counter.Add(2, "duplicate")
+	counter.Inc("processed")
+
+	plain := NewCounter("test_plain_total", "Counter without labels.")
+	plain.Inc()
+
+	if got := counter.Value("processed"); got != 2 {
+		t.Errorf("Expected 2 processed, got %d", got)
+	}
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, want := range []string{
+		"# TYPE test_deliveries_total counter\n",
+		"test_deliveries_total{result=\"duplicate\"} 2\ntest_deliveries_total{result=\"processed\"} 2\n",
+		"test_plain_total 1\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %q", ct)
+	}
+}
+
+func TestLabelCountMismatch(t *testing.T) {
+	counter := NewCounter("test_mismatch_total", "Mismatched labels.", "result")
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for missing label values")
+		}
+	}()
+	counter.Inc()
+}
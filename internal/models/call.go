@@ -11,6 +11,7 @@ type Call struct {
 	Status         string    `json:"status" gorm:"index"`
 	Duration       int       `json:"duration"` // in seconds
 	RecordingURL   string    `json:"recording_url,omitempty"`
+	RecordingKey   string    `json:"-"` // object storage key of the recording
 	TranscriptID   string    `json:"transcript_id,omitempty"`
 	StartedAt      time.Time `json:"started_at" gorm:"index"`
 	EndedAt        *time.Time `json:"ended_at,omitempty"`
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FlowResponse represents the data a contact submitted by completing a WhatsApp Flow
+type FlowResponse struct {
+	ID            string    `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	MessageID     string    `json:"message_id" gorm:"uniqueIndex;type:varchar(100);not null"` // inbound nfm_reply message
+	FlowMessageID *string   `json:"flow_message_id,omitempty" gorm:"index;type:varchar(100)"` // outbound flow message that was answered
+	ContactID     string    `json:"contact_id" gorm:"index;type:varchar(100);not null"`
+	PhoneNumber   string    `json:"phone_number" gorm:"index;type:varchar(50);not null"`
+	PhoneNumberID string    `json:"phone_number_id,omitempty" gorm:"index;type:varchar(100)"`
+	FlowID        string    `json:"flow_id,omitempty" gorm:"index;type:varchar(100)"`
+	FlowName      string    `json:"flow_name,omitempty" gorm:"type:varchar(255)"`
+	FlowToken     string    `json:"flow_token,omitempty" gorm:"index;type:varchar(255)"`
+	Name          string    `json:"name" gorm:"type:varchar(100)"`
+	Body          string    `json:"body" gorm:"type:text"`
+	Response      JSONMap   `json:"response" gorm:"type:jsonb"`
+	ReceivedAt    time.Time `json:"received_at" gorm:"index;not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for FlowResponse
+func (FlowResponse) TableName() string {
+	return "flow_responses"
+}
+
+// BeforeCreate hook to generate ID and set timestamps
+func (f *FlowResponse) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == "" {
+		f.ID = GenerateID("flowresp")
+	}
+	if f.CreatedAt.IsZero() {
+		f.CreatedAt = time.Now().UTC()
+	}
+	if f.UpdatedAt.IsZero() {
+		f.UpdatedAt = time.Now().UTC()
+	}
+	if f.ReceivedAt.IsZero() {
+		f.ReceivedAt = f.CreatedAt
+	}
+	return f.Validate()
+}
+
+// BeforeUpdate hook
+func (f *FlowResponse) BeforeUpdate(tx *gorm.DB) error {
+	f.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Validate performs business logic validation
+func (f *FlowResponse) Validate() error {
+	if f.MessageID == "" {
+		return errors.New("message_id is required")
+	}
+	if f.ContactID == "" {
+		return errors.New("contact_id is required")
+	}
+	if f.PhoneNumber == "" {
+		return errors.New("phone_number is required")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MediaRetention is how long WhatsApp keeps uploaded media available
+const MediaRetention = 30 * 24 * time.Hour
+
+// MediaDirection constants
+const (
+	MediaDirectionInbound  = "inbound"
+	MediaDirectionOutbound = "outbound"
+)
+
+// MediaStatus constants
+const (
+	MediaStatusPending     = "pending"     // inbound media waiting to be downloaded
+	MediaStatusDownloading = "downloading" // claimed by a download worker
+	MediaStatusStored      = "stored"      // content available in local storage
+	MediaStatusUploaded    = "uploaded"    // sent to WhatsApp but not stored locally
+	MediaStatusFailed      = "failed"      // download gave up after retries
+)
+
+// Media represents a media file uploaded to or received from WhatsApp
+type Media struct {
+	ID              string     `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	WhatsAppMediaID string     `json:"whatsapp_media_id" gorm:"column:whatsapp_media_id;index;type:varchar(255)"`
+	MessageID       string     `json:"message_id,omitempty" gorm:"index;type:varchar(100)"`
+	PhoneNumberID   string     `json:"phone_number_id,omitempty" gorm:"index;type:varchar(100)"` // business number the media belongs to
+	Direction       string     `json:"direction" gorm:"type:varchar(20);not null;default:'outbound'"`
+	Status          string     `json:"status" gorm:"index;type:varchar(20);not null;default:'uploaded'"`
+	Filename        string     `json:"filename,omitempty" gorm:"type:varchar(255)"`
+	MimeType        string     `json:"mime_type" gorm:"type:varchar(100);not null"`
+	Size            int64      `json:"size"`
+	SHA256          string     `json:"sha256" gorm:"type:varchar(64)"`
+	StorageKey      string     `json:"-" gorm:"type:varchar(500)"`
+	Attempts        int        `json:"attempts,omitempty" gorm:"default:0"`
+	LastError       string     `json:"last_error,omitempty" gorm:"type:text"`
+	UploadedBy      string     `json:"uploaded_by,omitempty" gorm:"type:varchar(100)"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"index;not null"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for Media
+func (Media) TableName() string {
+	return "media"
+}
+
+// BeforeCreate hook to generate ID and set timestamps
+func (m *Media) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = GenerateID("media")
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+	if m.UpdatedAt.IsZero() {
+		m.UpdatedAt = time.Now().UTC()
+	}
+	if m.Direction == "" {
+		m.Direction = MediaDirectionOutbound
+	}
+	if m.Status == "" {
+		m.Status = MediaStatusUploaded
+	}
+	return m.Validate()
+}
+
+// BeforeUpdate hook
+func (m *Media) BeforeUpdate(tx *gorm.DB) error {
+	m.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Validate performs business logic validation
+func (m *Media) Validate() error {
+	if m.MimeType == "" {
+		return errors.New("mime_type is required")
+	}
+	if m.Direction != MediaDirectionInbound && m.Direction != MediaDirectionOutbound {
+		return errors.New("direction must be inbound or outbound")
+	}
+	return nil
+}
+
+// IsStored returns true if the content is available in local storage
+func (m *Media) IsStored() bool {
+	return m.Status == MediaStatusStored && m.StorageKey != ""
+}
+
+// IsExpired returns true if WhatsApp no longer holds the media
+func (m *Media) IsExpired() bool {
+	if m.ExpiresAt == nil {
+		return false
+	}
+	return time.Now().UTC().After(*m.ExpiresAt)
+}
@@ -15,34 +15,113 @@ const (
 	MessageStatusDelivered = "delivered"
 	MessageStatusRead      = "read"
 	MessageStatusFailed    = "failed"
+	MessageStatusReceived  = "received"
 )
 
+// outboundStatusOrder ranks the delivery statuses of an outbound message
+var outboundStatusOrder = map[string]int{
+	MessageStatusQueued:    0,
+	MessageStatusSent:      1,
+	MessageStatusDelivered: 2,
+	MessageStatusRead:      3,
+}
+
+// CanTransitionStatus reports whether an outbound message may move from one status
+// to another. Delivery statuses only advance, so updates that arrive out of order
+// never regress a message. Failed is terminal, except that a later delivered or
+// read proves the message arrived after all; a delivered or read message cannot fail.
+func CanTransitionStatus(from, to string) bool {
+	if from == to {
+		return false
+	}
+	if to == MessageStatusFailed {
+		return from == MessageStatusQueued || from == MessageStatusSent
+	}
+
+	toRank, ok := outboundStatusOrder[to]
+	if !ok {
+		return false
+	}
+	if from == MessageStatusFailed {
+		return to == MessageStatusDelivered || to == MessageStatusRead
+	}
+	fromRank, ok := outboundStatusOrder[from]
+	return ok && toRank > fromRank
+}
+
+// StatusesBefore returns the statuses an outbound message may be in to move to status
+func StatusesBefore(status string) []string {
+	var from []string
+	for _, candidate := range []string{
+		MessageStatusQueued,
+		MessageStatusSent,
+		MessageStatusDelivered,
+		MessageStatusRead,
+		MessageStatusFailed,
+	} {
+		if CanTransitionStatus(candidate, status) {
+			from = append(from, candidate)
+		}
+	}
+	return from
+}
+
+// StatusTimestampColumn returns the column recording when an outbound message
+// reached status, or an empty string if the status has none
+func StatusTimestampColumn(status string) string {
+	switch status {
+	case MessageStatusSent:
+		return "sent_at"
+	case MessageStatusDelivered:
+		return "delivered_at"
+	case MessageStatusRead:
+		return "recipient_read_at"
+	}
+	return ""
+}
+
 // Message types
 const (
-	MessageTypeText     = "text"
-	MessageTypeImage    = "image"
-	MessageTypeVideo    = "video"
-	MessageTypeAudio    = "audio"
-	MessageTypeDocument = "document"
-	MessageTypeLocation = "location"
-	MessageTypeTemplate = "template"
+	MessageTypeText        = "text"
+	MessageTypeImage       = "image"
+	MessageTypeVideo       = "video"
+	MessageTypeAudio       = "audio"
+	MessageTypeDocument    = "document"
+	MessageTypeLocation    = "location"
+	MessageTypeContacts    = "contacts"
+	MessageTypeTemplate    = "template"
+	MessageTypeInteractive = "interactive"
+	MessageTypeReaction    = "reaction"
+	MessageTypeOrder       = "order"
+	MessageTypeSticker     = "sticker"
+	MessageTypeButton      = "button"      // template quick reply
+	MessageTypeSystem      = "system"      // customer notices such as a number change
+	MessageTypeUnsupported = "unsupported" // a type WhatsApp could not deliver to the Cloud API
 )
 
 // Message represents a WhatsApp message
 type Message struct {
 	ID                  string    `json:"id" gorm:"primaryKey;type:varchar(100)"`
-	WhatsAppMessageID   string    `json:"whatsapp_message_id" gorm:"uniqueIndex;type:varchar(255)"`
+	WhatsAppMessageID   string    `json:"whatsapp_message_id" gorm:"column:whatsapp_message_id;uniqueIndex;type:varchar(255)"`
 	FromNumber          string    `json:"from_number" gorm:"index;type:varchar(50);not null" validate:"required,e164"`
 	ToNumber            string    `json:"to_number" gorm:"index;type:varchar(50);not null" validate:"required,e164"`
+	PhoneNumberID       string    `json:"phone_number_id,omitempty" gorm:"index;type:varchar(100)"` // our WhatsApp phone number ID the message was exchanged on
 	Direction           string    `json:"direction" gorm:"type:varchar(20);not null" validate:"required,oneof=inbound outbound"`
 	MessageType         string    `json:"message_type" gorm:"type:varchar(50);not null" validate:"required"`
 	Content             string    `json:"content" gorm:"type:text"`
 	MediaURL            string    `json:"media_url,omitempty" gorm:"type:varchar(500)"`
+	MediaID             string    `json:"media_id,omitempty" gorm:"index;type:varchar(100)"` // uploaded media record
 	MediaMimeType       string    `json:"media_mime_type,omitempty" gorm:"type:varchar(100)"`
 	Status              string    `json:"status" gorm:"index;type:varchar(50);not null" validate:"required"`
 	ErrorCode           string    `json:"error_code,omitempty" gorm:"type:varchar(100)"`
 	ErrorMessage        string    `json:"error_message,omitempty" gorm:"type:text"`
 	Metadata            JSONMap   `json:"metadata,omitempty" gorm:"type:jsonb"`
+	ReplyToID           *string   `json:"reply_to_id,omitempty" gorm:"index;type:varchar(100)"` // quoted or reacted-to message
+	SentAt              *time.Time `json:"sent_at,omitempty"`      // when WhatsApp reported the outbound message sent
+	DeliveredAt         *time.Time `json:"delivered_at,omitempty"` // when WhatsApp reported the outbound message delivered
+	RecipientReadAt     *time.Time `json:"recipient_read_at,omitempty"` // when WhatsApp reported the outbound message read
+	ReadAt              *time.Time `json:"read_at,omitempty"`           // when we marked an inbound message as read
+	ReadBy              string    `json:"read_by,omitempty" gorm:"type:varchar(100)"` // API key that marked an inbound message as read
 	Timestamp           time.Time `json:"timestamp" gorm:"index;not null"`
 	CreatedAt           time.Time `json:"created_at" gorm:"index;not null"`
 	UpdatedAt           time.Time `json:"updated_at" gorm:"not null"`
@@ -78,7 +157,9 @@ func (m *Message) BeforeUpdate(tx *gorm.DB) error {
 
 // Validate performs business logic validation
 func (m *Message) Validate() error {
-	if m.FromNumber == "" {
+	// Outbound messages may be sent from a number whose display number could
+	// not be resolved; they are still identified by phone_number_id
+	if m.FromNumber == "" && m.Direction != "outbound" {
 		return errors.New("from_number is required")
 	}
 	if m.ToNumber == "" {
@@ -111,6 +192,11 @@ func (m *Message) IsDelivered() bool {
 	return m.Status == MessageStatusDelivered || m.Status == MessageStatusRead
 }
 
+// IsReaction returns true if the message is an emoji reaction to another message
+func (m *Message) IsReaction() bool {
+	return m.MessageType == MessageTypeReaction
+}
+
 // HasFailed returns true if the message has failed
 func (m *Message) HasFailed() bool {
 	return m.Status == MessageStatusFailed
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MessageStatusEvent records one status update WhatsApp reported for an outbound
+// message, with the failure reason and the conversation and pricing it was charged to
+type MessageStatusEvent struct {
+	ID                    string     `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	MessageID             *string    `json:"message_id,omitempty" gorm:"index;type:varchar(100)"` // nil until the message is known
+	WhatsAppMessageID     string     `json:"whatsapp_message_id" gorm:"column:whatsapp_message_id;uniqueIndex:idx_message_status_events_unique;type:varchar(255);not null"`
+	PhoneNumberID         string     `json:"phone_number_id,omitempty" gorm:"index;type:varchar(100)"`
+	RecipientID           string     `json:"recipient_id,omitempty" gorm:"type:varchar(50)"`
+	Status                string     `json:"status" gorm:"uniqueIndex:idx_message_status_events_unique;type:varchar(50);not null"`
+	Timestamp             time.Time  `json:"timestamp" gorm:"uniqueIndex:idx_message_status_events_unique;index;not null"`
+	ErrorCode             int        `json:"error_code,omitempty"`
+	ErrorTitle            string     `json:"error_title,omitempty" gorm:"type:varchar(255)"`
+	ErrorMessage          string     `json:"error_message,omitempty" gorm:"type:text"`
+	ErrorDetails          string     `json:"error_details,omitempty" gorm:"type:text"`
+	ConversationID        string     `json:"conversation_id,omitempty" gorm:"index;type:varchar(255)"`
+	ConversationOrigin    string     `json:"conversation_origin,omitempty" gorm:"type:varchar(50)"`
+	ConversationExpiresAt *time.Time `json:"conversation_expires_at,omitempty"`
+	Billable              *bool      `json:"billable,omitempty"`
+	PricingModel          string     `json:"pricing_model,omitempty" gorm:"type:varchar(20)"`
+	PricingCategory       string     `json:"pricing_category,omitempty" gorm:"index;type:varchar(50)"`
+	PricingType           string     `json:"pricing_type,omitempty" gorm:"type:varchar(50)"`
+	CreatedAt             time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt             time.Time  `json:"updated_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for MessageStatusEvent
+func (MessageStatusEvent) TableName() string {
+	return "message_status_events"
+}
+
+// BeforeCreate hook to generate ID and set timestamps
+func (e *MessageStatusEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = GenerateID("msgst")
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	if e.UpdatedAt.IsZero() {
+		e.UpdatedAt = time.Now().UTC()
+	}
+	return e.Validate()
+}
+
+// BeforeUpdate hook
+func (e *MessageStatusEvent) BeforeUpdate(tx *gorm.DB) error {
+	e.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Validate performs business logic validation
+func (e *MessageStatusEvent) Validate() error {
+	if e.WhatsAppMessageID == "" {
+		return errors.New("whatsapp_message_id is required")
+	}
+	if e.Status == "" {
+		return errors.New("status is required")
+	}
+	return nil
+}
+
+// HasError returns true if WhatsApp reported why the message failed
+func (e *MessageStatusEvent) HasError() bool {
+	return e.ErrorCode != 0
+}
+
+// FailureReason describes why the message failed, from the most specific details
+// WhatsApp sent
+func (e *MessageStatusEvent) FailureReason() string {
+	parts := make([]string, 0, 2)
+	if e.ErrorTitle != "" {
+		parts = append(parts, e.ErrorTitle)
+	}
+	if e.ErrorDetails != "" {
+		parts = append(parts, e.ErrorDetails)
+	} else if e.ErrorMessage != "" && e.ErrorMessage != e.ErrorTitle {
+		parts = append(parts, e.ErrorMessage)
+	}
+	return strings.Join(parts, ": ")
+}
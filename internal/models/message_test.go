@@ -0,0 +1,52 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanTransitionStatus(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{MessageStatusQueued, MessageStatusSent, true},
+		{MessageStatusSent, MessageStatusDelivered, true},
+		{MessageStatusSent, MessageStatusRead, true},
+		{MessageStatusDelivered, MessageStatusRead, true},
+		{MessageStatusRead, MessageStatusDelivered, false},
+		{MessageStatusDelivered, MessageStatusSent, false},
+		{MessageStatusSent, MessageStatusSent, false},
+		{MessageStatusSent, MessageStatusFailed, true},
+		{MessageStatusQueued, MessageStatusFailed, true},
+		{MessageStatusDelivered, MessageStatusFailed, false},
+		{MessageStatusRead, MessageStatusFailed, false},
+		{MessageStatusFailed, MessageStatusSent, false},
+		{MessageStatusFailed, MessageStatusDelivered, true},
+		{MessageStatusFailed, MessageStatusRead, true},
+		{MessageStatusReceived, MessageStatusRead, false},
+		{MessageStatusSent, "deleted", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionStatus(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionStatus(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestStatusesBefore(t *testing.T) {
+	tests := map[string][]string{
+		MessageStatusSent:      {MessageStatusQueued},
+		MessageStatusDelivered: {MessageStatusQueued, MessageStatusSent, MessageStatusFailed},
+		MessageStatusRead:      {MessageStatusQueued, MessageStatusSent, MessageStatusDelivered, MessageStatusFailed},
+		MessageStatusFailed:    {MessageStatusQueued, MessageStatusSent},
+		MessageStatusQueued:    nil,
+	}
+
+	for status, want := range tests {
+		if got := StatusesBefore(status); !reflect.DeepEqual(got, want) {
+			t.Errorf("StatusesBefore(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
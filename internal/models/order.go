@@ -0,0 +1,153 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Order statuses
+const (
+	OrderStatusPending    = "pending"
+	OrderStatusConfirmed  = "confirmed"
+	OrderStatusProcessing = "processing"
+	OrderStatusShipped    = "shipped"
+	OrderStatusDelivered  = "delivered"
+	OrderStatusCancelled  = "cancelled"
+)
+
+// orderStatuses lists the valid order statuses
+var orderStatuses = map[string]bool{
+	OrderStatusPending:    true,
+	OrderStatusConfirmed:  true,
+	OrderStatusProcessing: true,
+	OrderStatusShipped:    true,
+	OrderStatusDelivered:  true,
+	OrderStatusCancelled:  true,
+}
+
+// Order represents an order a contact placed from a catalog or product message
+type Order struct {
+	ID              string      `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	MessageID       string      `json:"message_id" gorm:"uniqueIndex;type:varchar(100);not null"` // inbound order message
+	ReplyToID       *string     `json:"reply_to_id,omitempty" gorm:"index;type:varchar(100)"`     // product message the order was placed from
+	ContactID       string      `json:"contact_id" gorm:"index;type:varchar(100);not null"`
+	PhoneNumber     string      `json:"phone_number" gorm:"index;type:varchar(50);not null"`
+	PhoneNumberID   string      `json:"phone_number_id,omitempty" gorm:"index;type:varchar(100)"`
+	CatalogID       string      `json:"catalog_id" gorm:"index;type:varchar(100);not null"`
+	Text            string      `json:"text,omitempty" gorm:"type:text"`
+	Status          string      `json:"status" gorm:"index;type:varchar(20);not null;default:'pending'"`
+	Currency        string      `json:"currency" gorm:"type:varchar(10)"`
+	Total           float64     `json:"total" gorm:"type:decimal(14,2)"`
+	ItemCount       int         `json:"item_count"`
+	Note            string      `json:"note,omitempty" gorm:"type:text"` // merchant note from the last status update
+	Items           []OrderItem `json:"items,omitempty" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	PlacedAt        time.Time   `json:"placed_at" gorm:"index;not null"`
+	StatusUpdatedAt *time.Time  `json:"status_updated_at,omitempty"`
+	CreatedAt       time.Time   `json:"created_at" gorm:"not null"`
+	UpdatedAt       time.Time   `json:"updated_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for Order
+func (Order) TableName() string {
+	return "orders"
+}
+
+// BeforeCreate hook to generate ID and set timestamps
+func (o *Order) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == "" {
+		o.ID = GenerateID("order")
+	}
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = time.Now().UTC()
+	}
+	if o.UpdatedAt.IsZero() {
+		o.UpdatedAt = time.Now().UTC()
+	}
+	if o.PlacedAt.IsZero() {
+		o.PlacedAt = o.CreatedAt
+	}
+	if o.Status == "" {
+		o.Status = OrderStatusPending
+	}
+	return o.Validate()
+}
+
+// BeforeUpdate hook
+func (o *Order) BeforeUpdate(tx *gorm.DB) error {
+	o.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Validate performs business logic validation
+func (o *Order) Validate() error {
+	if o.MessageID == "" {
+		return errors.New("message_id is required")
+	}
+	if o.ContactID == "" {
+		return errors.New("contact_id is required")
+	}
+	if o.CatalogID == "" {
+		return errors.New("catalog_id is required")
+	}
+	if !orderStatuses[o.Status] {
+		return fmt.Errorf("invalid order status: %s", o.Status)
+	}
+	return nil
+}
+
+// IsClosed returns true once the order is delivered or cancelled
+func (o *Order) IsClosed() bool {
+	return o.Status == OrderStatusDelivered || o.Status == OrderStatusCancelled
+}
+
+// CanTransitionTo reports whether the order may move to the given status.
+// Closed orders can no longer change.
+func (o *Order) CanTransitionTo(status string) error {
+	if !orderStatuses[status] {
+		return fmt.Errorf("invalid order status: %s", status)
+	}
+	if o.IsClosed() && status != o.Status {
+		return fmt.Errorf("order is %s and can no longer change status", o.Status)
+	}
+	return nil
+}
+
+// OrderItem represents a line item of an order
+type OrderItem struct {
+	ID                string    `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	OrderID           string    `json:"order_id" gorm:"index;type:varchar(100);not null"`
+	ProductRetailerID string    `json:"product_retailer_id" gorm:"index;type:varchar(255);not null"`
+	Quantity          int       `json:"quantity"`
+	ItemPrice         float64   `json:"item_price" gorm:"type:decimal(14,2)"`
+	Currency          string    `json:"currency" gorm:"type:varchar(10)"`
+	CreatedAt         time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt         time.Time `json:"updated_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for OrderItem
+func (OrderItem) TableName() string {
+	return "order_items"
+}
+
+// BeforeCreate hook to generate ID and set timestamps
+func (i *OrderItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = GenerateID("orditem")
+	}
+	if i.CreatedAt.IsZero() {
+		i.CreatedAt = time.Now().UTC()
+	}
+	if i.UpdatedAt.IsZero() {
+		i.UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
+
+// BeforeUpdate hook
+func (i *OrderItem) BeforeUpdate(tx *gorm.DB) error {
+	i.UpdatedAt = time.Now().UTC()
+	return nil
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PhoneNumber represents a phone number registered in the WhatsApp Business Account
+type PhoneNumber struct {
+	ID                    string     `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	WhatsAppPhoneNumberID string     `json:"whatsapp_phone_number_id" gorm:"column:whatsapp_phone_number_id;uniqueIndex;type:varchar(100);not null"`
+	DisplayPhoneNumber    string     `json:"display_phone_number" gorm:"type:varchar(50)"`
+	VerifiedName          string     `json:"verified_name" gorm:"type:varchar(255)"`
+	NameStatus            string     `json:"name_status" gorm:"type:varchar(50)"`
+	QualityRating         string     `json:"quality_rating" gorm:"index;type:varchar(20)"`
+	MessagingLimitTier    string     `json:"messaging_limit_tier" gorm:"type:varchar(50)"`
+	Status                string     `json:"status" gorm:"type:varchar(50)"`
+	ThroughputLevel       string     `json:"throughput_level,omitempty" gorm:"type:varchar(50)"`
+	LastSyncedAt          *time.Time `json:"last_synced_at,omitempty"`
+	CreatedAt             time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt             time.Time  `json:"updated_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for PhoneNumber
+func (PhoneNumber) TableName() string {
+	return "phone_numbers"
+}
+
+// BeforeCreate hook to generate ID and set timestamps
+func (p *PhoneNumber) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = GenerateID("phone")
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now().UTC()
+	}
+	if p.UpdatedAt.IsZero() {
+		p.UpdatedAt = time.Now().UTC()
+	}
+	return p.Validate()
+}
+
+// BeforeUpdate hook
+func (p *PhoneNumber) BeforeUpdate(tx *gorm.DB) error {
+	p.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Validate performs business logic validation
+func (p *PhoneNumber) Validate() error {
+	if p.WhatsAppPhoneNumberID == "" {
+		return errors.New("whatsapp_phone_number_id is required")
+	}
+	return nil
+}
+
+// Snapshot captures the tracked state of the phone number at the given time
+func (p *PhoneNumber) Snapshot(recordedAt time.Time) *PhoneNumberSnapshot {
+	return &PhoneNumberSnapshot{
+		PhoneNumberID:      p.ID,
+		NameStatus:         p.NameStatus,
+		QualityRating:      p.QualityRating,
+		MessagingLimitTier: p.MessagingLimitTier,
+		Status:             p.Status,
+		RecordedAt:         recordedAt,
+	}
+}
+
+// PhoneNumberSnapshot records the state of a phone number whenever a tracked field changes
+type PhoneNumberSnapshot struct {
+	ID                 string    `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	PhoneNumberID      string    `json:"phone_number_id" gorm:"index:idx_phone_number_snapshots_phone_recorded;type:varchar(100);not null"`
+	NameStatus         string    `json:"name_status" gorm:"type:varchar(50)"`
+	QualityRating      string    `json:"quality_rating" gorm:"type:varchar(20)"`
+	MessagingLimitTier string    `json:"messaging_limit_tier" gorm:"type:varchar(50)"`
+	Status             string    `json:"status" gorm:"type:varchar(50)"`
+	RecordedAt         time.Time `json:"recorded_at" gorm:"index:idx_phone_number_snapshots_phone_recorded;not null"`
+	CreatedAt          time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt          time.Time `json:"updated_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for PhoneNumberSnapshot
+func (PhoneNumberSnapshot) TableName() string {
+	return "phone_number_snapshots"
+}
+
+// BeforeCreate hook to generate ID and set timestamps
+func (s *PhoneNumberSnapshot) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = GenerateID("pnsnap")
+	}
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now().UTC()
+	}
+	if s.UpdatedAt.IsZero() {
+		s.UpdatedAt = time.Now().UTC()
+	}
+	if s.RecordedAt.IsZero() {
+		s.RecordedAt = s.CreatedAt
+	}
+	return nil
+}
+
+// BeforeUpdate hook
+func (s *PhoneNumberSnapshot) BeforeUpdate(tx *gorm.DB) error {
+	s.UpdatedAt = time.Now().UTC()
+	return nil
+}
@@ -9,11 +9,18 @@ import (
 	"gorm.io/gorm"
 )
 
-// Template statuses
+// Template statuses, as reported by WhatsApp in lower case
 const (
-	TemplateStatusApproved = "approved"
-	TemplateStatusPending  = "pending"
-	TemplateStatusRejected = "rejected"
+	TemplateStatusApproved        = "approved"
+	TemplateStatusPending         = "pending"
+	TemplateStatusRejected        = "rejected"
+	TemplateStatusPaused          = "paused"
+	TemplateStatusDisabled        = "disabled"
+	TemplateStatusInAppeal        = "in_appeal"
+	TemplateStatusPendingDeletion = "pending_deletion"
+	TemplateStatusDeleted         = "deleted"
+	TemplateStatusArchived        = "archived"
+	TemplateStatusLimitExceeded   = "limit_exceeded"
 )
 
 // Template categories
@@ -25,16 +32,40 @@ const (
 
 // Template represents a WhatsApp message template
 type Template struct {
-	ID          string     `json:"id" gorm:"primaryKey;type:varchar(100)"`
-	Name        string     `json:"name" gorm:"index;type:varchar(255);not null" validate:"required"`
-	Language    string     `json:"language" gorm:"type:varchar(10);not null" validate:"required"`
-	Category    string     `json:"category" gorm:"type:varchar(50);not null" validate:"required"`
-	Status      string     `json:"status" gorm:"index;type:varchar(50);not null" validate:"required"`
-	Content     string     `json:"content" gorm:"type:text;not null" validate:"required"`
-	Parameters  JSONArray  `json:"parameters,omitempty" gorm:"type:jsonb"`
-	Metadata    JSONMap    `json:"metadata,omitempty" gorm:"type:jsonb"`
-	CreatedAt   time.Time  `json:"created_at" gorm:"index;not null"`
-	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null"`
+	ID                 string             `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	WhatsAppTemplateID string             `json:"whatsapp_template_id,omitempty" gorm:"column:whatsapp_template_id;index;type:varchar(100)"`
+	Name               string             `json:"name" gorm:"index;type:varchar(255);not null" validate:"required"`
+	Language           string             `json:"language" gorm:"type:varchar(10);not null" validate:"required"`
+	Category           string             `json:"category" gorm:"type:varchar(50);not null" validate:"required"`
+	Status             string             `json:"status" gorm:"index;type:varchar(50);not null" validate:"required"`
+	RejectionReason    string             `json:"rejection_reason,omitempty" gorm:"type:varchar(255)"`
+	QualityScore       string             `json:"quality_score,omitempty" gorm:"type:varchar(20)"` // GREEN, YELLOW, RED or UNKNOWN, as rated by WhatsApp
+	Content            string             `json:"content" gorm:"type:text;not null" validate:"required"`
+	Parameters         JSONArray          `json:"parameters,omitempty" gorm:"type:jsonb"` // example values for body placeholders
+	Components         TemplateComponents `json:"components,omitempty" gorm:"type:jsonb"`
+	Metadata           JSONMap            `json:"metadata,omitempty" gorm:"type:jsonb"`
+	LastSyncedAt       *time.Time         `json:"last_synced_at,omitempty"`
+	StatusChangedAt    *time.Time         `json:"status_changed_at,omitempty"` // when the current status was reported by WhatsApp or found by a sync
+	CreatedAt          time.Time          `json:"created_at" gorm:"index;not null"`
+	UpdatedAt          time.Time          `json:"updated_at" gorm:"not null"`
+}
+
+// TemplateComponent describes one component of a template definition
+type TemplateComponent struct {
+	Type    string                 `json:"type"`             // HEADER, BODY, FOOTER, BUTTONS
+	Format  string                 `json:"format,omitempty"` // TEXT, IMAGE, VIDEO, DOCUMENT, LOCATION (headers)
+	Text    string                 `json:"text,omitempty"`
+	Example map[string]interface{} `json:"example,omitempty"`
+	Buttons []TemplateButton       `json:"buttons,omitempty"`
+}
+
+// TemplateButton describes a button of a template definition
+type TemplateButton struct {
+	Type        string   `json:"type"` // QUICK_REPLY, URL, PHONE_NUMBER, COPY_CODE, FLOW
+	Text        string   `json:"text,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	PhoneNumber string   `json:"phone_number,omitempty"`
+	Example     []string `json:"example,omitempty"`
 }
 
 // TableName specifies the table name for Template
@@ -87,7 +118,12 @@ func (t *Template) Validate() error {
 	}
 
 	// Validate status
-	validStatuses := []string{TemplateStatusApproved, TemplateStatusPending, TemplateStatusRejected}
+	validStatuses := []string{
+		TemplateStatusApproved, TemplateStatusPending, TemplateStatusRejected,
+		TemplateStatusPaused, TemplateStatusDisabled, TemplateStatusInAppeal,
+		TemplateStatusPendingDeletion, TemplateStatusDeleted, TemplateStatusArchived,
+		TemplateStatusLimitExceeded,
+	}
 	if !contains(validStatuses, t.Status) {
 		return fmt.Errorf("invalid status: %s", t.Status)
 	}
@@ -100,6 +136,29 @@ func (t *Template) IsApproved() bool {
 	return t.Status == TemplateStatusApproved
 }
 
+// IsSynced returns true if the template exists in the WhatsApp Business Account
+func (t *Template) IsSynced() bool {
+	return t.WhatsAppTemplateID != ""
+}
+
+// Component returns the component of the given type (HEADER, BODY, FOOTER, BUTTONS), if present
+func (t *Template) Component(componentType string) *TemplateComponent {
+	for i := range t.Components {
+		if strings.EqualFold(t.Components[i].Type, componentType) {
+			return &t.Components[i]
+		}
+	}
+	return nil
+}
+
+// HeaderFormat returns the header format (TEXT, IMAGE, VIDEO, DOCUMENT, LOCATION), or "" without a header
+func (t *Template) HeaderFormat() string {
+	if header := t.Component("HEADER"); header != nil {
+		return strings.ToUpper(header.Format)
+	}
+	return ""
+}
+
 // ParameterCount returns the number of parameters in the template
 func (t *Template) ParameterCount() int {
 	return len(t.Parameters)
@@ -80,3 +80,85 @@ func (j *JSONArray) Scan(value interface{}) error {
 	*j = result
 	return nil
 }
+
+// TemplateComponents represents template definition components stored as JSONB
+type TemplateComponents []TemplateComponent
+
+// Value implements the driver.Valuer interface for TemplateComponents
+func (t TemplateComponents) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return json.Marshal(t)
+}
+
+// Scan implements the sql.Scanner interface for TemplateComponents
+func (t *TemplateComponents) Scan(value interface{}) error {
+	if value == nil {
+		*t = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("type assertion to []byte failed")
+	}
+
+	if len(bytes) == 0 {
+		*t = nil
+		return nil
+	}
+
+	var result []TemplateComponent
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return fmt.Errorf("failed to unmarshal TemplateComponents: %w", err)
+	}
+
+	*t = result
+	return nil
+}
+
+// RawJSON holds a JSON document verbatim, such as a webhook payload kept for replay
+type RawJSON []byte
+
+// Value implements the driver.Valuer interface for RawJSON
+func (r RawJSON) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return nil, nil
+	}
+	return string(r), nil
+}
+
+// Scan implements the sql.Scanner interface for RawJSON
+func (r *RawJSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = nil
+	case []byte:
+		*r = append(RawJSON(nil), v...)
+	case string:
+		*r = RawJSON(v)
+	default:
+		return errors.New("type assertion to []byte failed")
+	}
+	return nil
+}
+
+// MarshalJSON embeds the document as is
+func (r RawJSON) MarshalJSON() ([]byte, error) {
+	if len(r) == 0 {
+		return []byte("null"), nil
+	}
+	return r, nil
+}
+
+// UnmarshalJSON keeps a copy of the document
+func (r *RawJSON) UnmarshalJSON(data []byte) error {
+	*r = append(RawJSON(nil), data...)
+	return nil
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookEventStatus constants
+const (
+	WebhookEventStatusPending    = "pending"    // stored, waiting for a worker
+	WebhookEventStatusProcessing = "processing" // claimed by a worker
+	WebhookEventStatusProcessed  = "processed"  // every message and status in it was handled
+	WebhookEventStatusFailed     = "failed"     // last attempt failed, retry scheduled at NextAttemptAt
+	WebhookEventStatusDead       = "dead"       // gave up after the maximum number of attempts
+)
+
+// WebhookEvent is a webhook payload received from WhatsApp, stored before it is
+// processed so that no delivery is lost when processing fails
+type WebhookEvent struct {
+	ID            string     `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	Object        string     `json:"object" gorm:"index;type:varchar(100)"`
+	Payload       RawJSON    `json:"payload" gorm:"type:jsonb;not null"`
+	Status        string     `json:"status" gorm:"index;type:varchar(20);not null;default:'pending'"`
+	Attempts      int        `json:"attempts" gorm:"default:0"`
+	LastError     string     `json:"last_error,omitempty" gorm:"type:text"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" gorm:"index"`
+	ProcessedAt   *time.Time `json:"processed_at,omitempty"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"` // when the account notices in the payload were published; they are not published again
+	ReceivedAt    time.Time  `json:"received_at" gorm:"index;not null"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for WebhookEvent
+func (WebhookEvent) TableName() string {
+	return "webhook_events"
+}
+
+// BeforeCreate hook to generate ID and set timestamps
+func (e *WebhookEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = GenerateID("whevt")
+	}
+	now := time.Now().UTC()
+	if e.ReceivedAt.IsZero() {
+		e.ReceivedAt = now
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = now
+	}
+	if e.UpdatedAt.IsZero() {
+		e.UpdatedAt = now
+	}
+	if e.Status == "" {
+		e.Status = WebhookEventStatusPending
+	}
+	return e.Validate()
+}
+
+// BeforeUpdate hook
+func (e *WebhookEvent) BeforeUpdate(tx *gorm.DB) error {
+	e.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Validate performs business logic validation
+func (e *WebhookEvent) Validate() error {
+	if len(e.Payload) == 0 {
+		return errors.New("payload is required")
+	}
+	return nil
+}
+
+// IsFinal returns true if the event will not be processed again without a manual reprocess
+func (e *WebhookEvent) IsFinal() bool {
+	return e.Status == WebhookEventStatusProcessed || e.Status == WebhookEventStatusDead
+}
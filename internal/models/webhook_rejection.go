@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook rejection reasons
+const (
+	WebhookRejectionMissingSignature   = "missing_signature"   // unsigned delivery while a secret is configured
+	WebhookRejectionInvalidSignature   = "invalid_signature"   // signature matches none of the configured secrets
+	WebhookRejectionBodyTooLarge       = "body_too_large"      // body exceeds the configured limit
+	WebhookRejectionVerificationFailed = "verification_failed" // subscription check with a wrong mode or verify token
+)
+
+// WebhookRejection is a security log entry for a request to the webhook endpoint
+// that was refused, kept so that forged or misconfigured deliveries can be traced
+type WebhookRejection struct {
+	ID        string `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	Reason    string `json:"reason" gorm:"index;type:varchar(50);not null"`
+	SourceIP  string `json:"source_ip" gorm:"index;type:varchar(100)"` // client IP, from X-Forwarded-For when sent by a trusted proxy
+	RemoteIP  string `json:"remote_ip" gorm:"type:varchar(100)"`       // address of the connection peer
+	UserAgent string `json:"user_agent,omitempty" gorm:"type:varchar(500)"`
+	Method    string `json:"method" gorm:"type:varchar(10)"`
+	Path      string `json:"path" gorm:"type:varchar(255)"`
+	BodySize  int64  `json:"body_size"` // bytes read before rejecting, or the declared length if the body was too large
+	// Suppressed counts the rejections with the same source IP and reason that
+	// were not stored since the previous entry, to keep floods from filling the log
+	Suppressed int64     `json:"suppressed,omitempty"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index;not null"`
+}
+
+// TableName specifies the table name for WebhookRejection
+func (WebhookRejection) TableName() string {
+	return "webhook_rejections"
+}
+
+// BeforeCreate hook to generate ID and set timestamps
+func (r *WebhookRejection) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = GenerateID("whrej")
+	}
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+	return r.Validate()
+}
+
+// Validate performs business logic validation
+func (r *WebhookRejection) Validate() error {
+	if r.Reason == "" {
+		return errors.New("reason is required")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"time"
 
 	"github.com/ashok/vibecoded-wa-client/internal/models"
@@ -20,45 +21,45 @@ func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
 }
 
 // FindByKeyHash finds an API key by its hash
-func (r *APIKeyRepository) FindByKeyHash(keyHash string) (*models.APIKey, error) {
+func (r *APIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
 	var apiKey models.APIKey
-	err := r.DB.Where("key_hash = ?", keyHash).First(&apiKey).Error
+	err := r.DB.WithContext(ctx).Where("key_hash = ?", keyHash).First(&apiKey).Error
 	return &apiKey, err
 }
 
 // FindByKeyPrefix finds API keys by prefix
-func (r *APIKeyRepository) FindByKeyPrefix(prefix string) ([]*models.APIKey, error) {
+func (r *APIKeyRepository) FindByKeyPrefix(ctx context.Context, prefix string) ([]*models.APIKey, error) {
 	var apiKeys []*models.APIKey
-	err := r.DB.Where("key_prefix = ?", prefix).Find(&apiKeys).Error
+	err := r.DB.WithContext(ctx).Where("key_prefix = ?", prefix).Find(&apiKeys).Error
 	return apiKeys, err
 }
 
 // UpdateLastUsed updates the last_used_at timestamp
-func (r *APIKeyRepository) UpdateLastUsed(id string) error {
-	return r.DB.Model(&models.APIKey{}).
+func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Model(&models.APIKey{}).
 		Where("id = ?", id).
 		Update("last_used_at", time.Now().UTC()).Error
 }
 
 // FindValid finds all valid (not expired) API keys
-func (r *APIKeyRepository) FindValid() ([]*models.APIKey, error) {
+func (r *APIKeyRepository) FindValid(ctx context.Context) ([]*models.APIKey, error) {
 	var apiKeys []*models.APIKey
-	err := r.DB.Where("expires_at IS NULL OR expires_at > ?", time.Now().UTC()).
+	err := r.DB.WithContext(ctx).Where("expires_at IS NULL OR expires_at > ?", time.Now().UTC()).
 		Find(&apiKeys).Error
 	return apiKeys, err
 }
 
 // FindExpired finds all expired API keys
-func (r *APIKeyRepository) FindExpired() ([]*models.APIKey, error) {
+func (r *APIKeyRepository) FindExpired(ctx context.Context) ([]*models.APIKey, error) {
 	var apiKeys []*models.APIKey
-	err := r.DB.Where("expires_at IS NOT NULL AND expires_at <= ?", time.Now().UTC()).
+	err := r.DB.WithContext(ctx).Where("expires_at IS NOT NULL AND expires_at <= ?", time.Now().UTC()).
 		Find(&apiKeys).Error
 	return apiKeys, err
 }
 
 // ListAll lists all API keys
-func (r *APIKeyRepository) ListAll() ([]*models.APIKey, error) {
+func (r *APIKeyRepository) ListAll(ctx context.Context) ([]*models.APIKey, error) {
 	var apiKeys []*models.APIKey
-	err := r.DB.Order("created_at DESC").Find(&apiKeys).Error
+	err := r.DB.WithContext(ctx).Order("created_at DESC").Find(&apiKeys).Error
 	return apiKeys, err
 }
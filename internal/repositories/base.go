@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/ashok/vibecoded-wa-client/pkg/utils"
 	"gorm.io/gorm"
 )
@@ -16,38 +18,38 @@ func NewBaseRepository(db *gorm.DB) *BaseRepository {
 }
 
 // Create creates a new record
-func (r *BaseRepository) Create(model interface{}) error {
-	return r.DB.Create(model).Error
+func (r *BaseRepository) Create(ctx context.Context, model interface{}) error {
+	return r.DB.WithContext(ctx).Create(model).Error
 }
 
 // FindByID finds a record by ID
-func (r *BaseRepository) FindByID(id string, model interface{}) error {
-	return r.DB.Where("id = ?", id).First(model).Error
+func (r *BaseRepository) FindByID(ctx context.Context, id string, model interface{}) error {
+	return r.DB.WithContext(ctx).Where("id = ?", id).First(model).Error
 }
 
 // Update updates a record
-func (r *BaseRepository) Update(model interface{}) error {
-	return r.DB.Save(model).Error
+func (r *BaseRepository) Update(ctx context.Context, model interface{}) error {
+	return r.DB.WithContext(ctx).Save(model).Error
 }
 
 // UpdateFields updates specific fields
-func (r *BaseRepository) UpdateFields(id string, model interface{}, updates map[string]interface{}) error {
-	return r.DB.Model(model).Where("id = ?", id).Updates(updates).Error
+func (r *BaseRepository) UpdateFields(ctx context.Context, id string, model interface{}, updates map[string]interface{}) error {
+	return r.DB.WithContext(ctx).Model(model).Where("id = ?", id).Updates(updates).Error
 }
 
 // Delete soft deletes a record
-func (r *BaseRepository) Delete(model interface{}) error {
-	return r.DB.Delete(model).Error
+func (r *BaseRepository) Delete(ctx context.Context, model interface{}) error {
+	return r.DB.WithContext(ctx).Delete(model).Error
 }
 
 // HardDelete permanently deletes a record
-func (r *BaseRepository) HardDelete(model interface{}) error {
-	return r.DB.Unscoped().Delete(model).Error
+func (r *BaseRepository) HardDelete(ctx context.Context, model interface{}) error {
+	return r.DB.WithContext(ctx).Unscoped().Delete(model).Error
 }
 
 // List returns paginated records
-func (r *BaseRepository) List(model interface{}, pagination *utils.Pagination) error {
-	query := r.DB.Model(model)
+func (r *BaseRepository) List(ctx context.Context, model interface{}, pagination *utils.Pagination) error {
+	query := r.DB.WithContext(ctx).Model(model)
 
 	// Get total count
 	var total int64
@@ -61,8 +63,8 @@ func (r *BaseRepository) List(model interface{}, pagination *utils.Pagination) e
 }
 
 // Exists checks if a record exists
-func (r *BaseRepository) Exists(query interface{}, args ...interface{}) (bool, error) {
+func (r *BaseRepository) Exists(ctx context.Context, query interface{}, args ...interface{}) (bool, error) {
 	var count int64
-	err := r.DB.Model(&struct{}{}).Where(query, args...).Count(&count).Error
+	err := r.DB.WithContext(ctx).Model(&struct{}{}).Where(query, args...).Count(&count).Error
 	return count > 0, err
 }
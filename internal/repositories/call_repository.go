@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+)
+
+// CallRepository handles call data access
+type CallRepository struct {
+	*BaseRepository
+}
+
+// NewCallRepository creates a new call repository
+func NewCallRepository(db *gorm.DB) *CallRepository {
+	return &CallRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
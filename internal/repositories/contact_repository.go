@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"time"
 
 	"github.com/ashok/vibecoded-wa-client/internal/models"
@@ -22,30 +23,40 @@ func NewContactRepository(db *gorm.DB) *ContactRepository {
 }
 
 // FindByPhone finds a contact by phone number
-func (r *ContactRepository) FindByPhone(phone string) (*models.Contact, error) {
+func (r *ContactRepository) FindByPhone(ctx context.Context, phone string) (*models.Contact, error) {
 	var contact models.Contact
-	err := r.DB.Where("phone_number = ?", phone).First(&contact).Error
+	err := r.DB.WithContext(ctx).Where("phone_number = ?", phone).First(&contact).Error
 	return &contact, err
 }
 
 // GetOrCreate gets an existing contact or creates a new one
-func (r *ContactRepository) GetOrCreate(phone string) (*models.Contact, error) {
+func (r *ContactRepository) GetOrCreate(ctx context.Context, phone string) (*models.Contact, error) {
 	var contact models.Contact
 
 	// Use upsert to handle race conditions
-	result := r.DB.Where("phone_number = ?", phone).
+	result := r.DB.WithContext(ctx).Where("phone_number = ?", phone).
 		Attrs(models.Contact{PhoneNumber: phone}).
 		FirstOrCreate(&contact)
 
 	return &contact, result.Error
 }
 
+// contactsOnPhoneNumber matches contacts with messages on one of our phone numbers
+const contactsOnPhoneNumber = `EXISTS (
+	SELECT 1 FROM messages
+	WHERE (messages.from_number = contacts.phone_number OR messages.to_number = contacts.phone_number)
+	AND messages.phone_number_id = ?
+)`
+
 // Search searches contacts by name or phone
-func (r *ContactRepository) Search(query string, pagination *utils.Pagination) ([]*models.Contact, error) {
+func (r *ContactRepository) Search(ctx context.Context, query string, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Contact, error) {
 	var contacts []*models.Contact
 
-	dbQuery := r.DB.Where("name ILIKE ? OR phone_number ILIKE ?", "%"+query+"%", "%"+query+"%").
-		Order("last_message_at DESC NULLS LAST")
+	dbQuery := r.DB.WithContext(ctx).Where("name ILIKE ? OR phone_number ILIKE ?", "%"+query+"%", "%"+query+"%")
+	if phoneNumberID, ok := filters["phone_number_id"].(string); ok && phoneNumberID != "" {
+		dbQuery = dbQuery.Where(contactsOnPhoneNumber, phoneNumberID)
+	}
+	dbQuery = dbQuery.Order("last_message_at DESC NULLS LAST")
 
 	// Get total count
 	var total int64
@@ -60,8 +71,8 @@ func (r *ContactRepository) Search(query string, pagination *utils.Pagination) (
 }
 
 // UpdateLastMessage updates the last message timestamp for a contact
-func (r *ContactRepository) UpdateLastMessage(phone string, timestamp time.Time) error {
-	return r.DB.Model(&models.Contact{}).
+func (r *ContactRepository) UpdateLastMessage(ctx context.Context, phone string, timestamp time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.Contact{}).
 		Where("phone_number = ?", phone).
 		Updates(map[string]interface{}{
 			"last_message_at": timestamp,
@@ -70,31 +81,31 @@ func (r *ContactRepository) UpdateLastMessage(phone string, timestamp time.Time)
 }
 
 // IncrementMessageCount increments the message count for a contact
-func (r *ContactRepository) IncrementMessageCount(phone string, delta int) error {
-	return r.DB.Model(&models.Contact{}).
+func (r *ContactRepository) IncrementMessageCount(ctx context.Context, phone string, delta int) error {
+	return r.DB.WithContext(ctx).Model(&models.Contact{}).
 		Where("phone_number = ?", phone).
 		UpdateColumn("message_count", gorm.Expr("message_count + ?", delta)).Error
 }
 
 // UpdateUnreadCount updates the unread count for a contact
-func (r *ContactRepository) UpdateUnreadCount(phone string, delta int) error {
-	return r.DB.Model(&models.Contact{}).
+func (r *ContactRepository) UpdateUnreadCount(ctx context.Context, phone string, delta int) error {
+	return r.DB.WithContext(ctx).Model(&models.Contact{}).
 		Where("phone_number = ?", phone).
 		UpdateColumn("unread_count", gorm.Expr("GREATEST(unread_count + ?, 0)", delta)).Error
 }
 
-// ResetUnreadCount resets the unread count to zero
-func (r *ContactRepository) ResetUnreadCount(phone string) error {
-	return r.DB.Model(&models.Contact{}).
+// SetUnreadCount sets the unread count of a contact
+func (r *ContactRepository) SetUnreadCount(ctx context.Context, phone string, count int64) error {
+	return r.DB.WithContext(ctx).Model(&models.Contact{}).
 		Where("phone_number = ?", phone).
-		Update("unread_count", 0).Error
+		Update("unread_count", count).Error
 }
 
 // FindActive finds contacts with recent activity
-func (r *ContactRepository) FindActive(limit int, pagination *utils.Pagination) ([]*models.Contact, error) {
+func (r *ContactRepository) FindActive(ctx context.Context, limit int, pagination *utils.Pagination) ([]*models.Contact, error) {
 	var contacts []*models.Contact
 
-	query := r.DB.Where("last_message_at IS NOT NULL").
+	query := r.DB.WithContext(ctx).Where("last_message_at IS NOT NULL").
 		Order("last_message_at DESC").
 		Limit(limit)
 
@@ -103,10 +114,15 @@ func (r *ContactRepository) FindActive(limit int, pagination *utils.Pagination)
 }
 
 // ListWithFilters lists contacts with filters
-func (r *ContactRepository) ListWithFilters(filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Contact, error) {
+func (r *ContactRepository) ListWithFilters(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Contact, error) {
 	var contacts []*models.Contact
 
-	query := r.DB.Model(&models.Contact{})
+	query := r.DB.WithContext(ctx).Model(&models.Contact{})
+
+	// Only contacts that exchanged messages with one of our numbers
+	if phoneNumberID, ok := filters["phone_number_id"].(string); ok && phoneNumberID != "" {
+		query = query.Where(contactsOnPhoneNumber, phoneNumberID)
+	}
 
 	// Apply sorting
 	sortField := "last_message_at"
@@ -133,8 +149,8 @@ func (r *ContactRepository) ListWithFilters(filters map[string]interface{}, pagi
 }
 
 // UpsertContact creates or updates a contact
-func (r *ContactRepository) UpsertContact(contact *models.Contact) error {
-	return r.DB.Clauses(clause.OnConflict{
+func (r *ContactRepository) UpsertContact(ctx context.Context, contact *models.Contact) error {
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "phone_number"}},
 		DoUpdates: clause.AssignmentColumns([]string{"name", "profile_url", "updated_at"}),
 	}).Create(contact).Error
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// FlowResponseRepository handles flow response data access
+type FlowResponseRepository struct {
+	*BaseRepository
+}
+
+// NewFlowResponseRepository creates a new flow response repository
+func NewFlowResponseRepository(db *gorm.DB) *FlowResponseRepository {
+	return &FlowResponseRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// FindByMessageID finds the flow response submitted in an inbound message
+func (r *FlowResponseRepository) FindByMessageID(ctx context.Context, messageID string) (*models.FlowResponse, error) {
+	var response models.FlowResponse
+	err := r.DB.WithContext(ctx).Where("message_id = ?", messageID).First(&response).Error
+	return &response, err
+}
+
+// ListWithFilters lists flow responses, newest first. Besides column filters,
+// "response" holds submitted field values that must match exactly.
+func (r *FlowResponseRepository) ListWithFilters(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.FlowResponse, error) {
+	var responses []*models.FlowResponse
+
+	query := r.DB.WithContext(ctx).Model(&models.FlowResponse{})
+
+	// Apply filters
+	for _, column := range []string{"contact_id", "phone_number", "phone_number_id", "flow_id", "flow_name", "flow_token", "flow_message_id"} {
+		if value, ok := filters[column].(string); ok && value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+	if fields, ok := filters["response"].(map[string]string); ok {
+		for field, value := range fields {
+			query = query.Where("response ->> ? = ?", field, value)
+		}
+	}
+
+	query = query.Order("received_at DESC")
+
+	// Get total count
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	pagination.SetTotal(total)
+
+	// Apply pagination
+	err := pagination.ApplyToQuery(query).Find(&responses).Error
+	return responses, err
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"gorm.io/gorm"
+)
+
+// MediaRepository handles media data access
+type MediaRepository struct {
+	*BaseRepository
+}
+
+// NewMediaRepository creates a new media repository
+func NewMediaRepository(db *gorm.DB) *MediaRepository {
+	return &MediaRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// FindByWhatsAppMediaID finds media by its WhatsApp media ID
+func (r *MediaRepository) FindByWhatsAppMediaID(ctx context.Context, waMediaID string) (*models.Media, error) {
+	var media models.Media
+	err := r.DB.WithContext(ctx).Where("whatsapp_media_id = ?", waMediaID).First(&media).Error
+	return &media, err
+}
+
+// FindByStatus finds media records in a given status, oldest first
+func (r *MediaRepository) FindByStatus(ctx context.Context, status string, limit int) ([]*models.Media, error) {
+	var media []*models.Media
+	err := r.DB.WithContext(ctx).Where("status = ?", status).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&media).Error
+	return media, err
+}
+
+// ClaimForDownload atomically moves a pending media record to downloading.
+// It returns false if another worker already claimed it.
+func (r *MediaRepository) ClaimForDownload(ctx context.Context, id string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.Media{}).
+		Where("id = ? AND status = ?", id, models.MediaStatusPending).
+		Updates(map[string]interface{}{
+			"status":     models.MediaStatusDownloading,
+			"updated_at": time.Now().UTC(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReclaimStaleDownloads returns downloads that have been claimed since before
+// the cutoff to pending. Such claims belong to a worker that crashed or was
+// restarted; recent claims may still be running on another instance.
+func (r *MediaRepository) ReclaimStaleDownloads(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Model(&models.Media{}).
+		Where("status = ? AND updated_at < ?", models.MediaStatusDownloading, cutoff).
+		Updates(map[string]interface{}{
+			"status":     models.MediaStatusPending,
+			"updated_at": time.Now().UTC(),
+		})
+	return result.RowsAffected, result.Error
+}
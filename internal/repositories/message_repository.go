@@ -1,11 +1,14 @@
 package repositories
 
 import (
+	"context"
+	"strconv"
 	"time"
 
 	"github.com/ashok/vibecoded-wa-client/internal/models"
 	"github.com/ashok/vibecoded-wa-client/pkg/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // MessageRepository handles message data access
@@ -21,10 +24,10 @@ func NewMessageRepository(db *gorm.DB) *MessageRepository {
 }
 
 // FindByPhone finds messages by phone number with pagination
-func (r *MessageRepository) FindByPhone(phone string, pagination *utils.Pagination) ([]*models.Message, error) {
+func (r *MessageRepository) FindByPhone(ctx context.Context, phone string, pagination *utils.Pagination) ([]*models.Message, error) {
 	var messages []*models.Message
 
-	query := r.DB.Where("from_number = ? OR to_number = ?", phone, phone).
+	query := r.DB.WithContext(ctx).Where("from_number = ? OR to_number = ?", phone, phone).
 		Order("timestamp DESC")
 
 	// Get total count
@@ -40,10 +43,10 @@ func (r *MessageRepository) FindByPhone(phone string, pagination *utils.Paginati
 }
 
 // FindByDateRange finds messages within a date range
-func (r *MessageRepository) FindByDateRange(start, end time.Time, pagination *utils.Pagination) ([]*models.Message, error) {
+func (r *MessageRepository) FindByDateRange(ctx context.Context, start, end time.Time, pagination *utils.Pagination) ([]*models.Message, error) {
 	var messages []*models.Message
 
-	query := r.DB.Where("timestamp >= ? AND timestamp <= ?", start, end).
+	query := r.DB.WithContext(ctx).Where("timestamp >= ? AND timestamp <= ?", start, end).
 		Order("timestamp DESC")
 
 	// Get total count
@@ -59,10 +62,10 @@ func (r *MessageRepository) FindByDateRange(start, end time.Time, pagination *ut
 }
 
 // FindByStatus finds messages by status
-func (r *MessageRepository) FindByStatus(status string, pagination *utils.Pagination) ([]*models.Message, error) {
+func (r *MessageRepository) FindByStatus(ctx context.Context, status string, pagination *utils.Pagination) ([]*models.Message, error) {
 	var messages []*models.Message
 
-	query := r.DB.Where("status = ?", status).
+	query := r.DB.WithContext(ctx).Where("status = ?", status).
 		Order("created_at DESC")
 
 	// Get total count
@@ -78,22 +81,25 @@ func (r *MessageRepository) FindByStatus(status string, pagination *utils.Pagina
 }
 
 // FindByWhatsAppMessageID finds a message by WhatsApp message ID
-func (r *MessageRepository) FindByWhatsAppMessageID(waMessageID string) (*models.Message, error) {
+func (r *MessageRepository) FindByWhatsAppMessageID(ctx context.Context, waMessageID string) (*models.Message, error) {
 	var message models.Message
-	err := r.DB.Where("whatsapp_message_id = ?", waMessageID).First(&message).Error
+	err := r.DB.WithContext(ctx).Where("whatsapp_message_id = ?", waMessageID).First(&message).Error
 	return &message, err
 }
 
 // Search performs full-text search on message content
-func (r *MessageRepository) Search(query string, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Message, error) {
+func (r *MessageRepository) Search(ctx context.Context, query string, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Message, error) {
 	var messages []*models.Message
 
-	dbQuery := r.DB.Where("content ILIKE ?", "%"+query+"%")
+	dbQuery := r.DB.WithContext(ctx).Where("content ILIKE ?", "%"+query+"%")
 
 	// Apply filters
 	if phone, ok := filters["phone"].(string); ok && phone != "" {
 		dbQuery = dbQuery.Where("from_number = ? OR to_number = ?", phone, phone)
 	}
+	if phoneNumberID, ok := filters["phone_number_id"].(string); ok && phoneNumberID != "" {
+		dbQuery = dbQuery.Where("phone_number_id = ?", phoneNumberID)
+	}
 	if direction, ok := filters["direction"].(string); ok && direction != "" {
 		dbQuery = dbQuery.Where("direction = ?", direction)
 	}
@@ -116,24 +122,27 @@ func (r *MessageRepository) Search(query string, filters map[string]interface{},
 }
 
 // CountByPhone counts messages for a phone number
-func (r *MessageRepository) CountByPhone(phone string) (int64, error) {
+func (r *MessageRepository) CountByPhone(ctx context.Context, phone string) (int64, error) {
 	var count int64
-	err := r.DB.Model(&models.Message{}).
+	err := r.DB.WithContext(ctx).Model(&models.Message{}).
 		Where("from_number = ? OR to_number = ?", phone, phone).
 		Count(&count).Error
 	return count, err
 }
 
 // ListWithFilters lists messages with various filters
-func (r *MessageRepository) ListWithFilters(filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Message, error) {
+func (r *MessageRepository) ListWithFilters(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Message, error) {
 	var messages []*models.Message
 
-	query := r.DB.Model(&models.Message{})
+	query := r.DB.WithContext(ctx).Model(&models.Message{})
 
 	// Apply filters
 	if phone, ok := filters["phone"].(string); ok && phone != "" {
 		query = query.Where("from_number = ? OR to_number = ?", phone, phone)
 	}
+	if phoneNumberID, ok := filters["phone_number_id"].(string); ok && phoneNumberID != "" {
+		query = query.Where("phone_number_id = ?", phoneNumberID)
+	}
 	if direction, ok := filters["direction"].(string); ok && direction != "" {
 		query = query.Where("direction = ?", direction)
 	}
@@ -164,9 +173,180 @@ func (r *MessageRepository) ListWithFilters(filters map[string]interface{}, pagi
 	return messages, err
 }
 
-// UpdateStatus updates the status of a message
-func (r *MessageRepository) UpdateStatus(whatsappMessageID, status string) error {
-	return r.DB.Model(&models.Message{}).
-		Where("whatsapp_message_id = ?", whatsappMessageID).
-		Update("status", status).Error
+// RecordStatusEvent stores a status update in the status history. It returns
+// false when the same status update was already recorded.
+func (r *MessageRepository) RecordStatusEvent(ctx context.Context, event *models.MessageStatusEvent) (bool, error) {
+	result := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "whatsapp_message_id"}, {Name: "status"}, {Name: "timestamp"}},
+		DoNothing: true,
+	}).Create(event)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ApplyPendingStatusEvents applies the recorded status updates that are not yet
+// linked to the message with the WhatsApp message ID, oldest first, and links
+// them. Updates can arrive before the message is stored, so they wait in the
+// history until it is. It returns how many were applied, or gorm.ErrRecordNotFound
+// if the message is not stored.
+func (r *MessageRepository) ApplyPendingStatusEvents(ctx context.Context, waMessageID string) (int, error) {
+	applied := 0
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var message models.Message
+		if err := tx.Where("whatsapp_message_id = ?", waMessageID).First(&message).Error; err != nil {
+			return err
+		}
+
+		var events []*models.MessageStatusEvent
+		if err := tx.Where("whatsapp_message_id = ? AND message_id IS NULL", waMessageID).
+			Order("timestamp ASC, created_at ASC").
+			Find(&events).Error; err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := applyStatusEvent(tx, message.ID, event); err != nil {
+				return err
+			}
+			if err := tx.Model(&models.MessageStatusEvent{}).
+				Where("id = ?", event.ID).
+				Update("message_id", message.ID).Error; err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// applyStatusEvent records when the message reached the status and moves the
+// message to it if the status machine allows. Both updates are conditional, so
+// applying the same event twice or out of order never regresses the message.
+func applyStatusEvent(tx *gorm.DB, messageID string, event *models.MessageStatusEvent) error {
+	if column := models.StatusTimestampColumn(event.Status); column != "" {
+		if err := tx.Model(&models.Message{}).
+			Where("id = ? AND "+column+" IS NULL", messageID).
+			Update(column, event.Timestamp).Error; err != nil {
+			return err
+		}
+	}
+
+	from := models.StatusesBefore(event.Status)
+	if len(from) == 0 {
+		return nil
+	}
+
+	updates := map[string]interface{}{"status": event.Status}
+	if event.Status == models.MessageStatusFailed {
+		updates["error_code"] = ""
+		if event.HasError() {
+			updates["error_code"] = strconv.Itoa(event.ErrorCode)
+		}
+		updates["error_message"] = event.FailureReason()
+	} else {
+		// Delivered or read after a failure: the message arrived after all
+		updates["error_code"] = ""
+		updates["error_message"] = ""
+	}
+	return tx.Model(&models.Message{}).
+		Where("id = ? AND status IN ?", messageID, from).
+		Updates(updates).Error
+}
+
+// ExistsByWhatsAppMessageID checks whether a message with the WhatsApp message ID is stored
+func (r *MessageRepository) ExistsByWhatsAppMessageID(ctx context.Context, waMessageID string) (bool, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.Message{}).
+		Where("whatsapp_message_id = ?", waMessageID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CreateInbound stores an inbound message unless one with the same WhatsApp message
+// ID exists, and in the same transaction updates the sender's contact: the last
+// message time and, when countsAsMessage, the message and unread counts. It returns
+// false without touching the contact when the message is a duplicate delivery.
+func (r *MessageRepository) CreateInbound(ctx context.Context, message *models.Message, countsAsMessage bool) (bool, error) {
+	created := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "whatsapp_message_id"}},
+			DoNothing: true,
+		}).Create(message)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		created = true
+
+		if !countsAsMessage {
+			return nil
+		}
+
+		// Deliveries can arrive out of order, so only move last_message_at forward
+		return tx.Model(&models.Contact{}).
+			Where("phone_number = ?", message.FromNumber).
+			Updates(map[string]interface{}{
+				"last_message_at": gorm.Expr("CASE WHEN last_message_at IS NULL OR last_message_at < ? THEN ? ELSE last_message_at END", message.Timestamp, message.Timestamp),
+				"message_count":   gorm.Expr("message_count + 1"),
+				"unread_count":    gorm.Expr("unread_count + 1"),
+				"updated_at":      time.Now().UTC(),
+			}).Error
+	})
+	return created, err
+}
+
+// FindReplies finds the replies and reactions linked to a message
+func (r *MessageRepository) FindReplies(ctx context.Context, messageID string) ([]*models.Message, error) {
+	var messages []*models.Message
+	err := r.DB.WithContext(ctx).Where("reply_to_id = ?", messageID).
+		Order("timestamp ASC").
+		Find(&messages).Error
+	return messages, err
+}
+
+// FindLatestInbound finds the most recent inbound message from a phone number to one of our numbers
+func (r *MessageRepository) FindLatestInbound(ctx context.Context, phone, phoneNumberID string) (*models.Message, error) {
+	var message models.Message
+	err := r.DB.WithContext(ctx).
+		Where("from_number = ? AND phone_number_id = ? AND direction = ?", phone, phoneNumberID, "inbound").
+		Order("timestamp DESC").
+		First(&message).Error
+	return &message, err
+}
+
+// FindInboundPhoneNumberIDs lists our phone number IDs that received messages from a phone number,
+// optionally only those with unread messages
+func (r *MessageRepository) FindInboundPhoneNumberIDs(ctx context.Context, phone string, unreadOnly bool) ([]string, error) {
+	query := r.DB.WithContext(ctx).Model(&models.Message{}).
+		Where("from_number = ? AND direction = ?", phone, "inbound")
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var phoneNumberIDs []string
+	err := query.Distinct().Pluck("phone_number_id", &phoneNumberIDs).Error
+	return phoneNumberIDs, err
+}
+
+// CountUnreadInbound counts the unread inbound messages from a phone number across all our numbers
+func (r *MessageRepository) CountUnreadInbound(ctx context.Context, phone string) (int64, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.Message{}).
+		Where("from_number = ? AND direction = ? AND read_at IS NULL", phone, "inbound").
+		Count(&count).Error
+	return count, err
+}
+
+// MarkInboundRead marks unread inbound messages from a phone number to one of our numbers
+// up to the given time as read
+func (r *MessageRepository) MarkInboundRead(ctx context.Context, phone, phoneNumberID string, upTo time.Time, readBy string) (int64, error) {
+	result := r.DB.WithContext(ctx).Model(&models.Message{}).
+		Where("from_number = ? AND phone_number_id = ? AND direction = ? AND timestamp <= ? AND read_at IS NULL",
+			phone, phoneNumberID, "inbound", upTo).
+		Updates(map[string]interface{}{
+			"status":  models.MessageStatusRead,
+			"read_at": time.Now().UTC(),
+			"read_by": readBy,
+		})
+	return result.RowsAffected, result.Error
 }
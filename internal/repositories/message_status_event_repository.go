@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"gorm.io/gorm"
+)
+
+// MessageStatusEventRepository handles message status history data access
+type MessageStatusEventRepository struct {
+	*BaseRepository
+}
+
+// NewMessageStatusEventRepository creates a new message status event repository
+func NewMessageStatusEventRepository(db *gorm.DB) *MessageStatusEventRepository {
+	return &MessageStatusEventRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// FindByWhatsAppMessageID finds the status history of a message, oldest first
+func (r *MessageStatusEventRepository) FindByWhatsAppMessageID(ctx context.Context, waMessageID string) ([]*models.MessageStatusEvent, error) {
+	var events []*models.MessageStatusEvent
+	err := r.DB.WithContext(ctx).Where("whatsapp_message_id = ?", waMessageID).
+		Order("timestamp ASC, created_at ASC").
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// OrderRepository handles order data access
+type OrderRepository struct {
+	*BaseRepository
+}
+
+// NewOrderRepository creates a new order repository
+func NewOrderRepository(db *gorm.DB) *OrderRepository {
+	return &OrderRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// FindWithItems finds an order by ID along with its line items
+func (r *OrderRepository) FindWithItems(ctx context.Context, orderID string) (*models.Order, error) {
+	var order models.Order
+	err := r.DB.WithContext(ctx).Preload("Items").Where("id = ?", orderID).First(&order).Error
+	return &order, err
+}
+
+// FindByMessageID finds the order placed in an inbound message
+func (r *OrderRepository) FindByMessageID(ctx context.Context, messageID string) (*models.Order, error) {
+	var order models.Order
+	err := r.DB.WithContext(ctx).Preload("Items").Where("message_id = ?", messageID).First(&order).Error
+	return &order, err
+}
+
+// ListWithFilters lists orders with their line items, newest first
+func (r *OrderRepository) ListWithFilters(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Order, error) {
+	var orders []*models.Order
+
+	query := r.DB.WithContext(ctx).Model(&models.Order{})
+
+	// Apply filters
+	for _, column := range []string{"status", "contact_id", "phone_number", "phone_number_id", "catalog_id"} {
+		if value, ok := filters[column].(string); ok && value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+
+	query = query.Order("placed_at DESC")
+
+	// Get total count
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	pagination.SetTotal(total)
+
+	// Apply pagination
+	err := pagination.ApplyToQuery(query).Preload("Items").Find(&orders).Error
+	return orders, err
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// PhoneNumberRepository handles phone number data access
+type PhoneNumberRepository struct {
+	*BaseRepository
+}
+
+// NewPhoneNumberRepository creates a new phone number repository
+func NewPhoneNumberRepository(db *gorm.DB) *PhoneNumberRepository {
+	return &PhoneNumberRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// FindByWhatsAppPhoneNumberID finds a phone number by its WhatsApp phone number ID
+func (r *PhoneNumberRepository) FindByWhatsAppPhoneNumberID(ctx context.Context, waPhoneNumberID string) (*models.PhoneNumber, error) {
+	var phoneNumber models.PhoneNumber
+	err := r.DB.WithContext(ctx).Where("whatsapp_phone_number_id = ?", waPhoneNumberID).First(&phoneNumber).Error
+	return &phoneNumber, err
+}
+
+// ListAll lists all phone numbers ordered by display number
+func (r *PhoneNumberRepository) ListAll(ctx context.Context) ([]*models.PhoneNumber, error) {
+	var phoneNumbers []*models.PhoneNumber
+	err := r.DB.WithContext(ctx).Order("display_phone_number ASC").Find(&phoneNumbers).Error
+	return phoneNumbers, err
+}
+
+// ListSnapshots lists the recorded history of a phone number, newest first
+func (r *PhoneNumberRepository) ListSnapshots(ctx context.Context, phoneNumberID string, pagination *utils.Pagination) ([]*models.PhoneNumberSnapshot, error) {
+	var snapshots []*models.PhoneNumberSnapshot
+
+	query := r.DB.WithContext(ctx).Model(&models.PhoneNumberSnapshot{}).
+		Where("phone_number_id = ?", phoneNumberID).
+		Order("recorded_at DESC")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	pagination.SetTotal(total)
+
+	err := pagination.ApplyToQuery(query).Find(&snapshots).Error
+	return snapshots, err
+}
+
+// SaveWithSnapshot creates or updates a phone number and records a snapshot in one transaction
+func (r *PhoneNumberRepository) SaveWithSnapshot(ctx context.Context, phoneNumber *models.PhoneNumber, snapshot *models.PhoneNumberSnapshot) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(phoneNumber).Error; err != nil {
+			return err
+		}
+		if snapshot == nil {
+			return nil
+		}
+		snapshot.PhoneNumberID = phoneNumber.ID
+		return tx.Create(snapshot).Error
+	})
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/ashok/vibecoded-wa-client/internal/models"
 	"github.com/ashok/vibecoded-wa-client/pkg/utils"
 	"gorm.io/gorm"
@@ -19,17 +21,17 @@ func NewTemplateRepository(db *gorm.DB) *TemplateRepository {
 }
 
 // FindByName finds a template by name and language
-func (r *TemplateRepository) FindByName(name, language string) (*models.Template, error) {
+func (r *TemplateRepository) FindByName(ctx context.Context, name, language string) (*models.Template, error) {
 	var template models.Template
-	err := r.DB.Where("name = ? AND language = ?", name, language).First(&template).Error
+	err := r.DB.WithContext(ctx).Where("name = ? AND language = ?", name, language).First(&template).Error
 	return &template, err
 }
 
 // FindByCategory finds templates by category
-func (r *TemplateRepository) FindByCategory(category string, pagination *utils.Pagination) ([]*models.Template, error) {
+func (r *TemplateRepository) FindByCategory(ctx context.Context, category string, pagination *utils.Pagination) ([]*models.Template, error) {
 	var templates []*models.Template
 
-	query := r.DB.Where("category = ?", category).Order("created_at DESC")
+	query := r.DB.WithContext(ctx).Where("category = ?", category).Order("created_at DESC")
 
 	var total int64
 	if err := query.Model(&models.Template{}).Count(&total).Error; err != nil {
@@ -42,10 +44,10 @@ func (r *TemplateRepository) FindByCategory(category string, pagination *utils.P
 }
 
 // FindByStatus finds templates by status
-func (r *TemplateRepository) FindByStatus(status string, pagination *utils.Pagination) ([]*models.Template, error) {
+func (r *TemplateRepository) FindByStatus(ctx context.Context, status string, pagination *utils.Pagination) ([]*models.Template, error) {
 	var templates []*models.Template
 
-	query := r.DB.Where("status = ?", status).Order("created_at DESC")
+	query := r.DB.WithContext(ctx).Where("status = ?", status).Order("created_at DESC")
 
 	var total int64
 	if err := query.Model(&models.Template{}).Count(&total).Error; err != nil {
@@ -58,15 +60,15 @@ func (r *TemplateRepository) FindByStatus(status string, pagination *utils.Pagin
 }
 
 // FindApproved finds all approved templates
-func (r *TemplateRepository) FindApproved(pagination *utils.Pagination) ([]*models.Template, error) {
-	return r.FindByStatus(models.TemplateStatusApproved, pagination)
+func (r *TemplateRepository) FindApproved(ctx context.Context, pagination *utils.Pagination) ([]*models.Template, error) {
+	return r.FindByStatus(ctx, models.TemplateStatusApproved, pagination)
 }
 
 // ListAll lists all templates with pagination
-func (r *TemplateRepository) ListAll(pagination *utils.Pagination) ([]*models.Template, error) {
+func (r *TemplateRepository) ListAll(ctx context.Context, pagination *utils.Pagination) ([]*models.Template, error) {
 	var templates []*models.Template
 
-	query := r.DB.Model(&models.Template{}).Order("created_at DESC")
+	query := r.DB.WithContext(ctx).Model(&models.Template{}).Order("created_at DESC")
 
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -77,3 +79,17 @@ func (r *TemplateRepository) ListAll(pagination *utils.Pagination) ([]*models.Te
 	err := pagination.ApplyToQuery(query).Find(&templates).Error
 	return templates, err
 }
+
+// FindByWhatsAppTemplateID finds a template by its WhatsApp template ID
+func (r *TemplateRepository) FindByWhatsAppTemplateID(ctx context.Context, waTemplateID string) (*models.Template, error) {
+	var template models.Template
+	err := r.DB.WithContext(ctx).Where("whatsapp_template_id = ?", waTemplateID).First(&template).Error
+	return &template, err
+}
+
+// FindSynced finds all templates that exist in the WhatsApp Business Account
+func (r *TemplateRepository) FindSynced(ctx context.Context) ([]*models.Template, error) {
+	var templates []*models.Template
+	err := r.DB.WithContext(ctx).Where("whatsapp_template_id <> ''").Find(&templates).Error
+	return templates, err
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// WebhookEventRepository handles webhook event data access
+type WebhookEventRepository struct {
+	*BaseRepository
+}
+
+// NewWebhookEventRepository creates a new webhook event repository
+func NewWebhookEventRepository(db *gorm.DB) *WebhookEventRepository {
+	return &WebhookEventRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// FindDue finds pending events and failed events whose retry is due, oldest first
+func (r *WebhookEventRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*models.WebhookEvent, error) {
+	var events []*models.WebhookEvent
+	err := r.DB.WithContext(ctx).
+		Where("status = ? OR (status = ? AND next_attempt_at <= ?)",
+			models.WebhookEventStatusPending, models.WebhookEventStatusFailed, now).
+		Order("received_at ASC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// Claim atomically moves a pending event, or a failed event whose retry is due,
+// to processing. It returns false if another worker already claimed it or its
+// retry is not due yet.
+func (r *WebhookEventRepository) Claim(ctx context.Context, id string, now time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.WebhookEvent{}).
+		Where("id = ? AND (status = ? OR (status = ? AND next_attempt_at <= ?))",
+			id, models.WebhookEventStatusPending, models.WebhookEventStatusFailed, now).
+		Updates(map[string]interface{}{
+			"status":     models.WebhookEventStatusProcessing,
+			"updated_at": time.Now().UTC(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReclaimStale returns events that have been claimed since before the cutoff to
+// pending. Such claims belong to a worker that crashed or was restarted; recent
+// claims may still be running on another instance.
+func (r *WebhookEventRepository) ReclaimStale(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Model(&models.WebhookEvent{}).
+		Where("status = ? AND updated_at < ?", models.WebhookEventStatusProcessing, cutoff).
+		Updates(map[string]interface{}{
+			"status":     models.WebhookEventStatusPending,
+			"updated_at": time.Now().UTC(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// Requeue moves an event back to pending with a fresh attempt budget.
+// It returns false if the event is currently being processed.
+func (r *WebhookEventRepository) Requeue(ctx context.Context, id string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.WebhookEvent{}).
+		Where("id = ? AND status <> ?", id, models.WebhookEventStatusProcessing).
+		Updates(requeueFields())
+	return result.RowsAffected == 1, result.Error
+}
+
+// RequeueByStatus moves every event in the given status back to pending and
+// returns their IDs, oldest first
+func (r *WebhookEventRepository) RequeueByStatus(ctx context.Context, status string) ([]string, error) {
+	var ids []string
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.WebhookEvent{}).
+			Where("status = ?", status).
+			Order("received_at ASC").
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&models.WebhookEvent{}).
+			Where("id IN ? AND status = ?", ids, status).
+			Updates(requeueFields()).Error
+	})
+	return ids, err
+}
+
+func requeueFields() map[string]interface{} {
+	return map[string]interface{}{
+		"status":          models.WebhookEventStatusPending,
+		"attempts":        0,
+		"next_attempt_at": nil,
+		"updated_at":      time.Now().UTC(),
+	}
+}
+
+// ListWithFilters lists webhook events, newest first
+func (r *WebhookEventRepository) ListWithFilters(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.WebhookEvent, error) {
+	var events []*models.WebhookEvent
+
+	query := r.DB.WithContext(ctx).Model(&models.WebhookEvent{})
+
+	// Apply filters
+	for _, column := range []string{"status", "object"} {
+		if value, ok := filters[column].(string); ok && value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+
+	query = query.Order("received_at DESC")
+
+	// Get total count
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	pagination.SetTotal(total)
+
+	// Apply pagination
+	err := pagination.ApplyToQuery(query).Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// WebhookRejectionRepository handles webhook security log data access
+type WebhookRejectionRepository struct {
+	*BaseRepository
+}
+
+// NewWebhookRejectionRepository creates a new webhook rejection repository
+func NewWebhookRejectionRepository(db *gorm.DB) *WebhookRejectionRepository {
+	return &WebhookRejectionRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// DeleteOlderThan removes security log entries created before the cutoff
+func (r *WebhookRejectionRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&models.WebhookRejection{})
+	return result.RowsAffected, result.Error
+}
+
+// ListWithFilters lists rejected webhook requests, newest first
+func (r *WebhookRejectionRepository) ListWithFilters(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.WebhookRejection, error) {
+	var rejections []*models.WebhookRejection
+
+	query := r.DB.WithContext(ctx).Model(&models.WebhookRejection{})
+
+	// Apply filters
+	for _, column := range []string{"reason", "source_ip"} {
+		if value, ok := filters[column].(string); ok && value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+
+	query = query.Order("created_at DESC")
+
+	// Get total count
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	pagination.SetTotal(total)
+
+	// Apply pagination
+	err := pagination.ApplyToQuery(query).Find(&rejections).Error
+	return rejections, err
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"time"
 
 	"github.com/ashok/vibecoded-wa-client/internal/models"
@@ -22,7 +23,7 @@ func NewAuthService(apiKeyRepo *repositories.APIKeyRepository) *AuthService {
 }
 
 // CreateAPIKey creates a new API key
-func (s *AuthService) CreateAPIKey(name string, permissions []string, expiresAt *time.Time) (*models.APIKey, string, error) {
+func (s *AuthService) CreateAPIKey(ctx context.Context, name string, permissions []string, expiresAt *time.Time) (*models.APIKey, string, error) {
 	// Generate API key
 	rawKey, err := utils.GenerateAPIKey()
 	if err != nil {
@@ -44,7 +45,7 @@ func (s *AuthService) CreateAPIKey(name string, permissions []string, expiresAt
 		ExpiresAt:   expiresAt,
 	}
 
-	if err := s.apiKeyRepo.Create(apiKey); err != nil {
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
 		return nil, "", errors.NewDatabaseError(err)
 	}
 
@@ -53,12 +54,12 @@ func (s *AuthService) CreateAPIKey(name string, permissions []string, expiresAt
 }
 
 // ValidateAPIKey validates an API key and returns the key info
-func (s *AuthService) ValidateAPIKey(rawKey string) (*models.APIKey, error) {
+func (s *AuthService) ValidateAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error) {
 	// Get key prefix for optimization
 	prefix := utils.GetKeyPrefix(rawKey)
 
 	// Find keys with matching prefix
-	apiKeys, err := s.apiKeyRepo.FindByKeyPrefix(prefix)
+	apiKeys, err := s.apiKeyRepo.FindByKeyPrefix(ctx, prefix)
 	if err != nil {
 		return nil, errors.NewUnauthorized("Invalid API key")
 	}
@@ -72,7 +73,7 @@ func (s *AuthService) ValidateAPIKey(rawKey string) (*models.APIKey, error) {
 			}
 
 			// Update last used
-			s.apiKeyRepo.UpdateLastUsed(apiKey.ID)
+			s.apiKeyRepo.UpdateLastUsed(ctx, apiKey.ID)
 
 			return apiKey, nil
 		}
@@ -82,16 +83,16 @@ func (s *AuthService) ValidateAPIKey(rawKey string) (*models.APIKey, error) {
 }
 
 // RevokeAPIKey revokes an API key
-func (s *AuthService) RevokeAPIKey(keyID string) error {
+func (s *AuthService) RevokeAPIKey(ctx context.Context, keyID string) error {
 	var apiKey models.APIKey
-	if err := s.apiKeyRepo.FindByID(keyID, &apiKey); err != nil {
+	if err := s.apiKeyRepo.FindByID(ctx, keyID, &apiKey); err != nil {
 		return errors.NewNotFound("API Key", keyID)
 	}
 
-	return s.apiKeyRepo.Delete(&apiKey)
+	return s.apiKeyRepo.Delete(ctx, &apiKey)
 }
 
 // ListAPIKeys lists all API keys
-func (s *AuthService) ListAPIKeys() ([]*models.APIKey, error) {
-	return s.apiKeyRepo.ListAll()
+func (s *AuthService) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	return s.apiKeyRepo.ListAll(ctx)
 }
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	_ "image/jpeg" // register decoders used to check profile picture dimensions
+	_ "image/png"
+	"io"
+	"net/http"
+
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// minProfilePictureSize is the smallest width and height WhatsApp accepts for a profile picture
+const minProfilePictureSize = 192
+
+// BusinessProfileService manages the WhatsApp business profile
+type BusinessProfileService struct {
+	waClient *whatsapp.Client
+	logger   *zap.Logger
+}
+
+// NewBusinessProfileService creates a new business profile service
+func NewBusinessProfileService(waClient *whatsapp.Client, logger *zap.Logger) *BusinessProfileService {
+	return &BusinessProfileService{
+		waClient: waClient,
+		logger:   logger,
+	}
+}
+
+// GetProfile returns the current business profile
+func (s *BusinessProfileService) GetProfile(ctx context.Context) (*whatsapp.BusinessProfile, error) {
+	return s.waClient.GetBusinessProfile(ctx)
+}
+
+// UpdateProfile applies a partial update and returns the resulting profile
+func (s *BusinessProfileService) UpdateProfile(ctx context.Context, update *whatsapp.BusinessProfileUpdate) (*whatsapp.BusinessProfile, error) {
+	if update.IsEmpty() {
+		return nil, errors.NewBadRequest("No profile fields to update")
+	}
+	if err := update.Validate(); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	if err := s.waClient.UpdateBusinessProfile(ctx, update); err != nil {
+		return nil, err
+	}
+
+	return s.waClient.GetBusinessProfile(ctx)
+}
+
+// UpdateProfilePicture uploads a new profile picture and returns the resulting profile
+func (s *BusinessProfileService) UpdateProfilePicture(ctx context.Context, filename string, r io.Reader) (*whatsapp.BusinessProfile, error) {
+	if !s.waClient.HasApp() {
+		return nil, errors.NewBadRequest("Profile picture uploads require WHATSAPP_APP_ID to be configured")
+	}
+
+	// Read one extra byte to detect content over the limit
+	data, err := io.ReadAll(io.LimitReader(r, whatsapp.MaxProfilePictureSize+1))
+	if err != nil {
+		return nil, errors.NewBadRequest("Failed to read uploaded file")
+	}
+	if len(data) > whatsapp.MaxProfilePictureSize {
+		return nil, errors.NewBadRequest("Profile picture must be at most 5 MB")
+	}
+
+	// Trust the content rather than the client-supplied type
+	mimeType := http.DetectContentType(data)
+	if mimeType != "image/jpeg" && mimeType != "image/png" {
+		return nil, errors.NewBadRequest("Profile picture must be a JPEG or PNG image")
+	}
+
+	img, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.NewBadRequest("Profile picture is not a valid image")
+	}
+	if img.Width < minProfilePictureSize || img.Height < minProfilePictureSize {
+		return nil, errors.NewBadRequest("Profile picture must be at least 192x192 pixels")
+	}
+
+	handle, err := s.waClient.UploadProfilePicture(ctx, filename, mimeType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.waClient.UpdateBusinessProfile(ctx, &whatsapp.BusinessProfileUpdate{ProfilePictureHandle: handle}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Business profile picture updated", zap.Int("size", len(data)))
+
+	return s.waClient.GetBusinessProfile(ctx)
+}
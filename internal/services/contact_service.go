@@ -1,8 +1,11 @@
 package services
 
 import (
+	"context"
+
 	"github.com/ashok/vibecoded-wa-client/internal/models"
 	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
 	"github.com/ashok/vibecoded-wa-client/pkg/errors"
 	"github.com/ashok/vibecoded-wa-client/pkg/utils"
 )
@@ -10,27 +13,29 @@ import (
 // ContactService handles contact business logic
 type ContactService struct {
 	contactRepo *repositories.ContactRepository
+	senders     *whatsapp.Registry
 }
 
 // NewContactService creates a new contact service
-func NewContactService(contactRepo *repositories.ContactRepository) *ContactService {
+func NewContactService(contactRepo *repositories.ContactRepository, senders *whatsapp.Registry) *ContactService {
 	return &ContactService{
 		contactRepo: contactRepo,
+		senders:     senders,
 	}
 }
 
 // GetContact gets a contact by ID
-func (s *ContactService) GetContact(contactID string) (*models.Contact, error) {
+func (s *ContactService) GetContact(ctx context.Context, contactID string) (*models.Contact, error) {
 	var contact models.Contact
-	if err := s.contactRepo.FindByID(contactID, &contact); err != nil {
+	if err := s.contactRepo.FindByID(ctx, contactID, &contact); err != nil {
 		return nil, errors.NewNotFound("Contact", contactID)
 	}
 	return &contact, nil
 }
 
 // GetContactByPhone gets a contact by phone number
-func (s *ContactService) GetContactByPhone(phone string) (*models.Contact, error) {
-	contact, err := s.contactRepo.FindByPhone(phone)
+func (s *ContactService) GetContactByPhone(ctx context.Context, phone string) (*models.Contact, error) {
+	contact, err := s.contactRepo.FindByPhone(ctx, phone)
 	if err != nil {
 		return nil, errors.NewNotFound("Contact", phone)
 	}
@@ -38,28 +43,34 @@ func (s *ContactService) GetContactByPhone(phone string) (*models.Contact, error
 }
 
 // ListContacts lists all contacts with pagination and filters
-func (s *ContactService) ListContacts(filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Contact, error) {
-	return s.contactRepo.ListWithFilters(filters, pagination)
+func (s *ContactService) ListContacts(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Contact, error) {
+	if err := applySenderFilter(s.senders, filters); err != nil {
+		return nil, err
+	}
+	return s.contactRepo.ListWithFilters(ctx, filters, pagination)
 }
 
 // SearchContacts searches contacts by name or phone
-func (s *ContactService) SearchContacts(query string, pagination *utils.Pagination) ([]*models.Contact, error) {
-	return s.contactRepo.Search(query, pagination)
+func (s *ContactService) SearchContacts(ctx context.Context, query string, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Contact, error) {
+	if err := applySenderFilter(s.senders, filters); err != nil {
+		return nil, err
+	}
+	return s.contactRepo.Search(ctx, query, filters, pagination)
 }
 
 // UpdateContact updates contact information
-func (s *ContactService) UpdateContact(contactID string, updates map[string]interface{}) (*models.Contact, error) {
+func (s *ContactService) UpdateContact(ctx context.Context, contactID string, updates map[string]interface{}) (*models.Contact, error) {
 	var contact models.Contact
-	if err := s.contactRepo.FindByID(contactID, &contact); err != nil {
+	if err := s.contactRepo.FindByID(ctx, contactID, &contact); err != nil {
 		return nil, errors.NewNotFound("Contact", contactID)
 	}
 
-	if err := s.contactRepo.UpdateFields(contactID, &contact, updates); err != nil {
+	if err := s.contactRepo.UpdateFields(ctx, contactID, &contact, updates); err != nil {
 		return nil, errors.NewDatabaseError(err)
 	}
 
 	// Fetch updated contact
-	if err := s.contactRepo.FindByID(contactID, &contact); err != nil {
+	if err := s.contactRepo.FindByID(ctx, contactID, &contact); err != nil {
 		return nil, errors.NewDatabaseError(err)
 	}
 
@@ -67,6 +78,6 @@ func (s *ContactService) UpdateContact(contactID string, updates map[string]inte
 }
 
 // GetOrCreateContact gets an existing contact or creates a new one
-func (s *ContactService) GetOrCreateContact(phone string) (*models.Contact, error) {
-	return s.contactRepo.GetOrCreate(phone)
+func (s *ContactService) GetOrCreateContact(ctx context.Context, phone string) (*models.Contact, error) {
+	return s.contactRepo.GetOrCreate(ctx, phone)
 }
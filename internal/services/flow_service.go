@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FlowService records and queries WhatsApp Flow responses
+type FlowService struct {
+	flowResponseRepo *repositories.FlowResponseRepository
+	messageRepo      *repositories.MessageRepository
+	senders          *whatsapp.Registry
+	logger           *zap.Logger
+}
+
+// NewFlowService creates a new flow service
+func NewFlowService(
+	flowResponseRepo *repositories.FlowResponseRepository,
+	messageRepo *repositories.MessageRepository,
+	senders *whatsapp.Registry,
+	logger *zap.Logger,
+) *FlowService {
+	return &FlowService{
+		flowResponseRepo: flowResponseRepo,
+		messageRepo:      messageRepo,
+		senders:          senders,
+		logger:           logger,
+	}
+}
+
+// RecordResponse stores a completed flow received in an inbound message. The flow
+// is identified through the flow message the user replied to. Recording the same
+// message again returns the stored response, so a redelivered webhook is safe.
+func (s *FlowService) RecordResponse(ctx context.Context, message *models.Message, contact *models.Contact, reply *whatsapp.FlowReply) (*models.FlowResponse, error) {
+	if existing, err := s.flowResponseRepo.FindByMessageID(ctx, message.ID); err == nil {
+		return existing, nil
+	} else if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	response := &models.FlowResponse{
+		MessageID:     message.ID,
+		ContactID:     contact.ID,
+		PhoneNumber:   contact.PhoneNumber,
+		PhoneNumberID: message.PhoneNumberID,
+		FlowToken:     reply.FlowToken,
+		Name:          reply.Name,
+		Body:          reply.Body,
+		Response:      models.JSONMap(reply.Response),
+		ReceivedAt:    message.Timestamp,
+	}
+
+	if message.ReplyToID != nil {
+		var flowMessage models.Message
+		if err := s.messageRepo.FindByID(ctx, *message.ReplyToID, &flowMessage); err == nil {
+			response.FlowMessageID = &flowMessage.ID
+			response.FlowID, _ = flowMessage.Metadata["flow_id"].(string)
+			response.FlowName, _ = flowMessage.Metadata["flow_name"].(string)
+			if response.FlowToken == "" {
+				response.FlowToken, _ = flowMessage.Metadata["flow_token"].(string)
+			}
+		}
+	}
+
+	if err := s.flowResponseRepo.Create(ctx, response); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	s.logger.Info("Flow response recorded",
+		zap.String("flow_response_id", response.ID),
+		zap.String("flow_id", response.FlowID),
+		zap.String("phone", response.PhoneNumber),
+	)
+
+	return response, nil
+}
+
+// GetFlowResponse retrieves a flow response by ID
+func (s *FlowService) GetFlowResponse(ctx context.Context, responseID string) (*models.FlowResponse, error) {
+	var response models.FlowResponse
+	if err := s.flowResponseRepo.FindByID(ctx, responseID, &response); err != nil {
+		return nil, errors.NewNotFound("Flow response", responseID)
+	}
+	return &response, nil
+}
+
+// ListFlowResponses lists flow responses with filters and pagination
+func (s *FlowService) ListFlowResponses(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.FlowResponse, error) {
+	if err := applySenderFilter(s.senders, filters); err != nil {
+		return nil, err
+	}
+
+	responses, err := s.flowResponseRepo.ListWithFilters(ctx, filters, pagination)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	return responses, nil
+}
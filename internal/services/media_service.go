@@ -0,0 +1,500 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/storage"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Download pipeline settings
+const (
+	mediaDownloadWorkers  = 2
+	mediaDownloadQueue    = 100
+	mediaDownloadAttempts = 3
+	mediaDownloadBackoff  = 2 * time.Second
+	mediaSweepInterval    = 5 * time.Minute
+	mediaSweepBatch       = 50
+	mediaDownloadTimeout  = 3 * time.Minute
+	mediaStatusTimeout    = 5 * time.Second
+
+	// mediaDownloadStaleAfter is how long a download may stay claimed before it
+	// is assumed abandoned: every attempt timing out plus a margin for backoff
+	mediaDownloadStaleAfter = mediaDownloadAttempts*mediaDownloadTimeout + 5*time.Minute
+)
+
+// MediaService handles media business logic
+type MediaService struct {
+	mediaRepo *repositories.MediaRepository
+	senders   *whatsapp.Registry
+	storage   storage.Storage
+	logger    *zap.Logger
+
+	queue  chan string
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewMediaService creates a new media service
+func NewMediaService(
+	mediaRepo *repositories.MediaRepository,
+	senders *whatsapp.Registry,
+	store storage.Storage,
+	logger *zap.Logger,
+) *MediaService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MediaService{
+		mediaRepo: mediaRepo,
+		senders:   senders,
+		storage:   store,
+		logger:    logger,
+		queue:     make(chan string, mediaDownloadQueue),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// UploadMedia uploads a file to WhatsApp and stores a local record of it. Uploaded
+// media can only be sent from the sender phone number it was uploaded with.
+func (s *MediaService) UploadMedia(ctx context.Context, from, filename, mimeType string, r io.Reader, uploadedBy string) (*models.Media, error) {
+	sender, ok := s.senders.Get(from)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("unknown sender %q", from))
+	}
+
+	// Read the file, allowing one extra byte to detect oversized uploads
+	data, err := io.ReadAll(io.LimitReader(r, whatsapp.MaxDocumentSize+1))
+	if err != nil {
+		return nil, errors.NewBadRequest("failed to read uploaded file: " + err.Error())
+	}
+	if len(data) == 0 {
+		return nil, errors.NewBadRequest("uploaded file is empty")
+	}
+
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	// Enforce WhatsApp size limits for the media type
+	mediaType := whatsapp.MediaTypeForMime(mimeType)
+	if maxSize := whatsapp.MaxMediaSize(mediaType); int64(len(data)) > maxSize {
+		return nil, errors.NewBadRequestWithDetails(
+			fmt.Sprintf("%s files must not exceed %d MB", mediaType, maxSize>>20),
+			map[string]interface{}{"size": len(data), "max_size": maxSize},
+		)
+	}
+
+	sum := sha256.Sum256(data)
+
+	// Upload to WhatsApp
+	waMediaID, err := sender.Client.UploadMedia(ctx, filename, mimeType, bytes.NewReader(data))
+	if err != nil {
+		s.logger.Error("Failed to upload media to WhatsApp", zap.Error(err))
+		return nil, err
+	}
+
+	// Create media record
+	expiresAt := time.Now().UTC().Add(models.MediaRetention)
+	media := &models.Media{
+		WhatsAppMediaID: waMediaID,
+		PhoneNumberID:   sender.PhoneNumberID(),
+		Direction:       models.MediaDirectionOutbound,
+		Status:          models.MediaStatusUploaded,
+		Filename:        filename,
+		MimeType:        mimeType,
+		Size:            int64(len(data)),
+		SHA256:          hex.EncodeToString(sum[:]),
+		UploadedBy:      uploadedBy,
+		ExpiresAt:       &expiresAt,
+	}
+
+	if err := s.mediaRepo.Create(ctx, media); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	// Keep a copy so sent media can be displayed; WhatsApp already has the file
+	if key, err := s.store(ctx, media, data); err != nil {
+		s.logger.Warn("Failed to store uploaded media", zap.Error(err), zap.String("media_id", media.ID))
+	} else {
+		media.Status = models.MediaStatusStored
+		media.StorageKey = key
+		s.mediaRepo.UpdateFields(ctx, media.ID, &models.Media{}, map[string]interface{}{
+			"status":      media.Status,
+			"storage_key": media.StorageKey,
+		})
+	}
+
+	s.logger.Info("Media uploaded",
+		zap.String("media_id", media.ID),
+		zap.String("whatsapp_media_id", waMediaID),
+		zap.Int64("size", media.Size),
+	)
+
+	return media, nil
+}
+
+// GetMedia gets a media record by ID
+func (s *MediaService) GetMedia(ctx context.Context, mediaID string) (*models.Media, error) {
+	var media models.Media
+	if err := s.mediaRepo.FindByID(ctx, mediaID, &media); err != nil {
+		return nil, errors.NewNotFound("Media", mediaID)
+	}
+	return &media, nil
+}
+
+// OpenMediaContent opens the stored content of a media record.
+// The caller must close the returned reader.
+func (s *MediaService) OpenMediaContent(ctx context.Context, mediaID string) (*models.Media, io.ReadCloser, *storage.ObjectInfo, error) {
+	media, err := s.storedMedia(ctx, mediaID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	reader, info, err := s.storage.Get(ctx, media.StorageKey)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			s.logger.Error("Stored media object is missing", zap.String("media_id", mediaID))
+			return nil, nil, nil, errors.NewNotFound("Media content", mediaID)
+		}
+		return nil, nil, nil, errors.NewInternalError(err)
+	}
+
+	return media, reader, info, nil
+}
+
+// PresignMediaURL returns a temporary direct download URL for stored media.
+// It returns storage.ErrPresignNotSupported for backends that cannot presign.
+func (s *MediaService) PresignMediaURL(ctx context.Context, mediaID string, expires time.Duration) (string, error) {
+	media, err := s.storedMedia(ctx, mediaID)
+	if err != nil {
+		return "", err
+	}
+
+	url, err := s.storage.PresignGet(ctx, media.StorageKey, expires)
+	if err != nil && err != storage.ErrPresignNotSupported {
+		return "", errors.NewInternalError(err)
+	}
+	return url, err
+}
+
+// storedMedia returns a media record whose content is in storage
+func (s *MediaService) storedMedia(ctx context.Context, mediaID string) (*models.Media, error) {
+	media, err := s.GetMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch media.Status {
+	case models.MediaStatusPending, models.MediaStatusDownloading:
+		return nil, errors.NewConflict("media is still being downloaded")
+	case models.MediaStatusFailed:
+		return nil, errors.NewNotFound("Media content", mediaID).WithDetail("last_error", media.LastError)
+	}
+
+	if !media.IsStored() {
+		return nil, errors.NewNotFound("Media content", mediaID)
+	}
+
+	return media, nil
+}
+
+// RegisterInboundMedia records media received in a webhook so it can be downloaded.
+// Call EnqueueDownload once the owning message has been saved.
+func (s *MediaService) RegisterInboundMedia(ctx context.Context, event *whatsapp.MessageEvent) (*models.Media, error) {
+	media := &models.Media{
+		WhatsAppMediaID: event.MediaID,
+		PhoneNumberID:   event.PhoneNumberID,
+		Direction:       models.MediaDirectionInbound,
+		Status:          models.MediaStatusPending,
+		Filename:        event.Filename,
+		MimeType:        event.MimeType,
+		SHA256:          event.SHA256,
+	}
+	if media.MimeType == "" {
+		media.MimeType = "application/octet-stream"
+	}
+
+	if err := s.mediaRepo.Create(ctx, media); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	return media, nil
+}
+
+// DiscardInboundMedia removes a media record registered for a message that turned
+// out to be a duplicate delivery, before its download is scheduled
+func (s *MediaService) DiscardInboundMedia(ctx context.Context, media *models.Media) {
+	if err := s.mediaRepo.Delete(ctx, media); err != nil {
+		s.logger.Error("Failed to discard inbound media", zap.Error(err), zap.String("media_id", media.ID))
+	}
+}
+
+// EnqueueDownload links inbound media to its message and schedules the download.
+// If the queue is full the record stays pending and is picked up by the next sweep.
+func (s *MediaService) EnqueueDownload(ctx context.Context, mediaID, messageID string) {
+	if err := s.mediaRepo.UpdateFields(ctx, mediaID, &models.Media{}, map[string]interface{}{
+		"message_id": messageID,
+	}); err != nil {
+		s.logger.Error("Failed to link media to message", zap.Error(err), zap.String("media_id", mediaID))
+	}
+
+	select {
+	case s.queue <- mediaID:
+	default:
+		s.logger.Warn("Media download queue full, deferring to sweep", zap.String("media_id", mediaID))
+	}
+}
+
+// StartWorkers starts the background download workers
+func (s *MediaService) StartWorkers() {
+	for i := 0; i < mediaDownloadWorkers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+
+	s.wg.Add(1)
+	go s.sweeper()
+}
+
+// StopWorkers cancels in-flight downloads and waits for the workers to exit.
+// Interrupted downloads are left pending and resume on the next start.
+func (s *MediaService) StopWorkers() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// worker downloads queued media until stopped
+func (s *MediaService) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case mediaID := <-s.queue:
+			s.processDownload(s.ctx, mediaID)
+		}
+	}
+}
+
+// sweeper periodically reclaims abandoned downloads and re-queues pending media
+// that never made it onto the queue
+func (s *MediaService) sweeper() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(mediaSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		s.sweepPending(s.ctx)
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *MediaService) sweepPending(ctx context.Context) {
+	s.reclaimStale(ctx)
+
+	pending, err := s.mediaRepo.FindByStatus(ctx, models.MediaStatusPending, mediaSweepBatch)
+	if err != nil {
+		s.logger.Error("Failed to list pending media downloads", zap.Error(err))
+		return
+	}
+	for _, media := range pending {
+		select {
+		case s.queue <- media.ID:
+		default:
+			return
+		}
+	}
+}
+
+// reclaimStale returns downloads abandoned by a crashed or restarted worker to
+// pending. Only old claims are reclaimed, so downloads still running on another
+// instance are left alone.
+func (s *MediaService) reclaimStale(ctx context.Context) {
+	n, err := s.mediaRepo.ReclaimStaleDownloads(ctx, time.Now().UTC().Add(-mediaDownloadStaleAfter))
+	if err != nil {
+		s.logger.Error("Failed to reclaim abandoned media downloads", zap.Error(err))
+	} else if n > 0 {
+		s.logger.Info("Resumed abandoned media downloads", zap.Int64("count", n))
+	}
+}
+
+// processDownload claims a pending media record and downloads it with retries
+func (s *MediaService) processDownload(ctx context.Context, mediaID string) {
+	claimed, err := s.mediaRepo.ClaimForDownload(ctx, mediaID)
+	if err != nil {
+		s.logger.Error("Failed to claim media download", zap.Error(err), zap.String("media_id", mediaID))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	var media models.Media
+	if err := s.mediaRepo.FindByID(ctx, mediaID, &media); err != nil {
+		s.logger.Error("Failed to load media record", zap.Error(err), zap.String("media_id", mediaID))
+		return
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= mediaDownloadAttempts; attempt++ {
+		media.Attempts++
+
+		attemptCtx, cancel := context.WithTimeout(ctx, mediaDownloadTimeout)
+		lastErr = s.download(attemptCtx, &media)
+		cancel()
+		if lastErr == nil {
+			break
+		}
+
+		s.logger.Warn("Media download attempt failed",
+			zap.Error(lastErr),
+			zap.String("media_id", media.ID),
+			zap.Int("attempt", attempt),
+		)
+
+		// Permanent failures such as expired media IDs are not worth retrying
+		if ctx.Err() == nil && !whatsapp.IsRetryable(lastErr) {
+			break
+		}
+
+		if ctx.Err() == nil && attempt < mediaDownloadAttempts {
+			select {
+			case <-ctx.Done():
+			case <-time.After(mediaDownloadBackoff * time.Duration(1<<(attempt-1))):
+			}
+		}
+
+		if ctx.Err() != nil {
+			// Shutting down: leave the record pending so it resumes after restart
+			s.updateStatus(media.ID, map[string]interface{}{
+				"status":     models.MediaStatusPending,
+				"attempts":   media.Attempts,
+				"last_error": lastErr.Error(),
+			})
+			return
+		}
+	}
+
+	updates := map[string]interface{}{"attempts": media.Attempts}
+	if lastErr != nil {
+		updates["status"] = models.MediaStatusFailed
+		updates["last_error"] = lastErr.Error()
+		s.logger.Error("Giving up on media download", zap.Error(lastErr), zap.String("media_id", media.ID))
+	} else {
+		updates["status"] = models.MediaStatusStored
+		updates["storage_key"] = media.StorageKey
+		updates["size"] = media.Size
+		updates["sha256"] = media.SHA256
+		updates["last_error"] = ""
+		s.logger.Info("Media downloaded",
+			zap.String("media_id", media.ID),
+			zap.Int64("size", media.Size),
+		)
+	}
+
+	s.updateStatus(media.ID, updates)
+}
+
+// updateStatus records the outcome of a download. It uses its own deadline so the
+// result is saved even when the download was cancelled by shutdown.
+func (s *MediaService) updateStatus(mediaID string, updates map[string]interface{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), mediaStatusTimeout)
+	defer cancel()
+
+	if err := s.mediaRepo.UpdateFields(ctx, mediaID, &models.Media{}, updates); err != nil {
+		s.logger.Error("Failed to update media record", zap.Error(err), zap.String("media_id", mediaID))
+	}
+}
+
+// download fetches the media from WhatsApp, verifies it and stores it
+func (s *MediaService) download(ctx context.Context, media *models.Media) error {
+	// Media is fetched with the credentials of the number that received it
+	waClient := s.senders.ClientFor(media.PhoneNumberID)
+
+	info, err := waClient.GetMediaURL(ctx, media.WhatsAppMediaID)
+	if err != nil {
+		return err
+	}
+
+	data, err := waClient.DownloadMedia(ctx, info.URL, whatsapp.MaxDocumentSize)
+	if err != nil {
+		return err
+	}
+
+	// Verify integrity against the digest from the webhook, or from Graph if absent
+	expected := media.SHA256
+	if expected == "" {
+		expected = info.SHA256
+	}
+	if expected != "" && !whatsapp.VerifySHA256(data, expected) {
+		return fmt.Errorf("sha256 mismatch for media %s", media.WhatsAppMediaID)
+	}
+
+	key, err := s.store(ctx, media, data)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	media.StorageKey = key
+	media.Size = int64(len(data))
+	media.SHA256 = hex.EncodeToString(sum[:])
+	return nil
+}
+
+// store writes media content to storage and returns its key
+func (s *MediaService) store(ctx context.Context, media *models.Media, data []byte) (string, error) {
+	key := media.ID + mediaExtension(media.MimeType)
+	if err := s.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), media.MimeType); err != nil {
+		return "", fmt.Errorf("failed to store media: %w", err)
+	}
+	return key, nil
+}
+
+// preferredExtensions overrides the platform MIME table for common WhatsApp media types
+var preferredExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"audio/ogg":       ".ogg",
+	"audio/mpeg":      ".mp3",
+	"audio/mp4":       ".m4a",
+	"audio/aac":       ".aac",
+	"video/mp4":       ".mp4",
+	"video/3gpp":      ".3gp",
+	"application/pdf": ".pdf",
+}
+
+// mediaExtension returns a file extension for a MIME type such as "audio/ogg; codecs=opus"
+func mediaExtension(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return ".bin"
+	}
+	if ext, ok := preferredExtensions[mediaType]; ok {
+		return ext
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
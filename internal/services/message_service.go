@@ -1,9 +1,13 @@
 package services
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/ashok/vibecoded-wa-client/internal/metrics"
 	"github.com/ashok/vibecoded-wa-client/internal/models"
 	"github.com/ashok/vibecoded-wa-client/internal/repositories"
 	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
@@ -11,33 +15,74 @@ import (
 	"github.com/ashok/vibecoded-wa-client/pkg/utils"
 	"github.com/ashok/vibecoded-wa-client/pkg/validator"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// SendOptions holds options shared by all outbound message types
+type SendOptions struct {
+	ReplyTo string // internal ID of the message being quoted
+	From    string // sender name or phone number ID; defaults to the quoted message's number, then the default sender
+}
+
+// MediaMessageInput describes an outbound media message. Exactly one of
+// URL or MediaID must be set; MediaID refers to a previously uploaded media record.
+type MediaMessageInput struct {
+	Type     string
+	URL      string
+	MediaID  string
+	Caption  string
+	Filename string
+}
+
+// TemplateMessageInput describes an outbound template message. Components
+// carry typed parameters; Parameters is shorthand for plain text body parameters.
+type TemplateMessageInput struct {
+	Name       string
+	Language   string
+	Parameters []string
+	Components []whatsapp.TemplateComponent
+}
+
 // MessageService handles message business logic
 type MessageService struct {
-	messageRepo  *repositories.MessageRepository
-	contactRepo  *repositories.ContactRepository
-	waClient     *whatsapp.Client
-	logger       *zap.Logger
+	messageRepo     *repositories.MessageRepository
+	statusEventRepo *repositories.MessageStatusEventRepository
+	contactRepo     *repositories.ContactRepository
+	templateRepo    *repositories.TemplateRepository
+	mediaService    *MediaService
+	flowService     *FlowService
+	orderService    *OrderService
+	senders         *whatsapp.Registry
+	logger          *zap.Logger
 }
 
 // NewMessageService creates a new message service
 func NewMessageService(
 	messageRepo *repositories.MessageRepository,
+	statusEventRepo *repositories.MessageStatusEventRepository,
 	contactRepo *repositories.ContactRepository,
-	waClient *whatsapp.Client,
+	templateRepo *repositories.TemplateRepository,
+	mediaService *MediaService,
+	flowService *FlowService,
+	orderService *OrderService,
+	senders *whatsapp.Registry,
 	logger *zap.Logger,
 ) *MessageService {
 	return &MessageService{
-		messageRepo: messageRepo,
-		contactRepo: contactRepo,
-		waClient:    waClient,
-		logger:      logger,
+		messageRepo:     messageRepo,
+		statusEventRepo: statusEventRepo,
+		contactRepo:     contactRepo,
+		templateRepo:    templateRepo,
+		mediaService:    mediaService,
+		flowService:     flowService,
+		orderService:    orderService,
+		senders:         senders,
+		logger:          logger,
 	}
 }
 
 // SendTextMessage sends a text message
-func (s *MessageService) SendTextMessage(phone, content string) (*models.Message, error) {
+func (s *MessageService) SendTextMessage(ctx context.Context, phone, content string, opts SendOptions) (*models.Message, error) {
 	// Validate phone number
 	if err := validator.ValidatePhoneNumber(phone); err != nil {
 		return nil, errors.NewInvalidPhoneNumberError(phone)
@@ -49,14 +94,20 @@ func (s *MessageService) SendTextMessage(phone, content string) (*models.Message
 	}
 
 	// Get or create contact
-	_, err := s.contactRepo.GetOrCreate(phone)
+	_, err := s.contactRepo.GetOrCreate(ctx, phone)
 	if err != nil {
 		s.logger.Error("Failed to get/create contact", zap.Error(err))
 		return nil, errors.NewDatabaseError(err)
 	}
 
+	// Resolve sender and quoted message
+	out, err := s.resolveOutbound(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Send message via WhatsApp
-	resp, err := s.waClient.SendTextMessage(phone, content)
+	resp, err := out.sender.Client.SendTextMessage(ctx, phone, content, out.sendOpts...)
 	if err != nil {
 		s.logger.Error("Failed to send WhatsApp message", zap.Error(err))
 		return nil, err
@@ -65,23 +116,25 @@ func (s *MessageService) SendTextMessage(phone, content string) (*models.Message
 	// Create message record
 	message := &models.Message{
 		WhatsAppMessageID: resp.Messages[0].ID,
-		FromNumber:        resp.Contacts[0].Input,
+		FromNumber:        out.sender.PhoneNumber(),
 		ToNumber:          phone,
+		PhoneNumberID:     out.sender.PhoneNumberID(),
 		Direction:         "outbound",
 		MessageType:       models.MessageTypeText,
 		Content:           content,
 		Status:            models.MessageStatusSent,
 		Timestamp:         time.Now().UTC(),
+		ReplyToID:         out.replyToID,
 	}
 
-	if err := s.messageRepo.Create(message); err != nil {
+	if err := s.saveOutbound(ctx, message); err != nil {
 		s.logger.Error("Failed to save message", zap.Error(err))
 		return nil, errors.NewDatabaseError(err)
 	}
 
 	// Update contact last message time
-	s.contactRepo.UpdateLastMessage(phone, message.Timestamp)
-	s.contactRepo.IncrementMessageCount(phone, 1)
+	s.contactRepo.UpdateLastMessage(ctx, phone, message.Timestamp)
+	s.contactRepo.IncrementMessageCount(ctx, phone, 1)
 
 	s.logger.Info("Message sent successfully",
 		zap.String("message_id", message.ID),
@@ -92,30 +145,37 @@ func (s *MessageService) SendTextMessage(phone, content string) (*models.Message
 }
 
 // SendMediaMessage sends a media message
-func (s *MessageService) SendMediaMessage(phone, mediaURL, caption, mediaType string) (*models.Message, error) {
+func (s *MessageService) SendMediaMessage(ctx context.Context, phone string, input MediaMessageInput, opts SendOptions) (*models.Message, error) {
 	// Validate phone number
 	if err := validator.ValidatePhoneNumber(phone); err != nil {
 		return nil, errors.NewInvalidPhoneNumberError(phone)
 	}
 
-	// Validate media URL
-	if err := validator.ValidateURL(mediaURL); err != nil {
-		return nil, errors.NewBadRequest("invalid media URL: " + err.Error())
-	}
-
 	// Validate message type
-	if err := validator.ValidateMessageType(mediaType); err != nil {
+	if err := validator.ValidateMessageType(input.Type); err != nil {
 		return nil, errors.NewBadRequest(err.Error())
 	}
 
+	// Resolve sender and quoted message
+	out, err := s.resolveOutbound(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve media reference
+	media, err := s.resolveMedia(ctx, input, out.sender)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get or create contact
-	_, err := s.contactRepo.GetOrCreate(phone)
+	_, err = s.contactRepo.GetOrCreate(ctx, phone)
 	if err != nil {
 		return nil, errors.NewDatabaseError(err)
 	}
 
 	// Send message via WhatsApp
-	resp, err := s.waClient.SendMediaMessage(phone, mediaURL, caption, whatsapp.MediaType(mediaType))
+	resp, err := out.sender.Client.SendMediaMessage(ctx, phone, media, whatsapp.MediaType(input.Type), out.sendOpts...)
 	if err != nil {
 		s.logger.Error("Failed to send media message", zap.Error(err))
 		return nil, err
@@ -124,42 +184,126 @@ func (s *MessageService) SendMediaMessage(phone, mediaURL, caption, mediaType st
 	// Create message record
 	message := &models.Message{
 		WhatsAppMessageID: resp.Messages[0].ID,
-		FromNumber:        resp.Contacts[0].Input,
+		FromNumber:        out.sender.PhoneNumber(),
 		ToNumber:          phone,
+		PhoneNumberID:     out.sender.PhoneNumberID(),
 		Direction:         "outbound",
-		MessageType:       mediaType,
-		Content:           caption,
-		MediaURL:          mediaURL,
+		MessageType:       input.Type,
+		Content:           input.Caption,
+		MediaURL:          input.URL,
+		MediaID:           input.MediaID,
 		Status:            models.MessageStatusSent,
 		Timestamp:         time.Now().UTC(),
+		ReplyToID:         out.replyToID,
 	}
 
-	if err := s.messageRepo.Create(message); err != nil {
+	if err := s.saveOutbound(ctx, message); err != nil {
 		return nil, errors.NewDatabaseError(err)
 	}
 
 	// Update contact
-	s.contactRepo.UpdateLastMessage(phone, message.Timestamp)
-	s.contactRepo.IncrementMessageCount(phone, 1)
+	s.contactRepo.UpdateLastMessage(ctx, phone, message.Timestamp)
+	s.contactRepo.IncrementMessageCount(ctx, phone, 1)
 
 	return message, nil
 }
 
+// resolveMedia builds the WhatsApp media object from either a link or an uploaded media record
+func (s *MessageService) resolveMedia(ctx context.Context, input MediaMessageInput, sender *whatsapp.Sender) (*whatsapp.MediaObject, error) {
+	if (input.URL == "") == (input.MediaID == "") {
+		return nil, errors.NewBadRequest("exactly one of media_url or media_id is required")
+	}
+
+	media := &whatsapp.MediaObject{Caption: input.Caption}
+
+	if input.URL != "" {
+		if err := validator.ValidateURL(input.URL); err != nil {
+			return nil, errors.NewBadRequest("invalid media URL: " + err.Error())
+		}
+		media.Link = input.URL
+	} else {
+		record, err := s.mediaService.GetMedia(ctx, input.MediaID)
+		if err != nil {
+			return nil, err
+		}
+		if record.Direction != models.MediaDirectionOutbound {
+			return nil, errors.NewBadRequest("only uploaded media can be sent by media_id")
+		}
+		if record.IsExpired() {
+			return nil, errors.NewBadRequest("media has expired on WhatsApp, upload it again")
+		}
+		if err := checkMediaSender(record, sender); err != nil {
+			return nil, err
+		}
+		media.ID = record.WhatsAppMediaID
+		if input.Filename == "" {
+			input.Filename = record.Filename
+		}
+	}
+
+	// Only documents carry a filename; WhatsApp rejects it on other types
+	if input.Type == models.MessageTypeDocument {
+		media.Filename = input.Filename
+	}
+	// Audio does not support captions
+	if input.Type == models.MessageTypeAudio {
+		media.Caption = ""
+	}
+
+	return media, nil
+}
+
 // SendTemplateMessage sends a template message
-func (s *MessageService) SendTemplateMessage(phone, templateName, language string, params []string) (*models.Message, error) {
+func (s *MessageService) SendTemplateMessage(ctx context.Context, phone string, input TemplateMessageInput, opts SendOptions) (*models.Message, error) {
 	// Validate inputs
 	if err := validator.ValidatePhoneNumber(phone); err != nil {
 		return nil, errors.NewInvalidPhoneNumberError(phone)
 	}
+	if input.Name == "" || input.Language == "" {
+		return nil, errors.NewBadRequest("template_name and template_language are required")
+	}
+
+	components := input.Components
+	if len(components) == 0 {
+		components = whatsapp.BodyTextComponents(input.Parameters)
+	} else if len(input.Parameters) > 0 {
+		return nil, errors.NewBadRequest("use either parameters or components, not both")
+	}
+	if err := whatsapp.ValidateTemplateComponents(components); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	// Resolve sender and quoted message
+	out, err := s.resolveOutbound(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveTemplateMedia(ctx, components, out.sender); err != nil {
+		return nil, err
+	}
+
+	// Check against the local copy of the template when we have one
+	template, err := s.templateRepo.FindByName(ctx, input.Name, input.Language)
+	if err != nil {
+		if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.NewDatabaseError(err)
+		}
+		template = nil
+	}
+	if template != nil {
+		if err := checkTemplateComponents(template, components); err != nil {
+			return nil, err
+		}
+	}
 
 	// Get or create contact
-	_, err := s.contactRepo.GetOrCreate(phone)
+	_, err = s.contactRepo.GetOrCreate(ctx, phone)
 	if err != nil {
 		return nil, errors.NewDatabaseError(err)
 	}
 
 	// Send template message
-	resp, err := s.waClient.SendTemplateMessage(phone, templateName, language, params)
+	resp, err := out.sender.Client.SendTemplateMessage(ctx, phone, input.Name, input.Language, components, out.sendOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -167,66 +311,510 @@ func (s *MessageService) SendTemplateMessage(phone, templateName, language strin
 	// Create message record
 	message := &models.Message{
 		WhatsAppMessageID: resp.Messages[0].ID,
-		FromNumber:        resp.Contacts[0].Input,
+		FromNumber:        out.sender.PhoneNumber(),
 		ToNumber:          phone,
+		PhoneNumberID:     out.sender.PhoneNumberID(),
 		Direction:         "outbound",
 		MessageType:       models.MessageTypeTemplate,
-		Content:           fmt.Sprintf("Template: %s", templateName),
+		Content:           templateContent(template, input.Name, components),
 		Status:            models.MessageStatusSent,
 		Timestamp:         time.Now().UTC(),
+		ReplyToID:         out.replyToID,
 		Metadata: models.JSONMap{
-			"template_name": templateName,
-			"language":      language,
-			"parameters":    params,
+			"template_name": input.Name,
+			"language":      input.Language,
+			"parameters":    input.Parameters,
+			"components":    components,
 		},
 	}
 
-	if err := s.messageRepo.Create(message); err != nil {
+	if err := s.saveOutbound(ctx, message); err != nil {
 		return nil, errors.NewDatabaseError(err)
 	}
 
 	// Update contact
-	s.contactRepo.UpdateLastMessage(phone, message.Timestamp)
-	s.contactRepo.IncrementMessageCount(phone, 1)
+	s.contactRepo.UpdateLastMessage(ctx, phone, message.Timestamp)
+	s.contactRepo.IncrementMessageCount(ctx, phone, 1)
 
 	return message, nil
 }
 
+// resolveTemplateMedia replaces uploaded media record IDs in header parameters with their WhatsApp media IDs
+func (s *MessageService) resolveTemplateMedia(ctx context.Context, components []whatsapp.TemplateComponent, sender *whatsapp.Sender) error {
+	for i := range components {
+		for j := range components[i].Parameters {
+			media := components[i].Parameters[j].HeaderMedia()
+			if media == nil || !strings.HasPrefix(media.ID, "media_") {
+				continue
+			}
+
+			record, err := s.mediaService.GetMedia(ctx, media.ID)
+			if err != nil {
+				return err
+			}
+			if record.Direction != models.MediaDirectionOutbound {
+				return errors.NewBadRequest("only uploaded media can be used in template headers")
+			}
+			if record.IsExpired() {
+				return errors.NewBadRequest("media has expired on WhatsApp, upload it again")
+			}
+			if err := checkMediaSender(record, sender); err != nil {
+				return err
+			}
+			media.ID = record.WhatsAppMediaID
+		}
+	}
+	return nil
+}
+
+// checkMediaSender verifies that uploaded media belongs to the sending phone number.
+// WhatsApp media IDs are scoped to the number they were uploaded through.
+func checkMediaSender(record *models.Media, sender *whatsapp.Sender) error {
+	if record.PhoneNumberID != "" && record.PhoneNumberID != sender.PhoneNumberID() {
+		return errors.NewBadRequest(fmt.Sprintf("media %s was uploaded for a different sender, upload it again from %s", record.ID, sender.Name))
+	}
+	return nil
+}
+
+// checkTemplateComponents verifies that the components supply what the template definition needs
+func checkTemplateComponents(template *models.Template, components []whatsapp.TemplateComponent) error {
+	if template.IsSynced() && !template.IsApproved() {
+		return errors.NewBadRequest(fmt.Sprintf("template %s is %s and cannot be sent", template.Name, template.Status))
+	}
+
+	format := template.HeaderFormat()
+	if format == "" || format == "TEXT" {
+		return nil
+	}
+
+	for _, component := range components {
+		if component.Type != whatsapp.TemplateComponentHeader {
+			continue
+		}
+		if strings.EqualFold(component.Parameters[0].Type, format) {
+			return nil
+		}
+		return errors.NewBadRequest(fmt.Sprintf("template %s requires a %s header parameter", template.Name, strings.ToLower(format)))
+	}
+
+	return errors.NewBadRequest(fmt.Sprintf("template %s requires a %s header parameter", template.Name, strings.ToLower(format)))
+}
+
+// templateContent renders the body of a sent template for display, falling back to the template name
+func templateContent(template *models.Template, name string, components []whatsapp.TemplateComponent) string {
+	if template == nil || template.Content == "" {
+		return fmt.Sprintf("Template: %s", name)
+	}
+
+	content := template.Content
+	for _, component := range components {
+		if component.Type != whatsapp.TemplateComponentBody {
+			continue
+		}
+		for i, param := range component.Parameters {
+			placeholder := fmt.Sprintf("{{%d}}", i+1)
+			if param.ParameterName != "" {
+				placeholder = fmt.Sprintf("{{%s}}", param.ParameterName)
+			}
+			content = strings.ReplaceAll(content, placeholder, templateParameterText(param))
+		}
+	}
+	return content
+}
+
+// templateParameterText returns the display text of a body parameter
+func templateParameterText(param whatsapp.TemplateParameter) string {
+	switch {
+	case param.Currency != nil:
+		return param.Currency.FallbackValue
+	case param.DateTime != nil:
+		return param.DateTime.FallbackValue
+	default:
+		return param.Text
+	}
+}
+
+// SendLocationMessage sends a location message
+func (s *MessageService) SendLocationMessage(ctx context.Context, phone string, location *whatsapp.Location, opts SendOptions) (*models.Message, error) {
+	// Validate inputs
+	if err := validator.ValidatePhoneNumber(phone); err != nil {
+		return nil, errors.NewInvalidPhoneNumberError(phone)
+	}
+	if location == nil {
+		return nil, errors.NewBadRequest("location is required")
+	}
+	if err := location.Validate(); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	// Get or create contact
+	_, err := s.contactRepo.GetOrCreate(ctx, phone)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	// Resolve sender and quoted message
+	out, err := s.resolveOutbound(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send location message
+	resp, err := out.sender.Client.SendLocationMessage(ctx, phone, location, out.sendOpts...)
+	if err != nil {
+		s.logger.Error("Failed to send location message", zap.Error(err))
+		return nil, err
+	}
+
+	// Create message record
+	message := &models.Message{
+		WhatsAppMessageID: resp.Messages[0].ID,
+		FromNumber:        out.sender.PhoneNumber(),
+		ToNumber:          phone,
+		PhoneNumberID:     out.sender.PhoneNumberID(),
+		Direction:         "outbound",
+		MessageType:       models.MessageTypeLocation,
+		Content:           locationSummary(location),
+		Status:            models.MessageStatusSent,
+		Timestamp:         time.Now().UTC(),
+		ReplyToID:         out.replyToID,
+		Metadata:          locationMetadata(location),
+	}
+
+	if err := s.saveOutbound(ctx, message); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	// Update contact
+	s.contactRepo.UpdateLastMessage(ctx, phone, message.Timestamp)
+	s.contactRepo.IncrementMessageCount(ctx, phone, 1)
+
+	return message, nil
+}
+
+// SendContactsMessage sends one or more contact cards
+func (s *MessageService) SendContactsMessage(ctx context.Context, phone string, contacts []whatsapp.ContactCard, opts SendOptions) (*models.Message, error) {
+	// Validate inputs
+	if err := validator.ValidatePhoneNumber(phone); err != nil {
+		return nil, errors.NewInvalidPhoneNumberError(phone)
+	}
+	if len(contacts) == 0 {
+		return nil, errors.NewBadRequest("at least one contact is required")
+	}
+	for i := range contacts {
+		if err := contacts[i].Validate(); err != nil {
+			return nil, errors.NewBadRequest(err.Error())
+		}
+	}
+
+	// Get or create contact
+	_, err := s.contactRepo.GetOrCreate(ctx, phone)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	// Resolve sender and quoted message
+	out, err := s.resolveOutbound(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send contacts message
+	resp, err := out.sender.Client.SendContactsMessage(ctx, phone, contacts, out.sendOpts...)
+	if err != nil {
+		s.logger.Error("Failed to send contacts message", zap.Error(err))
+		return nil, err
+	}
+
+	// Create message record
+	message := &models.Message{
+		WhatsAppMessageID: resp.Messages[0].ID,
+		FromNumber:        out.sender.PhoneNumber(),
+		ToNumber:          phone,
+		PhoneNumberID:     out.sender.PhoneNumberID(),
+		Direction:         "outbound",
+		MessageType:       models.MessageTypeContacts,
+		Content:           contactsSummary(contacts),
+		Status:            models.MessageStatusSent,
+		Timestamp:         time.Now().UTC(),
+		ReplyToID:         out.replyToID,
+		Metadata:          contactsMetadata(contacts),
+	}
+
+	if err := s.saveOutbound(ctx, message); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	// Update contact
+	s.contactRepo.UpdateLastMessage(ctx, phone, message.Timestamp)
+	s.contactRepo.IncrementMessageCount(ctx, phone, 1)
+
+	return message, nil
+}
+
+// SendInteractiveMessage sends an interactive message (reply buttons, list or CTA URL)
+func (s *MessageService) SendInteractiveMessage(ctx context.Context, phone string, interactive *whatsapp.Interactive, opts SendOptions) (*models.Message, error) {
+	// Validate inputs
+	if err := validator.ValidatePhoneNumber(phone); err != nil {
+		return nil, errors.NewInvalidPhoneNumberError(phone)
+	}
+	if interactive == nil {
+		return nil, errors.NewBadRequest("interactive payload is required")
+	}
+	if err := interactive.Validate(); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	// Flow responses are matched back to the flow message by its token
+	if interactive.Type == whatsapp.InteractiveTypeFlow && interactive.Action.Parameters.FlowToken == "" {
+		interactive.Action.Parameters.FlowToken = models.GenerateID("flowtok")
+	}
+
+	// Get or create contact
+	_, err := s.contactRepo.GetOrCreate(ctx, phone)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	// Resolve sender and quoted message
+	out, err := s.resolveOutbound(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send interactive message
+	resp, err := out.sender.Client.SendInteractiveMessage(ctx, phone, interactive, out.sendOpts...)
+	if err != nil {
+		s.logger.Error("Failed to send interactive message", zap.Error(err))
+		return nil, err
+	}
+
+	// Create message record
+	message := &models.Message{
+		WhatsAppMessageID: resp.Messages[0].ID,
+		FromNumber:        out.sender.PhoneNumber(),
+		ToNumber:          phone,
+		PhoneNumberID:     out.sender.PhoneNumberID(),
+		Direction:         "outbound",
+		MessageType:       models.MessageTypeInteractive,
+		Content:           interactiveContent(interactive),
+		Status:            models.MessageStatusSent,
+		Timestamp:         time.Now().UTC(),
+		ReplyToID:         out.replyToID,
+		Metadata:          interactiveMetadata(interactive),
+	}
+
+	if err := s.saveOutbound(ctx, message); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	// Update contact
+	s.contactRepo.UpdateLastMessage(ctx, phone, message.Timestamp)
+	s.contactRepo.IncrementMessageCount(ctx, phone, 1)
+
+	return message, nil
+}
+
+// SendReaction reacts to a previously stored message; an empty emoji removes the reaction
+func (s *MessageService) SendReaction(ctx context.Context, phone, messageID, emoji string, opts SendOptions) (*models.Message, error) {
+	// Validate inputs
+	if err := validator.ValidatePhoneNumber(phone); err != nil {
+		return nil, errors.NewInvalidPhoneNumberError(phone)
+	}
+	if err := validator.ValidateNotEmpty(messageID, "reaction message_id"); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	// Find the message being reacted to
+	var target models.Message
+	if err := s.messageRepo.FindByID(ctx, messageID, &target); err != nil {
+		return nil, errors.NewNotFound("Message", messageID)
+	}
+	if target.WhatsAppMessageID == "" {
+		return nil, errors.NewBadRequest("message has no WhatsApp message ID and cannot be reacted to")
+	}
+
+	// Reactions go out from the number that holds the conversation
+	sender, err := s.resolveSender(opts.From, target.PhoneNumberID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send reaction
+	resp, err := sender.Client.SendReaction(ctx, phone, target.WhatsAppMessageID, emoji)
+	if err != nil {
+		s.logger.Error("Failed to send reaction", zap.Error(err))
+		return nil, err
+	}
+
+	// Create message record linked to the original message
+	message := &models.Message{
+		WhatsAppMessageID: resp.Messages[0].ID,
+		FromNumber:        sender.PhoneNumber(),
+		ToNumber:          phone,
+		PhoneNumberID:     sender.PhoneNumberID(),
+		Direction:         "outbound",
+		MessageType:       models.MessageTypeReaction,
+		Content:           emoji,
+		Status:            models.MessageStatusSent,
+		Timestamp:         time.Now().UTC(),
+		ReplyToID:         &target.ID,
+		Metadata:          reactionMetadata(target.WhatsAppMessageID, emoji),
+	}
+
+	if err := s.saveOutbound(ctx, message); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	return message, nil
+}
+
+// outbound is the resolved sender and quoted message of an outbound send
+type outbound struct {
+	sender    *whatsapp.Sender
+	replyToID *string
+	sendOpts  []whatsapp.SendOption
+}
+
+// resolveOutbound looks up the quoted message and picks the sender. Replies go out
+// from the number that holds the conversation unless another sender is selected.
+func (s *MessageService) resolveOutbound(ctx context.Context, opts SendOptions) (*outbound, error) {
+	out := &outbound{}
+
+	var conversationPhoneNumberID string
+	if opts.ReplyTo != "" {
+		var target models.Message
+		if err := s.messageRepo.FindByID(ctx, opts.ReplyTo, &target); err != nil {
+			return nil, errors.NewNotFound("Message", opts.ReplyTo)
+		}
+		if target.WhatsAppMessageID == "" {
+			return nil, errors.NewBadRequest("message has no WhatsApp message ID and cannot be replied to")
+		}
+		out.replyToID = &target.ID
+		out.sendOpts = []whatsapp.SendOption{whatsapp.WithReplyTo(target.WhatsAppMessageID)}
+		conversationPhoneNumberID = target.PhoneNumberID
+	}
+
+	sender, err := s.resolveSender(opts.From, conversationPhoneNumberID)
+	if err != nil {
+		return nil, err
+	}
+	out.sender = sender
+
+	return out, nil
+}
+
+// resolveSender finds the selected sender. Without a selection the number that holds
+// the conversation is used, then the default sender. A message can only be quoted
+// from the number it was exchanged on.
+func (s *MessageService) resolveSender(from, conversationPhoneNumberID string) (*whatsapp.Sender, error) {
+	conversationSender, known := s.senders.ForPhoneNumberID(conversationPhoneNumberID)
+	if from == "" && known {
+		return conversationSender, nil
+	}
+
+	sender, ok := s.senders.Get(from)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("unknown sender: %s", from))
+	}
+	if known && sender != conversationSender {
+		return nil, errors.NewBadRequest(fmt.Sprintf("message belongs to sender %s and cannot be referenced from %s", conversationSender.Name, sender.Name))
+	}
+
+	return sender, nil
+}
+
+// applySenderFilter replaces the "from" sender selector in list filters with the
+// phone number ID it refers to
+func applySenderFilter(senders *whatsapp.Registry, filters map[string]interface{}) error {
+	from, ok := filters["from"].(string)
+	if !ok || from == "" {
+		return nil
+	}
+
+	sender, ok := senders.Get(from)
+	if !ok {
+		return errors.NewBadRequest(fmt.Sprintf("unknown sender: %s", from))
+	}
+	delete(filters, "from")
+	filters["phone_number_id"] = sender.PhoneNumberID()
+
+	return nil
+}
+
 // GetMessage gets a message by ID
-func (s *MessageService) GetMessage(messageID string) (*models.Message, error) {
+func (s *MessageService) GetMessage(ctx context.Context, messageID string) (*models.Message, error) {
 	var message models.Message
-	if err := s.messageRepo.FindByID(messageID, &message); err != nil {
+	if err := s.messageRepo.FindByID(ctx, messageID, &message); err != nil {
 		return nil, errors.NewNotFound("Message", messageID)
 	}
 	return &message, nil
 }
 
 // ListMessages lists messages with filters and pagination
-func (s *MessageService) ListMessages(filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Message, error) {
-	return s.messageRepo.ListWithFilters(filters, pagination)
+func (s *MessageService) ListMessages(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Message, error) {
+	if err := applySenderFilter(s.senders, filters); err != nil {
+		return nil, err
+	}
+	return s.messageRepo.ListWithFilters(ctx, filters, pagination)
+}
+
+// ListReplies lists the replies and reactions linked to a message
+func (s *MessageService) ListReplies(ctx context.Context, messageID string) ([]*models.Message, error) {
+	var message models.Message
+	if err := s.messageRepo.FindByID(ctx, messageID, &message); err != nil {
+		return nil, errors.NewNotFound("Message", messageID)
+	}
+
+	replies, err := s.messageRepo.FindReplies(ctx, message.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	return replies, nil
 }
 
 // SearchMessages searches messages by content
-func (s *MessageService) SearchMessages(query string, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Message, error) {
-	return s.messageRepo.Search(query, filters, pagination)
+func (s *MessageService) SearchMessages(ctx context.Context, query string, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Message, error) {
+	if err := applySenderFilter(s.senders, filters); err != nil {
+		return nil, err
+	}
+	return s.messageRepo.Search(ctx, query, filters, pagination)
 }
 
 // ProcessIncomingMessage processes an incoming message from webhook
-func (s *MessageService) ProcessIncomingMessage(event *whatsapp.MessageEvent) error {
+func (s *MessageService) ProcessIncomingMessage(ctx context.Context, event *whatsapp.MessageEvent) error {
 	s.logger.Info("Processing incoming message",
 		zap.String("from", event.From),
 		zap.String("type", event.Type),
 	)
 
+	// Learn the display number of the receiving sender, or flag numbers we don't send from
+	if sender, ok := s.senders.ForPhoneNumberID(event.PhoneNumberID); ok {
+		sender.SetPhoneNumber(event.DisplayPhoneNumber)
+	} else {
+		s.logger.Warn("Message received on an unconfigured phone number",
+			zap.String("phone_number_id", event.PhoneNumberID),
+			zap.String("display_phone_number", event.DisplayPhoneNumber),
+		)
+	}
+
+	// Meta retries deliveries, so the same message can arrive more than once
+	if exists, err := s.messageRepo.ExistsByWhatsAppMessageID(ctx, event.MessageID); err != nil {
+		return errors.NewDatabaseError(err)
+	} else if exists {
+		s.recordDuplicate(event)
+		return s.recordRedeliveredSubmission(ctx, event)
+	}
+
 	// Get or create contact
-	contact, err := s.contactRepo.GetOrCreate(event.From)
+	contact, err := s.contactRepo.GetOrCreate(ctx, event.From)
 	if err != nil {
 		return errors.NewDatabaseError(err)
 	}
 
 	// Update contact name if provided
 	if event.ContactName != "" && contact.Name != event.ContactName {
-		s.contactRepo.UpdateFields(contact.ID, contact, map[string]interface{}{
+		s.contactRepo.UpdateFields(ctx, contact.ID, contact, map[string]interface{}{
 			"name": event.ContactName,
 		})
 	}
@@ -235,29 +823,441 @@ func (s *MessageService) ProcessIncomingMessage(event *whatsapp.MessageEvent) er
 	message := &models.Message{
 		WhatsAppMessageID: event.MessageID,
 		FromNumber:        event.From,
-		ToNumber:          "", // Will be set from webhook metadata
+		ToNumber:          whatsapp.NormalizePhoneNumber(event.DisplayPhoneNumber),
+		PhoneNumberID:     event.PhoneNumberID,
 		Direction:         "inbound",
 		MessageType:       event.Type,
 		Content:           event.Content,
 		MediaURL:          event.MediaURL,
 		MediaMimeType:     event.MimeType,
-		Status:            "received",
+		Status:            models.MessageStatusReceived,
 		Timestamp:         event.Timestamp,
+		Metadata:          inboundMetadata(event),
+	}
+	if message.Content == "" {
+		// Media messages carry their text as a caption, stored as content like outbound ones
+		message.Content = event.Caption
+	}
+
+	// Record inbound media so it can be downloaded before the WhatsApp URL expires
+	var media *models.Media
+	if event.MediaID != "" {
+		if media, err = s.mediaService.RegisterInboundMedia(ctx, event); err != nil {
+			s.logger.Error("Failed to register inbound media", zap.Error(err), zap.String("whatsapp_media_id", event.MediaID))
+		} else {
+			message.MediaID = media.ID
+		}
 	}
 
-	if err := s.messageRepo.Create(message); err != nil {
+	// Link replies and reactions to the original message when we know it
+	if event.ReplyToID != "" {
+		if original, err := s.messageRepo.FindByWhatsAppMessageID(ctx, event.ReplyToID); err == nil {
+			message.ReplyToID = &original.ID
+		}
+	}
+
+	// Store the message and update the contact counters together. Reactions do
+	// not count as new conversation messages.
+	created, err := s.messageRepo.CreateInbound(ctx, message, !message.IsReaction())
+	if err != nil {
 		return errors.NewDatabaseError(err)
 	}
+	if !created {
+		// A concurrent delivery of the same message won the race
+		if media != nil {
+			s.mediaService.DiscardInboundMedia(ctx, media)
+		}
+		s.recordDuplicate(event)
+		return s.recordRedeliveredSubmission(ctx, event)
+	}
+	metrics.WebhookMessages.Inc("processed")
 
-	// Update contact
-	s.contactRepo.UpdateLastMessage(event.From, event.Timestamp)
-	s.contactRepo.IncrementMessageCount(event.From, 1)
-	s.contactRepo.UpdateUnreadCount(event.From, 1)
+	if media != nil {
+		s.mediaService.EnqueueDownload(ctx, media.ID, message.ID)
+	}
+
+	return s.recordSubmission(ctx, message, contact, event)
+}
+
+// recordSubmission stores the flow data or order submitted in an inbound message.
+// Both are recorded at most once per message, so a failure is returned and the
+// webhook retried; the retry arrives as a duplicate and records them then.
+func (s *MessageService) recordSubmission(ctx context.Context, message *models.Message, contact *models.Contact, event *whatsapp.MessageEvent) error {
+	if event.FlowReply != nil && event.FlowReply.ParseError != "" {
+		// The raw response is kept in the message metadata
+		s.logger.Warn("Failed to parse flow response",
+			zap.String("error", event.FlowReply.ParseError),
+			zap.String("message_id", message.ID),
+		)
+	} else if event.FlowReply != nil {
+		if _, err := s.flowService.RecordResponse(ctx, message, contact, event.FlowReply); err != nil {
+			return fmt.Errorf("failed to record flow response: %w", err)
+		}
+	}
+	if event.Order != nil {
+		if _, err := s.orderService.RecordOrder(ctx, message, contact, event.Order); err != nil {
+			return fmt.Errorf("failed to record order: %w", err)
+		}
+	}
+	return nil
+}
+
+// recordRedeliveredSubmission records the flow data or order of an inbound message
+// that is already stored, in case recording it failed on an earlier delivery
+func (s *MessageService) recordRedeliveredSubmission(ctx context.Context, event *whatsapp.MessageEvent) error {
+	if event.FlowReply == nil && event.Order == nil {
+		return nil
+	}
+
+	message, err := s.messageRepo.FindByWhatsAppMessageID(ctx, event.MessageID)
+	if err != nil {
+		return errors.NewDatabaseError(err)
+	}
+	contact, err := s.contactRepo.GetOrCreate(ctx, message.FromNumber)
+	if err != nil {
+		return errors.NewDatabaseError(err)
+	}
+	return s.recordSubmission(ctx, message, contact, event)
+}
+
+// recordDuplicate counts a repeated delivery of an inbound message that is already stored
+func (s *MessageService) recordDuplicate(event *whatsapp.MessageEvent) {
+	metrics.WebhookMessages.Inc("duplicate")
+	s.logger.Debug("Skipping duplicate delivery of incoming message",
+		zap.String("whatsapp_message_id", event.MessageID),
+		zap.String("from", event.From),
+	)
+}
+
+// MarkMessageRead sends a read receipt for an inbound message, which also marks every
+// earlier message in the conversation as read, and resets the contact's unread count
+func (s *MessageService) MarkMessageRead(ctx context.Context, messageID, readBy string, showTyping bool) (*models.Message, error) {
+	var message models.Message
+	if err := s.messageRepo.FindByID(ctx, messageID, &message); err != nil {
+		return nil, errors.NewNotFound("Message", messageID)
+	}
+	if !message.IsInbound() {
+		return nil, errors.NewBadRequest("only inbound messages can be marked as read")
+	}
+
+	if err := s.markRead(ctx, &message, readBy, showTyping); err != nil {
+		return nil, err
+	}
+
+	// Fetch updated message
+	if err := s.messageRepo.FindByID(ctx, messageID, &message); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	return &message, nil
+}
+
+// MarkConversationRead sends a read receipt for the latest inbound message from a contact
+// on each of our numbers that has unread messages from them
+func (s *MessageService) MarkConversationRead(ctx context.Context, contactID, readBy string, showTyping bool) (*models.Contact, error) {
+	var contact models.Contact
+	if err := s.contactRepo.FindByID(ctx, contactID, &contact); err != nil {
+		return nil, errors.NewNotFound("Contact", contactID)
+	}
+
+	phoneNumberIDs, err := s.messageRepo.FindInboundPhoneNumberIDs(ctx, contact.PhoneNumber, true)
+	if err == nil && len(phoneNumberIDs) == 0 {
+		// Everything is read already; resend the receipt for every conversation
+		phoneNumberIDs, err = s.messageRepo.FindInboundPhoneNumberIDs(ctx, contact.PhoneNumber, false)
+	}
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	if len(phoneNumberIDs) == 0 {
+		return nil, errors.NewNotFound("Inbound message for contact", contactID)
+	}
+
+	for _, phoneNumberID := range phoneNumberIDs {
+		latest, err := s.messageRepo.FindLatestInbound(ctx, contact.PhoneNumber, phoneNumberID)
+		if err != nil {
+			return nil, errors.NewDatabaseError(err)
+		}
+		if err := s.markRead(ctx, latest, readBy, showTyping); err != nil {
+			return nil, err
+		}
+	}
+
+	// Fetch updated contact
+	if err := s.contactRepo.FindByID(ctx, contactID, &contact); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	return &contact, nil
+}
+
+// markRead sends the read receipt to WhatsApp and records it locally
+func (s *MessageService) markRead(ctx context.Context, message *models.Message, readBy string, showTyping bool) error {
+	client := s.senders.ClientFor(message.PhoneNumberID)
+	if err := client.MarkAsRead(ctx, message.WhatsAppMessageID, showTyping); err != nil {
+		s.logger.Error("Failed to send read receipt",
+			zap.Error(err),
+			zap.String("message_id", message.ID),
+		)
+		return err
+	}
+
+	// The receipt covers every earlier message received on the same number
+	count, err := s.messageRepo.MarkInboundRead(ctx, message.FromNumber, message.PhoneNumberID, message.Timestamp, readBy)
+	if err != nil {
+		return errors.NewDatabaseError(err)
+	}
+
+	// Recount rather than decrement, as other numbers may still hold unread messages
+	unread, err := s.messageRepo.CountUnreadInbound(ctx, message.FromNumber)
+	if err == nil {
+		err = s.contactRepo.SetUnreadCount(ctx, message.FromNumber, unread)
+	}
+	if err != nil {
+		return errors.NewDatabaseError(err)
+	}
+
+	s.logger.Info("Messages marked as read",
+		zap.String("message_id", message.ID),
+		zap.String("phone", message.FromNumber),
+		zap.Int64("count", count),
+		zap.String("read_by", readBy),
+	)
 
 	return nil
 }
 
-// UpdateMessageStatus updates the status of a message
-func (s *MessageService) UpdateMessageStatus(whatsappMessageID, status string) error {
-	return s.messageRepo.UpdateStatus(whatsappMessageID, status)
+// UpdateMessageStatus records a status update from a webhook in the message's
+// status history and applies it to the message. Statuses only advance, so
+// updates that arrive out of order are kept in the history without regressing
+// the message. Repeated deliveries of the same update are counted as duplicates
+// and otherwise ignored. Updates for a message that is not stored yet wait in
+// the history and are applied once it is.
+func (s *MessageService) UpdateMessageStatus(ctx context.Context, event *whatsapp.StatusEvent) error {
+	created, err := s.messageRepo.RecordStatusEvent(ctx, statusEventRecord(event))
+	if err != nil {
+		return errors.NewDatabaseError(err)
+	}
+	if !created {
+		metrics.WebhookStatuses.Inc("duplicate")
+		return nil
+	}
+
+	if _, err := s.messageRepo.ApplyPendingStatusEvents(ctx, event.MessageID); err != nil {
+		if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.NewDatabaseError(err)
+		}
+		metrics.WebhookStatuses.Inc("unknown_message")
+		s.logger.Info("Buffered status update for a message not stored yet",
+			zap.String("whatsapp_message_id", event.MessageID),
+			zap.String("status", event.Status),
+		)
+		return nil
+	}
+
+	metrics.WebhookStatuses.Inc("applied")
+	return nil
+}
+
+// saveOutbound stores a sent message and applies the status updates WhatsApp
+// delivered before it was stored. The message has already been sent, so failing
+// to apply them is logged rather than returned; they are applied with the next
+// status update instead.
+func (s *MessageService) saveOutbound(ctx context.Context, message *models.Message) error {
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return err
+	}
+
+	applied, err := s.messageRepo.ApplyPendingStatusEvents(ctx, message.WhatsAppMessageID)
+	if err != nil {
+		s.logger.Warn("Failed to apply buffered status updates",
+			zap.Error(err),
+			zap.String("message_id", message.ID),
+		)
+		return nil
+	}
+	if applied > 0 {
+		if err := s.messageRepo.FindByID(ctx, message.ID, message); err != nil {
+			s.logger.Warn("Failed to reload message", zap.Error(err), zap.String("message_id", message.ID))
+		}
+	}
+	return nil
+}
+
+// GetStatusHistory lists every status update WhatsApp reported for a message, oldest first
+func (s *MessageService) GetStatusHistory(ctx context.Context, messageID string) ([]*models.MessageStatusEvent, error) {
+	message, err := s.GetMessage(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.WhatsAppMessageID == "" {
+		return []*models.MessageStatusEvent{}, nil
+	}
+
+	events, err := s.statusEventRepo.FindByWhatsAppMessageID(ctx, message.WhatsAppMessageID)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	return events, nil
+}
+
+// statusEventRecord converts a status update from a webhook into a history record
+func statusEventRecord(event *whatsapp.StatusEvent) *models.MessageStatusEvent {
+	record := &models.MessageStatusEvent{
+		WhatsAppMessageID:     event.MessageID,
+		PhoneNumberID:         event.PhoneNumberID,
+		RecipientID:           event.RecipientID,
+		Status:                event.Status,
+		Timestamp:             event.Timestamp,
+		ErrorCode:             event.ErrorCode,
+		ErrorTitle:            event.ErrorTitle,
+		ErrorMessage:          event.ErrorMsg,
+		ErrorDetails:          event.ErrorDetails,
+		ConversationID:        event.ConversationID,
+		ConversationOrigin:    event.ConversationOrigin,
+		ConversationExpiresAt: event.ConversationExpiresAt,
+	}
+	if event.Pricing != nil {
+		billable := event.Pricing.Billable
+		record.Billable = &billable
+		record.PricingModel = event.Pricing.PricingModel
+		record.PricingCategory = event.Pricing.Category
+		record.PricingType = event.Pricing.Type
+	}
+	return record
+}
+
+// inboundMetadata builds the structured metadata stored with an incoming message
+func inboundMetadata(event *whatsapp.MessageEvent) models.JSONMap {
+	metadata := models.JSONMap{}
+
+	switch {
+	case event.Reaction != nil:
+		metadata = reactionMetadata(event.Reaction.MessageID, event.Reaction.Emoji)
+	case event.Location != nil:
+		metadata = locationMetadata(event.Location)
+	case len(event.Contacts) > 0:
+		metadata = contactsMetadata(event.Contacts)
+	case event.Order != nil:
+		metadata = models.JSONMap{"order": event.Order}
+	case event.Reply != nil:
+		metadata = models.JSONMap{"reply": event.Reply}
+		if event.Reply.Type != whatsapp.ReplyTypeQuickReply {
+			metadata["interactive_type"] = event.Reply.Type
+		}
+	case event.System != nil:
+		metadata = models.JSONMap{"system": event.System}
+	case len(event.Raw) > 0:
+		// A type we do not model yet: keep the whole message
+		metadata = models.JSONMap{"raw": event.Raw}
+	}
+
+	if event.ReplyToID != "" && event.Reaction == nil {
+		metadata["context_whatsapp_message_id"] = event.ReplyToID
+	}
+	if event.Forwarded {
+		metadata["forwarded"] = true
+	}
+	if event.FlowReply != nil {
+		metadata["interactive_type"] = "nfm_reply"
+		metadata["flow_token"] = event.FlowReply.FlowToken
+		if event.FlowReply.ParseError != "" {
+			metadata["flow_response_json"] = event.FlowReply.ResponseJSON
+			metadata["flow_response_error"] = event.FlowReply.ParseError
+		}
+	}
+	if event.Voice {
+		metadata["voice"] = true
+	}
+	if event.Animated {
+		metadata["animated"] = true
+	}
+	if event.Referral != nil {
+		metadata["referral"] = event.Referral
+	}
+	if len(event.Errors) > 0 {
+		metadata["errors"] = event.Errors
+	}
+
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
+
+// interactiveContent returns the display text of an interactive message; single
+// product messages may omit the body
+func interactiveContent(interactive *whatsapp.Interactive) string {
+	if interactive.Body != nil && interactive.Body.Text != "" {
+		return interactive.Body.Text
+	}
+	if interactive.Type == whatsapp.InteractiveTypeProduct {
+		return fmt.Sprintf("Product: %s", interactive.Action.ProductRetailerID)
+	}
+	return ""
+}
+
+// interactiveMetadata stores the interactive payload; flow messages also keep the
+// flow identifiers at the top level so responses can be linked to the flow
+func interactiveMetadata(interactive *whatsapp.Interactive) models.JSONMap {
+	metadata := models.JSONMap{
+		"interactive_type": string(interactive.Type),
+		"interactive":      interactive,
+	}
+
+	if interactive.Type == whatsapp.InteractiveTypeFlow {
+		params := interactive.Action.Parameters
+		metadata["flow_id"] = params.FlowID
+		metadata["flow_name"] = params.FlowName
+		metadata["flow_token"] = params.FlowToken
+	}
+
+	return metadata
+}
+
+// reactionMetadata stores the reacted-to WhatsApp message and whether the reaction was removed
+func reactionMetadata(whatsappMessageID, emoji string) models.JSONMap {
+	return models.JSONMap{
+		"reaction_to_whatsapp_message_id": whatsappMessageID,
+		"emoji":                           emoji,
+		"removed":                         emoji == "",
+	}
+}
+
+// locationMetadata stores the coordinates of a location message
+func locationMetadata(location *whatsapp.Location) models.JSONMap {
+	return models.JSONMap{
+		"location": location,
+	}
+}
+
+// contactsMetadata stores the structured contact cards along with their vCard rendering
+func contactsMetadata(contacts []whatsapp.ContactCard) models.JSONMap {
+	vcards := make([]string, len(contacts))
+	for i := range contacts {
+		vcards[i] = contacts[i].VCard()
+	}
+	return models.JSONMap{
+		"contacts": contacts,
+		"vcards":   vcards,
+	}
+}
+
+// locationSummary returns a human readable description of a location
+func locationSummary(location *whatsapp.Location) string {
+	if location.Name != "" {
+		return location.Name
+	}
+	if location.Address != "" {
+		return location.Address
+	}
+	return fmt.Sprintf("%f,%f", location.Latitude, location.Longitude)
+}
+
+// contactsSummary returns the names of the shared contacts
+func contactsSummary(contacts []whatsapp.ContactCard) string {
+	names := make([]string, len(contacts))
+	for i, contact := range contacts {
+		names[i] = contact.Name.FormattedName
+	}
+	return strings.Join(names, ", ")
 }
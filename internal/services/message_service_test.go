@@ -0,0 +1,299 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/database"
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/internal/storage"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp/fake"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens a migrated in-memory database private to the test
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := database.AutoMigrate(db); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	return db
+}
+
+// messageServiceEnv is a message service wired to a fake Graph API
+type messageServiceEnv struct {
+	service  *services.MessageService
+	db       *gorm.DB
+	srv      *fake.Server
+	webhooks <-chan []byte
+}
+
+func newMessageServiceEnv(t *testing.T) *messageServiceEnv {
+	t.Helper()
+
+	srv := fake.NewServer(fake.Options{}).Start()
+	t.Cleanup(srv.Close)
+
+	webhooks := make(chan []byte, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		webhooks <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(receiver.Close)
+	srv.SetWebhookURL(receiver.URL)
+
+	client, err := whatsapp.NewClient(srv.ClientConfig())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	registry, err := whatsapp.NewRegistry([]*whatsapp.Sender{
+		whatsapp.NewSender("main", fake.DefaultDisplayPhoneNumber, client),
+	}, "")
+	if err != nil {
+		t.Fatalf("Failed to create sender registry: %v", err)
+	}
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	db := newTestDB(t)
+	log := zap.NewNop()
+	messageRepo := repositories.NewMessageRepository(db)
+	mediaService := services.NewMediaService(repositories.NewMediaRepository(db), registry, store, log)
+	flowService := services.NewFlowService(repositories.NewFlowResponseRepository(db), messageRepo, registry, log)
+	orderService := services.NewOrderService(repositories.NewOrderRepository(db), registry, log)
+	service := services.NewMessageService(
+		messageRepo,
+		repositories.NewMessageStatusEventRepository(db),
+		repositories.NewContactRepository(db),
+		repositories.NewTemplateRepository(db),
+		mediaService,
+		flowService,
+		orderService,
+		registry,
+		log,
+	)
+
+	return &messageServiceEnv{service: service, db: db, srv: srv, webhooks: webhooks}
+}
+
+// deliverStatuses waits for a webhook from the fake and applies its status updates
+func (e *messageServiceEnv) deliverStatuses(t *testing.T) {
+	t.Helper()
+
+	var body []byte
+	select {
+	case body = <-e.webhooks:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for a webhook")
+	}
+
+	payload, err := whatsapp.ParseWebhook(body)
+	if err != nil {
+		t.Fatalf("ParseWebhook failed: %v", err)
+	}
+	events, err := whatsapp.ParseStatusEvent(payload)
+	if err != nil {
+		t.Fatalf("ParseStatusEvent failed: %v", err)
+	}
+	for _, event := range events {
+		if err := e.service.UpdateMessageStatus(context.Background(), event); err != nil {
+			t.Fatalf("UpdateMessageStatus failed: %v", err)
+		}
+	}
+}
+
+func TestSendTextMessageStatusRoundTrip(t *testing.T) {
+	env := newMessageServiceEnv(t)
+	ctx := context.Background()
+
+	message, err := env.service.SendTextMessage(ctx, "+15551234567", "hello", services.SendOptions{})
+	if err != nil {
+		t.Fatalf("SendTextMessage failed: %v", err)
+	}
+	if message.WhatsAppMessageID == "" || message.Status != models.MessageStatusSent {
+		t.Fatalf("Unexpected stored message: %+v", message)
+	}
+	if message.FromNumber != whatsapp.NormalizePhoneNumber(fake.DefaultDisplayPhoneNumber) || message.PhoneNumberID != fake.DefaultPhoneNumberID {
+		t.Errorf("Unexpected sender %s/%s", message.FromNumber, message.PhoneNumberID)
+	}
+
+	sent := env.srv.Messages()
+	if len(sent) != 1 || sent[0].ID != message.WhatsAppMessageID {
+		t.Fatalf("Expected the fake to receive the message, got %+v", sent)
+	}
+
+	for _, status := range []string{models.MessageStatusDelivered, models.MessageStatusRead} {
+		if err := env.srv.EmitStatus(ctx, message.WhatsAppMessageID, "15551234567", status); err != nil {
+			t.Fatalf("EmitStatus failed: %v", err)
+		}
+		env.deliverStatuses(t)
+	}
+
+	stored, err := env.service.GetMessage(ctx, message.ID)
+	if err != nil {
+		t.Fatalf("GetMessage failed: %v", err)
+	}
+	if stored.Status != models.MessageStatusRead {
+		t.Errorf("Expected status read, got %s", stored.Status)
+	}
+	if stored.RecipientReadAt == nil || stored.ReadAt != nil {
+		t.Errorf("Expected only recipient_read_at to be set, got %v and %v", stored.RecipientReadAt, stored.ReadAt)
+	}
+
+	history, err := env.service.GetStatusHistory(ctx, message.ID)
+	if err != nil {
+		t.Fatalf("GetStatusHistory failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("Expected 2 status events, got %d", len(history))
+	}
+}
+
+func TestRedeliveredOrderIsRecordedAfterFailure(t *testing.T) {
+	env := newMessageServiceEnv(t)
+	ctx := context.Background()
+
+	event := &whatsapp.MessageEvent{
+		MessageID:          "wamid.order",
+		From:               "15551234567",
+		Timestamp:          time.Now().UTC(),
+		Type:               "order",
+		PhoneNumberID:      fake.DefaultPhoneNumberID,
+		DisplayPhoneNumber: fake.DefaultDisplayPhoneNumber,
+		Order: &whatsapp.Order{
+			CatalogID: "catalog_1",
+			ProductItems: []whatsapp.OrderProductItem{
+				{ProductRetailerID: "sku-1", Quantity: 2, ItemPrice: 10, Currency: "USD"},
+			},
+		},
+	}
+
+	// Recording the order fails on the first delivery, after the message was stored
+	if err := env.db.Migrator().DropTable(&models.OrderItem{}, &models.Order{}); err != nil {
+		t.Fatalf("Failed to drop orders: %v", err)
+	}
+	if err := env.service.ProcessIncomingMessage(ctx, event); err == nil {
+		t.Fatalf("Expected the order failure to be returned")
+	}
+	if err := env.db.AutoMigrate(&models.Order{}, &models.OrderItem{}); err != nil {
+		t.Fatalf("Failed to restore orders: %v", err)
+	}
+
+	// Redeliveries record the order exactly once
+	for i := 0; i < 2; i++ {
+		if err := env.service.ProcessIncomingMessage(ctx, event); err != nil {
+			t.Fatalf("Redelivery %d failed: %v", i+1, err)
+		}
+	}
+
+	var messages, orders int64
+	env.db.Model(&models.Message{}).Where("whatsapp_message_id = ?", event.MessageID).Count(&messages)
+	env.db.Model(&models.Order{}).Count(&orders)
+	if messages != 1 || orders != 1 {
+		t.Errorf("Expected 1 message and 1 order, got %d and %d", messages, orders)
+	}
+}
+
+func TestIncomingMediaCaptionIsStoredAsContent(t *testing.T) {
+	env := newMessageServiceEnv(t)
+	ctx := context.Background()
+
+	event := &whatsapp.MessageEvent{
+		MessageID:          "wamid.image",
+		From:               "15551234567",
+		Timestamp:          time.Now().UTC(),
+		Type:               "image",
+		PhoneNumberID:      fake.DefaultPhoneNumberID,
+		DisplayPhoneNumber: fake.DefaultDisplayPhoneNumber,
+		MediaID:            "media-1",
+		MimeType:           "image/jpeg",
+		Caption:            "Receipt for order 42",
+	}
+	if err := env.service.ProcessIncomingMessage(ctx, event); err != nil {
+		t.Fatalf("ProcessIncomingMessage failed: %v", err)
+	}
+
+	var message models.Message
+	if err := env.db.Where("whatsapp_message_id = ?", event.MessageID).First(&message).Error; err != nil {
+		t.Fatalf("Failed to load message: %v", err)
+	}
+	if message.Content != event.Caption {
+		t.Errorf("Expected the caption as content, got %q", message.Content)
+	}
+}
+
+func TestFailedStatusWithoutErrorsLeavesErrorCodeEmpty(t *testing.T) {
+	env := newMessageServiceEnv(t)
+	ctx := context.Background()
+
+	message, err := env.service.SendTextMessage(ctx, "+15551234567", "hello", services.SendOptions{})
+	if err != nil {
+		t.Fatalf("SendTextMessage failed: %v", err)
+	}
+	if err := env.srv.EmitStatus(ctx, message.WhatsAppMessageID, "15551234567", models.MessageStatusFailed); err != nil {
+		t.Fatalf("EmitStatus failed: %v", err)
+	}
+	env.deliverStatuses(t)
+
+	stored, err := env.service.GetMessage(ctx, message.ID)
+	if err != nil {
+		t.Fatalf("GetMessage failed: %v", err)
+	}
+	if stored.Status != models.MessageStatusFailed || stored.ErrorCode != "" {
+		t.Errorf("Expected failed without an error code, got %s with %q", stored.Status, stored.ErrorCode)
+	}
+}
+
+func TestSendTemplateMessageFailsWhenTemplateLookupFails(t *testing.T) {
+	env := newMessageServiceEnv(t)
+	ctx := context.Background()
+
+	// A broken lookup must not be mistaken for a template that is not stored
+	if err := env.db.Migrator().DropTable(&models.Template{}); err != nil {
+		t.Fatalf("Failed to drop templates: %v", err)
+	}
+
+	_, err := env.service.SendTemplateMessage(ctx, "+15551234567", services.TemplateMessageInput{
+		Name:     "order_update",
+		Language: "en_US",
+	}, services.SendOptions{})
+	appErr, ok := err.(*errors.AppError)
+	if !ok || appErr.Code != errors.ErrDatabaseError {
+		t.Fatalf("Expected a database error, got %v", err)
+	}
+	if sent := env.srv.Messages(); len(sent) != 0 {
+		t.Errorf("Expected nothing to be sent, got %d messages", len(sent))
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// OrderService records orders placed over WhatsApp and tracks their fulfilment
+type OrderService struct {
+	orderRepo *repositories.OrderRepository
+	senders   *whatsapp.Registry
+	logger    *zap.Logger
+}
+
+// NewOrderService creates a new order service
+func NewOrderService(orderRepo *repositories.OrderRepository, senders *whatsapp.Registry, logger *zap.Logger) *OrderService {
+	return &OrderService{
+		orderRepo: orderRepo,
+		senders:   senders,
+		logger:    logger,
+	}
+}
+
+// RecordOrder stores an order received in an inbound message. Recording the same
+// message again returns the stored order, so a redelivered webhook is safe.
+func (s *OrderService) RecordOrder(ctx context.Context, message *models.Message, contact *models.Contact, order *whatsapp.Order) (*models.Order, error) {
+	if existing, err := s.orderRepo.FindByMessageID(ctx, message.ID); err == nil {
+		return existing, nil
+	} else if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	record := &models.Order{
+		MessageID:     message.ID,
+		ReplyToID:     message.ReplyToID,
+		ContactID:     contact.ID,
+		PhoneNumber:   contact.PhoneNumber,
+		PhoneNumberID: message.PhoneNumberID,
+		CatalogID:     order.CatalogID,
+		Text:          order.Text,
+		Status:        models.OrderStatusPending,
+		Currency:      order.Currency(),
+		Total:         order.Total(),
+		PlacedAt:      message.Timestamp,
+		Items:         make([]models.OrderItem, 0, len(order.ProductItems)),
+	}
+
+	for _, item := range order.ProductItems {
+		record.ItemCount += item.Quantity
+		record.Items = append(record.Items, models.OrderItem{
+			ProductRetailerID: item.ProductRetailerID,
+			Quantity:          item.Quantity,
+			ItemPrice:         item.ItemPrice,
+			Currency:          item.Currency,
+		})
+	}
+
+	if err := s.orderRepo.Create(ctx, record); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	s.logger.Info("Order received",
+		zap.String("order_id", record.ID),
+		zap.String("phone", record.PhoneNumber),
+		zap.Int("items", record.ItemCount),
+		zap.Float64("total", record.Total),
+		zap.String("currency", record.Currency),
+	)
+
+	return record, nil
+}
+
+// GetOrder retrieves an order with its line items
+func (s *OrderService) GetOrder(ctx context.Context, orderID string) (*models.Order, error) {
+	order, err := s.orderRepo.FindWithItems(ctx, orderID)
+	if err != nil {
+		return nil, errors.NewNotFound("Order", orderID)
+	}
+	return order, nil
+}
+
+// ListOrders lists orders with filters and pagination
+func (s *OrderService) ListOrders(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.Order, error) {
+	if err := applySenderFilter(s.senders, filters); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.orderRepo.ListWithFilters(ctx, filters, pagination)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	return orders, nil
+}
+
+// UpdateOrderStatus moves an order to a new status, recording an optional note
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID, status, note string) (*models.Order, error) {
+	order, err := s.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := order.CanTransitionTo(status); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	now := time.Now().UTC()
+	updates := map[string]interface{}{
+		"status":            status,
+		"note":              note,
+		"status_updated_at": now,
+	}
+	if err := s.orderRepo.UpdateFields(ctx, orderID, order, updates); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	s.logger.Info("Order status updated",
+		zap.String("order_id", orderID),
+		zap.String("status", status),
+	)
+
+	return s.GetOrder(ctx, orderID)
+}
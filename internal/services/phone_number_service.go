@@ -0,0 +1,441 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/events"
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// phoneNumberSyncTimeout bounds a single periodic sync run
+const phoneNumberSyncTimeout = time.Minute
+
+// QualityChangedEvent is published when the quality rating of a phone number changes
+type QualityChangedEvent struct {
+	PhoneNumberID         string `json:"phone_number_id"`
+	WhatsAppPhoneNumberID string `json:"whatsapp_phone_number_id"`
+	DisplayPhoneNumber    string `json:"display_phone_number"`
+	PreviousRating        string `json:"previous_rating"`
+	CurrentRating         string `json:"current_rating"`
+	Downgraded            bool   `json:"downgraded"`
+	MessagingLimitTier    string `json:"messaging_limit_tier"`
+}
+
+// LimitChangedEvent is published when WhatsApp reports a change to the messaging
+// limit or flagged state of a phone number
+type LimitChangedEvent struct {
+	PhoneNumberID         string `json:"phone_number_id,omitempty"` // empty if the number is not stored yet
+	WhatsAppPhoneNumberID string `json:"whatsapp_phone_number_id,omitempty"`
+	DisplayPhoneNumber    string `json:"display_phone_number"`
+	Event                 string `json:"event"` // FLAGGED, UNFLAGGED, UPGRADE, DOWNGRADE, ONBOARDING
+	PreviousLimitTier     string `json:"previous_limit_tier,omitempty"`
+	CurrentLimitTier      string `json:"current_limit_tier"`
+	Status                string `json:"status,omitempty"`
+}
+
+// NameReviewedEvent is published when WhatsApp decides on a requested display name
+type NameReviewedEvent struct {
+	PhoneNumberID         string `json:"phone_number_id,omitempty"` // empty if the number is not stored yet
+	WhatsAppPhoneNumberID string `json:"whatsapp_phone_number_id,omitempty"`
+	DisplayPhoneNumber    string `json:"display_phone_number"`
+	Decision              string `json:"decision"` // APPROVED, REJECTED, DEFERRED
+	RequestedVerifiedName string `json:"requested_verified_name"`
+	RejectionReason       string `json:"rejection_reason,omitempty"`
+}
+
+// PhoneNumberSyncResult summarizes a phone number sync run
+type PhoneNumberSyncResult struct {
+	Created   int       `json:"created"`
+	Updated   int       `json:"updated"`
+	Unchanged int       `json:"unchanged"`
+	SyncedAt  time.Time `json:"synced_at"`
+}
+
+// SenderInfo describes a phone number that messages can be sent from
+type SenderInfo struct {
+	Name          string `json:"name"`
+	PhoneNumberID string `json:"phone_number_id"`
+	PhoneNumber   string `json:"phone_number"`
+	Default       bool   `json:"default"`
+}
+
+// PhoneNumberService tracks the phone numbers of the WhatsApp Business Account
+type PhoneNumberService struct {
+	phoneNumberRepo *repositories.PhoneNumberRepository
+	waClient        *whatsapp.Client
+	senders         *whatsapp.Registry
+	bus             *events.Bus
+	syncInterval    time.Duration
+	logger          *zap.Logger
+
+	syncMu sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPhoneNumberService creates a new phone number service
+func NewPhoneNumberService(
+	phoneNumberRepo *repositories.PhoneNumberRepository,
+	waClient *whatsapp.Client,
+	senders *whatsapp.Registry,
+	bus *events.Bus,
+	syncInterval time.Duration,
+	logger *zap.Logger,
+) *PhoneNumberService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &PhoneNumberService{
+		phoneNumberRepo: phoneNumberRepo,
+		waClient:        waClient,
+		senders:         senders,
+		bus:             bus,
+		syncInterval:    syncInterval,
+		logger:          logger,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// ListPhoneNumbers returns all known phone numbers
+func (s *PhoneNumberService) ListPhoneNumbers(ctx context.Context) ([]*models.PhoneNumber, error) {
+	phoneNumbers, err := s.phoneNumberRepo.ListAll(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	return phoneNumbers, nil
+}
+
+// ListSenders returns the sender phone numbers configured for this deployment
+func (s *PhoneNumberService) ListSenders(ctx context.Context) []SenderInfo {
+	senders := make([]SenderInfo, 0, len(s.senders.All()))
+	for _, sender := range s.senders.All() {
+		senders = append(senders, SenderInfo{
+			Name:          sender.Name,
+			PhoneNumberID: sender.PhoneNumberID(),
+			PhoneNumber:   sender.PhoneNumber(),
+			Default:       sender == s.senders.Default(),
+		})
+	}
+	return senders
+}
+
+// GetPhoneNumber retrieves a phone number by ID
+func (s *PhoneNumberService) GetPhoneNumber(ctx context.Context, phoneNumberID string) (*models.PhoneNumber, error) {
+	var phoneNumber models.PhoneNumber
+	if err := s.phoneNumberRepo.FindByID(ctx, phoneNumberID, &phoneNumber); err != nil {
+		return nil, errors.NewNotFound("Phone number", phoneNumberID)
+	}
+	return &phoneNumber, nil
+}
+
+// ListHistory returns the recorded quality, tier and status changes of a phone number
+func (s *PhoneNumberService) ListHistory(ctx context.Context, phoneNumberID string, pagination *utils.Pagination) ([]*models.PhoneNumberSnapshot, error) {
+	if _, err := s.GetPhoneNumber(ctx, phoneNumberID); err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.phoneNumberRepo.ListSnapshots(ctx, phoneNumberID, pagination)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	return snapshots, nil
+}
+
+// SyncPhoneNumbers refreshes the phone numbers from the WhatsApp Business Account.
+// A snapshot is recorded whenever a tracked field changes, and quality rating
+// changes are published on the event bus.
+func (s *PhoneNumberService) SyncPhoneNumbers(ctx context.Context) (*PhoneNumberSyncResult, error) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	remote, err := s.waClient.ListPhoneNumbers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	result := &PhoneNumberSyncResult{SyncedAt: now}
+
+	for i := range remote {
+		info := &remote[i]
+
+		phoneNumber, err := s.phoneNumberRepo.FindByWhatsAppPhoneNumberID(ctx, info.ID)
+		isNew := stderrors.Is(err, gorm.ErrRecordNotFound)
+		if err != nil && !isNew {
+			return nil, errors.NewDatabaseError(err)
+		}
+		if isNew {
+			phoneNumber = &models.PhoneNumber{WhatsAppPhoneNumberID: info.ID}
+		}
+
+		previousRating := phoneNumber.QualityRating
+		changed := applyPhoneNumberInfo(phoneNumber, info)
+		phoneNumber.LastSyncedAt = &now
+
+		var snapshot *models.PhoneNumberSnapshot
+		if isNew || changed {
+			snapshot = phoneNumber.Snapshot(now)
+		}
+
+		if err := s.phoneNumberRepo.SaveWithSnapshot(ctx, phoneNumber, snapshot); err != nil {
+			s.logger.Error("Failed to save phone number", zap.Error(err), zap.String("whatsapp_phone_number_id", info.ID))
+			continue
+		}
+
+		switch {
+		case isNew:
+			result.Created++
+		case changed:
+			result.Updated++
+		default:
+			result.Unchanged++
+		}
+
+		if !isNew && previousRating != phoneNumber.QualityRating {
+			s.publishQualityChange(ctx, phoneNumber, previousRating)
+		}
+	}
+
+	s.logger.Info("Phone numbers synced",
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+	)
+
+	return result, nil
+}
+
+// publishQualityChange logs and publishes a quality rating change
+func (s *PhoneNumberService) publishQualityChange(ctx context.Context, phoneNumber *models.PhoneNumber, previousRating string) {
+	event := QualityChangedEvent{
+		PhoneNumberID:         phoneNumber.ID,
+		WhatsAppPhoneNumberID: phoneNumber.WhatsAppPhoneNumberID,
+		DisplayPhoneNumber:    phoneNumber.DisplayPhoneNumber,
+		PreviousRating:        previousRating,
+		CurrentRating:         phoneNumber.QualityRating,
+		Downgraded:            whatsapp.QualityRank(phoneNumber.QualityRating) < whatsapp.QualityRank(previousRating),
+		MessagingLimitTier:    phoneNumber.MessagingLimitTier,
+	}
+
+	log := s.logger.Info
+	if event.Downgraded {
+		log = s.logger.Warn
+	}
+	log("Phone number quality rating changed",
+		zap.String("display_phone_number", event.DisplayPhoneNumber),
+		zap.String("previous", event.PreviousRating),
+		zap.String("current", event.CurrentRating),
+	)
+
+	s.bus.Publish(ctx, events.PhoneNumberQualityChanged, event)
+}
+
+// ApplyQualityUpdate applies a messaging limit or flagged state change reported
+// by webhook, records a snapshot and publishes it. Numbers that are not stored
+// yet are imported by the next sync; the change is published either way.
+func (s *PhoneNumberService) ApplyQualityUpdate(ctx context.Context, update *whatsapp.PhoneNumberQualityUpdate) error {
+	event := LimitChangedEvent{
+		DisplayPhoneNumber: update.DisplayPhoneNumber,
+		Event:              update.Event,
+		PreviousLimitTier:  update.OldLimit,
+		CurrentLimitTier:   update.CurrentLimit,
+	}
+
+	phoneNumber, err := s.findByDisplayPhoneNumber(ctx, update.DisplayPhoneNumber)
+	if err != nil {
+		return err
+	}
+
+	if phoneNumber != nil {
+		if event.PreviousLimitTier == "" {
+			event.PreviousLimitTier = phoneNumber.MessagingLimitTier
+		}
+
+		tier := phoneNumber.MessagingLimitTier
+		if update.CurrentLimit != "" {
+			tier = update.CurrentLimit
+		}
+		status := phoneNumber.Status
+		switch update.Event {
+		case "FLAGGED":
+			status = "FLAGGED"
+		case "UNFLAGGED":
+			if status == "FLAGGED" {
+				status = "CONNECTED"
+			}
+		}
+
+		if tier == phoneNumber.MessagingLimitTier && status == phoneNumber.Status {
+			return nil
+		}
+		phoneNumber.MessagingLimitTier = tier
+		phoneNumber.Status = status
+		if err := s.phoneNumberRepo.SaveWithSnapshot(ctx, phoneNumber, phoneNumber.Snapshot(time.Now().UTC())); err != nil {
+			return errors.NewDatabaseError(err)
+		}
+
+		event.PhoneNumberID = phoneNumber.ID
+		event.WhatsAppPhoneNumberID = phoneNumber.WhatsAppPhoneNumberID
+		event.CurrentLimitTier = tier
+		event.Status = status
+	}
+
+	log := s.logger.Info
+	if update.Event == "FLAGGED" || update.Event == "DOWNGRADE" {
+		log = s.logger.Warn
+	}
+	log("Phone number messaging limit changed",
+		zap.String("display_phone_number", event.DisplayPhoneNumber),
+		zap.String("event", event.Event),
+		zap.String("previous", event.PreviousLimitTier),
+		zap.String("current", event.CurrentLimitTier),
+	)
+
+	s.bus.Publish(ctx, events.PhoneNumberLimitChanged, event)
+	return nil
+}
+
+// ApplyNameUpdate applies a display name review decision reported by webhook,
+// records a snapshot and publishes it
+func (s *PhoneNumberService) ApplyNameUpdate(ctx context.Context, update *whatsapp.PhoneNumberNameUpdate) error {
+	event := NameReviewedEvent{
+		DisplayPhoneNumber:    update.DisplayPhoneNumber,
+		Decision:              update.Decision,
+		RequestedVerifiedName: update.RequestedVerifiedName,
+		RejectionReason:       rejectionReason(update.RejectionReason),
+	}
+
+	phoneNumber, err := s.findByDisplayPhoneNumber(ctx, update.DisplayPhoneNumber)
+	if err != nil {
+		return err
+	}
+
+	if phoneNumber != nil {
+		nameStatus := nameStatusFromDecision(update.Decision)
+		verifiedName := phoneNumber.VerifiedName
+		if update.Decision == "APPROVED" && update.RequestedVerifiedName != "" {
+			verifiedName = update.RequestedVerifiedName
+		}
+
+		if nameStatus == phoneNumber.NameStatus && verifiedName == phoneNumber.VerifiedName {
+			return nil
+		}
+		phoneNumber.NameStatus = nameStatus
+		phoneNumber.VerifiedName = verifiedName
+		if err := s.phoneNumberRepo.SaveWithSnapshot(ctx, phoneNumber, phoneNumber.Snapshot(time.Now().UTC())); err != nil {
+			return errors.NewDatabaseError(err)
+		}
+
+		event.PhoneNumberID = phoneNumber.ID
+		event.WhatsAppPhoneNumberID = phoneNumber.WhatsAppPhoneNumberID
+	}
+
+	s.logger.Info("Phone number display name reviewed",
+		zap.String("display_phone_number", event.DisplayPhoneNumber),
+		zap.String("decision", event.Decision),
+		zap.String("requested_verified_name", event.RequestedVerifiedName),
+	)
+
+	s.bus.Publish(ctx, events.PhoneNumberNameReviewed, event)
+	return nil
+}
+
+// findByDisplayPhoneNumber finds a stored phone number by its display number,
+// ignoring formatting since webhooks send only the digits. It returns nil if
+// the number is not stored.
+func (s *PhoneNumberService) findByDisplayPhoneNumber(ctx context.Context, displayPhoneNumber string) (*models.PhoneNumber, error) {
+	phoneNumbers, err := s.phoneNumberRepo.ListAll(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	digits := whatsapp.NormalizePhoneNumber(displayPhoneNumber)
+	for _, phoneNumber := range phoneNumbers {
+		if digits != "" && whatsapp.NormalizePhoneNumber(phoneNumber.DisplayPhoneNumber) == digits {
+			return phoneNumber, nil
+		}
+	}
+
+	s.logger.Info("Update for a phone number that is not stored yet",
+		zap.String("display_phone_number", displayPhoneNumber),
+	)
+	return nil, nil
+}
+
+// StartSync starts the periodic phone number sync if a business account is configured
+func (s *PhoneNumberService) StartSync() {
+	if !s.waClient.HasBusinessAccount() || s.syncInterval <= 0 {
+		s.logger.Info("Phone number sync disabled")
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.syncInterval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(s.ctx, phoneNumberSyncTimeout)
+			if _, err := s.SyncPhoneNumbers(ctx); err != nil && s.ctx.Err() == nil {
+				s.logger.Error("Periodic phone number sync failed", zap.Error(err))
+			}
+			cancel()
+
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// StopSync stops the periodic phone number sync, aborting a sync that is in progress
+func (s *PhoneNumberService) StopSync() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// applyPhoneNumberInfo copies the remote state onto the local record and reports
+// whether a tracked field (name status, quality, tier or status) changed
+func applyPhoneNumberInfo(phoneNumber *models.PhoneNumber, info *whatsapp.PhoneNumberInfo) bool {
+	changed := phoneNumber.NameStatus != info.NameStatus ||
+		phoneNumber.QualityRating != info.QualityRating ||
+		phoneNumber.MessagingLimitTier != info.MessagingLimitTier ||
+		phoneNumber.Status != info.Status
+
+	phoneNumber.DisplayPhoneNumber = info.DisplayPhoneNumber
+	phoneNumber.VerifiedName = info.VerifiedName
+	phoneNumber.NameStatus = info.NameStatus
+	phoneNumber.QualityRating = info.QualityRating
+	phoneNumber.MessagingLimitTier = info.MessagingLimitTier
+	phoneNumber.Status = info.Status
+	phoneNumber.ThroughputLevel = info.Throughput.Level
+
+	return changed
+}
+
+// nameStatusFromDecision maps a display name review decision to the name status
+// reported by the phone numbers API
+func nameStatusFromDecision(decision string) string {
+	switch decision {
+	case "APPROVED":
+		return "APPROVED"
+	case "REJECTED":
+		return "DECLINED"
+	case "DEFERRED":
+		return "PENDING_REVIEW"
+	default:
+		return decision
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/storage"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// RecordingService stores and serves call recordings
+type RecordingService struct {
+	callRepo *repositories.CallRepository
+	storage  storage.Storage
+	logger   *zap.Logger
+}
+
+// NewRecordingService creates a new recording service
+func NewRecordingService(
+	callRepo *repositories.CallRepository,
+	store storage.Storage,
+	logger *zap.Logger,
+) *RecordingService {
+	return &RecordingService{
+		callRepo: callRepo,
+		storage:  store,
+		logger:   logger,
+	}
+}
+
+// SaveRecording stores the recording of a call and links it to the call record
+func (s *RecordingService) SaveRecording(ctx context.Context, callID string, r io.Reader, size int64, contentType string) (*models.Call, error) {
+	var call models.Call
+	if err := s.callRepo.FindByID(ctx, callID, &call); err != nil {
+		return nil, errors.NewNotFound("Call", callID)
+	}
+
+	key := call.ID + mediaExtension(contentType)
+	if err := s.storage.Put(ctx, key, r, size, contentType); err != nil {
+		s.logger.Error("Failed to store call recording", zap.Error(err), zap.String("call_id", callID))
+		return nil, errors.NewInternalError(err)
+	}
+
+	if err := s.callRepo.UpdateFields(ctx, call.ID, &call, map[string]interface{}{
+		"recording_key": key,
+		"recording_url": fmt.Sprintf("/api/v1/calls/%s/recording", call.ID),
+	}); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	return &call, nil
+}
+
+// OpenRecording opens the stored recording of a call.
+// The caller must close the returned reader.
+func (s *RecordingService) OpenRecording(ctx context.Context, callID string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	call, err := s.recordedCall(ctx, callID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader, info, err := s.storage.Get(ctx, call.RecordingKey)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, nil, errors.NewNotFound("Recording", callID)
+		}
+		return nil, nil, errors.NewInternalError(err)
+	}
+
+	return reader, info, nil
+}
+
+// PresignRecordingURL returns a temporary direct download URL for a recording.
+// It returns storage.ErrPresignNotSupported for backends that cannot presign.
+func (s *RecordingService) PresignRecordingURL(ctx context.Context, callID string, expires time.Duration) (string, error) {
+	call, err := s.recordedCall(ctx, callID)
+	if err != nil {
+		return "", err
+	}
+
+	url, err := s.storage.PresignGet(ctx, call.RecordingKey, expires)
+	if err != nil && err != storage.ErrPresignNotSupported {
+		return "", errors.NewInternalError(err)
+	}
+	return url, err
+}
+
+// recordedCall returns a call that has a stored recording
+func (s *RecordingService) recordedCall(ctx context.Context, callID string) (*models.Call, error) {
+	var call models.Call
+	if err := s.callRepo.FindByID(ctx, callID, &call); err != nil {
+		return nil, errors.NewNotFound("Call", callID)
+	}
+	if call.RecordingKey == "" {
+		return nil, errors.NewNotFound("Recording", callID)
+	}
+	return &call, nil
+}
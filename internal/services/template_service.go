@@ -1,45 +1,172 @@
 package services
 
 import (
+	"context"
+	stderrors "errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/events"
 	"github.com/ashok/vibecoded-wa-client/internal/models"
 	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
 	"github.com/ashok/vibecoded-wa-client/pkg/errors"
 	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// templateNamePattern matches the names WhatsApp accepts for templates
+var templateNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,512}$`)
+
+// serverOwnedTemplateFields are set by WhatsApp and cannot be changed through the API
+var serverOwnedTemplateFields = []string{
+	"id", "whatsapp_template_id", "status", "rejection_reason", "quality_score", "last_synced_at", "status_changed_at", "created_at", "updated_at",
+}
+
+// syncedTemplateFields cannot be edited locally once a template exists in WhatsApp
+var syncedTemplateFields = []string{"name", "language", "category", "content", "parameters", "components"}
+
+// templateSyncTimeout bounds a single periodic sync run
+const templateSyncTimeout = 2 * time.Minute
+
+// TemplateSyncResult summarises a reconciliation with the WhatsApp Business Account
+type TemplateSyncResult struct {
+	Created   int       `json:"created"`
+	Updated   int       `json:"updated"`
+	Unchanged int       `json:"unchanged"`
+	Removed   int       `json:"removed"`
+	SyncedAt  time.Time `json:"synced_at"`
+}
+
+// TemplateStatusChangedEvent is published when WhatsApp reports a template review
+// decision or status change
+type TemplateStatusChangedEvent struct {
+	TemplateID         string `json:"template_id,omitempty"` // empty if the template is not stored yet
+	WhatsAppTemplateID string `json:"whatsapp_template_id"`
+	Name               string `json:"name"`
+	Language           string `json:"language"`
+	Event              string `json:"event"`
+	PreviousStatus     string `json:"previous_status,omitempty"`
+	Status             string `json:"status,omitempty"`
+	Reason             string `json:"reason,omitempty"`
+	Title              string `json:"title,omitempty"`
+	Description        string `json:"description,omitempty"`
+}
+
+// TemplateQualityChangedEvent is published when the quality score of a template changes
+type TemplateQualityChangedEvent struct {
+	TemplateID         string `json:"template_id,omitempty"` // empty if the template is not stored yet
+	WhatsAppTemplateID string `json:"whatsapp_template_id"`
+	Name               string `json:"name"`
+	Language           string `json:"language"`
+	PreviousScore      string `json:"previous_score"`
+	CurrentScore       string `json:"current_score"`
+}
+
 // TemplateService handles template business logic
 type TemplateService struct {
 	templateRepo *repositories.TemplateRepository
+	waClient     *whatsapp.Client
+	bus          *events.Bus
+	syncInterval time.Duration
+	logger       *zap.Logger
+
+	syncMu sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 // NewTemplateService creates a new template service
-func NewTemplateService(templateRepo *repositories.TemplateRepository) *TemplateService {
+func NewTemplateService(
+	templateRepo *repositories.TemplateRepository,
+	waClient *whatsapp.Client,
+	bus *events.Bus,
+	syncInterval time.Duration,
+	logger *zap.Logger,
+) *TemplateService {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &TemplateService{
 		templateRepo: templateRepo,
+		waClient:     waClient,
+		bus:          bus,
+		syncInterval: syncInterval,
+		logger:       logger,
+		ctx:          ctx,
+		cancel:       cancel,
 	}
 }
 
-// CreateTemplate creates a new template
-func (s *TemplateService) CreateTemplate(template *models.Template) error {
+// CreateTemplate submits a new template to WhatsApp for review and stores it locally.
+// The status is always taken from WhatsApp, never from the caller. Without a
+// configured business account the template is only stored locally as pending.
+func (s *TemplateService) CreateTemplate(ctx context.Context, template *models.Template) error {
+	template.Status = models.TemplateStatusPending
+	template.WhatsAppTemplateID = ""
+	template.RejectionReason = ""
+
+	// With structured components the body text doubles as the content
+	if template.Content == "" {
+		if body := template.Component("BODY"); body != nil {
+			template.Content = body.Text
+		}
+	}
+
 	if err := template.Validate(); err != nil {
 		return errors.NewBadRequest(err.Error())
 	}
+	if !templateNamePattern.MatchString(template.Name) {
+		return errors.NewBadRequest("name may only contain lowercase letters, digits and underscores")
+	}
+
+	if _, err := s.templateRepo.FindByName(ctx, template.Name, template.Language); err == nil {
+		return errors.NewConflict("a template with this name and language already exists")
+	}
 
-	return s.templateRepo.Create(template)
+	if s.waClient.HasBusinessAccount() {
+		resp, err := s.waClient.CreateTemplate(ctx, templateDefinition(template))
+		if err != nil {
+			s.logger.Error("Failed to submit template", zap.Error(err), zap.String("name", template.Name))
+			return err
+		}
+
+		now := time.Now().UTC()
+		template.WhatsAppTemplateID = resp.ID
+		template.LastSyncedAt = &now
+		if resp.Status != "" {
+			template.Status = strings.ToLower(resp.Status)
+		}
+		// WhatsApp may recategorise a template on submission
+		if resp.Category != "" {
+			template.Category = strings.ToLower(resp.Category)
+		}
+	} else {
+		s.logger.Warn("Business account not configured, template stored locally only",
+			zap.String("name", template.Name),
+		)
+	}
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return errors.NewDatabaseError(err)
+	}
+	return nil
 }
 
 // GetTemplate gets a template by ID
-func (s *TemplateService) GetTemplate(templateID string) (*models.Template, error) {
+func (s *TemplateService) GetTemplate(ctx context.Context, templateID string) (*models.Template, error) {
 	var template models.Template
-	if err := s.templateRepo.FindByID(templateID, &template); err != nil {
+	if err := s.templateRepo.FindByID(ctx, templateID, &template); err != nil {
 		return nil, errors.NewNotFound("Template", templateID)
 	}
 	return &template, nil
 }
 
 // GetTemplateByName gets a template by name and language
-func (s *TemplateService) GetTemplateByName(name, language string) (*models.Template, error) {
-	template, err := s.templateRepo.FindByName(name, language)
+func (s *TemplateService) GetTemplateByName(ctx context.Context, name, language string) (*models.Template, error) {
+	template, err := s.templateRepo.FindByName(ctx, name, language)
 	if err != nil {
 		return nil, errors.NewNotFound("Template", name)
 	}
@@ -47,35 +174,483 @@ func (s *TemplateService) GetTemplateByName(name, language string) (*models.Temp
 }
 
 // ListTemplates lists all templates
-func (s *TemplateService) ListTemplates(pagination *utils.Pagination) ([]*models.Template, error) {
-	return s.templateRepo.ListAll(pagination)
+func (s *TemplateService) ListTemplates(ctx context.Context, pagination *utils.Pagination) ([]*models.Template, error) {
+	return s.templateRepo.ListAll(ctx, pagination)
 }
 
-// UpdateTemplate updates a template
-func (s *TemplateService) UpdateTemplate(templateID string, updates map[string]interface{}) (*models.Template, error) {
+// UpdateTemplate updates a template. Fields owned by WhatsApp are ignored, and
+// templates that exist in WhatsApp can only have their local metadata changed.
+func (s *TemplateService) UpdateTemplate(ctx context.Context, templateID string, updates map[string]interface{}) (*models.Template, error) {
 	var template models.Template
-	if err := s.templateRepo.FindByID(templateID, &template); err != nil {
+	if err := s.templateRepo.FindByID(ctx, templateID, &template); err != nil {
 		return nil, errors.NewNotFound("Template", templateID)
 	}
 
-	if err := s.templateRepo.UpdateFields(templateID, &template, updates); err != nil {
+	for _, field := range serverOwnedTemplateFields {
+		delete(updates, field)
+	}
+	if template.IsSynced() {
+		for _, field := range syncedTemplateFields {
+			if _, ok := updates[field]; ok {
+				return nil, errors.NewBadRequest(field + " cannot be changed after a template has been submitted to WhatsApp")
+			}
+		}
+	}
+	if len(updates) == 0 {
+		return &template, nil
+	}
+
+	if err := s.templateRepo.UpdateFields(ctx, templateID, &template, updates); err != nil {
 		return nil, errors.NewDatabaseError(err)
 	}
 
 	// Fetch updated template
-	if err := s.templateRepo.FindByID(templateID, &template); err != nil {
+	if err := s.templateRepo.FindByID(ctx, templateID, &template); err != nil {
 		return nil, errors.NewDatabaseError(err)
 	}
 
 	return &template, nil
 }
 
-// DeleteTemplate deletes a template
-func (s *TemplateService) DeleteTemplate(templateID string) error {
+// DeleteTemplate deletes a template from WhatsApp and then locally
+func (s *TemplateService) DeleteTemplate(ctx context.Context, templateID string) error {
 	var template models.Template
-	if err := s.templateRepo.FindByID(templateID, &template); err != nil {
+	if err := s.templateRepo.FindByID(ctx, templateID, &template); err != nil {
 		return errors.NewNotFound("Template", templateID)
 	}
 
-	return s.templateRepo.Delete(&template)
+	if template.IsSynced() {
+		if err := s.waClient.DeleteTemplate(ctx, template.Name, template.WhatsAppTemplateID); err != nil {
+			s.logger.Error("Failed to delete template in WhatsApp", zap.Error(err), zap.String("template_id", templateID))
+			return err
+		}
+	}
+
+	return s.templateRepo.Delete(ctx, &template)
+}
+
+// SyncTemplates reconciles local templates with the WhatsApp Business Account.
+// Remote templates are created or updated locally, and synced templates that
+// no longer exist remotely are marked as deleted.
+func (s *TemplateService) SyncTemplates(ctx context.Context) (*TemplateSyncResult, error) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	remote, err := s.waClient.ListTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	result := &TemplateSyncResult{SyncedAt: now}
+	seen := make(map[string]bool, len(remote))
+
+	for i := range remote {
+		definition := &remote[i]
+		seen[definition.ID] = true
+
+		local, err := s.templateRepo.FindByWhatsAppTemplateID(ctx, definition.ID)
+		if err != nil {
+			// Templates created before syncing existed are matched by name and language
+			local, err = s.templateRepo.FindByName(ctx, definition.Name, definition.Language)
+		}
+
+		if err != nil {
+			template := templateFromDefinition(definition, now)
+			if err := s.templateRepo.Create(ctx, template); err != nil {
+				s.logger.Error("Failed to import template",
+					zap.Error(err),
+					zap.String("name", definition.Name),
+					zap.String("language", definition.Language),
+				)
+				continue
+			}
+			result.Created++
+			continue
+		}
+
+		changed, err := s.applyDefinition(ctx, local, definition, now)
+		if err != nil {
+			s.logger.Error("Failed to update template", zap.Error(err), zap.String("template_id", local.ID))
+			continue
+		}
+		if changed {
+			result.Updated++
+		} else {
+			result.Unchanged++
+		}
+	}
+
+	// Mark templates that were deleted in WhatsApp Manager
+	synced, err := s.templateRepo.FindSynced(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	for _, template := range synced {
+		if seen[template.WhatsAppTemplateID] || template.Status == models.TemplateStatusDeleted {
+			continue
+		}
+		if err := s.templateRepo.UpdateFields(ctx, template.ID, template, map[string]interface{}{
+			"status":         models.TemplateStatusDeleted,
+			"last_synced_at": now,
+		}); err != nil {
+			s.logger.Error("Failed to mark template deleted", zap.Error(err), zap.String("template_id", template.ID))
+			continue
+		}
+		result.Removed++
+	}
+
+	s.logger.Info("Templates synced",
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+		zap.Int("removed", result.Removed),
+	)
+
+	return result, nil
+}
+
+// ApplyStatusUpdate applies a template status change reported by webhook, so that
+// review decisions show up without waiting for the next sync. Templates that are
+// not stored yet are imported by the next sync; the change is published either way.
+// sentAt is when WhatsApp sent the change: changes sent before the current status
+// was reported arrive late or are retried, and are skipped so that they do not
+// undo a newer one. A zero sentAt is always applied.
+func (s *TemplateService) ApplyStatusUpdate(ctx context.Context, update *whatsapp.TemplateStatusUpdate, sentAt time.Time) error {
+	event := TemplateStatusChangedEvent{
+		WhatsAppTemplateID: update.MessageTemplateID.String(),
+		Name:               update.MessageTemplateName,
+		Language:           update.MessageTemplateLanguage,
+		Event:              update.Event,
+		Reason:             rejectionReason(update.Reason),
+	}
+	if update.OtherInfo != nil {
+		event.Title = update.OtherInfo.Title
+		event.Description = update.OtherInfo.Description
+	}
+
+	status, hasStatus := templateStatusFromEvent(update.Event)
+	changed := true
+
+	template, err := s.findWebhookTemplate(ctx, event.WhatsAppTemplateID, event.Name, event.Language)
+	switch {
+	case err != nil:
+		return err
+	case template == nil:
+		s.logger.Info("Status update for a template that is not stored yet",
+			zap.String("name", event.Name),
+			zap.String("language", event.Language),
+			zap.String("event", update.Event),
+		)
+		if hasStatus {
+			event.Status = status
+		}
+	default:
+		event.TemplateID = template.ID
+		event.PreviousStatus = template.Status
+		event.Status = template.Status
+
+		if hasStatus && !sentAt.IsZero() && template.StatusChangedAt != nil && sentAt.Before(*template.StatusChangedAt) {
+			s.logger.Info("Skipping outdated template status update",
+				zap.String("template_id", template.ID),
+				zap.String("event", update.Event),
+				zap.Time("sent_at", sentAt),
+				zap.Time("status_changed_at", *template.StatusChangedAt),
+			)
+			return nil
+		}
+
+		if hasStatus {
+			reason := ""
+			if status == models.TemplateStatusRejected {
+				reason = event.Reason
+			}
+			changed = template.Status != status || template.RejectionReason != reason
+			updates := map[string]interface{}{}
+			if changed {
+				updates["status"] = status
+				updates["rejection_reason"] = reason
+				if template.WhatsAppTemplateID == "" {
+					updates["whatsapp_template_id"] = event.WhatsAppTemplateID
+				}
+			}
+			if !sentAt.IsZero() && (changed || template.StatusChangedAt == nil || sentAt.After(*template.StatusChangedAt)) {
+				updates["status_changed_at"] = sentAt.UTC()
+			}
+			if len(updates) > 0 {
+				if err := s.templateRepo.UpdateFields(ctx, template.ID, template, updates); err != nil {
+					return errors.NewDatabaseError(err)
+				}
+			}
+			if changed {
+				event.Status = status
+			}
+		}
+
+		s.logger.Info("Template status updated by webhook",
+			zap.String("template_id", template.ID),
+			zap.String("event", update.Event),
+			zap.String("status", event.Status),
+		)
+	}
+
+	// Redeliveries of a status that is already applied are not published again
+	if changed {
+		s.bus.Publish(ctx, events.TemplateStatusChanged, event)
+	}
+	return nil
+}
+
+// ApplyQualityUpdate stores a template quality score change reported by webhook
+// and publishes it
+func (s *TemplateService) ApplyQualityUpdate(ctx context.Context, update *whatsapp.TemplateQualityUpdate) error {
+	event := TemplateQualityChangedEvent{
+		WhatsAppTemplateID: update.MessageTemplateID.String(),
+		Name:               update.MessageTemplateName,
+		Language:           update.MessageTemplateLanguage,
+		PreviousScore:      update.PreviousQualityScore,
+		CurrentScore:       update.NewQualityScore,
+	}
+
+	template, err := s.findWebhookTemplate(ctx, event.WhatsAppTemplateID, event.Name, event.Language)
+	if err != nil {
+		return err
+	}
+	if template != nil {
+		if template.QualityScore == update.NewQualityScore {
+			return nil
+		}
+		if err := s.templateRepo.UpdateFields(ctx, template.ID, template, map[string]interface{}{
+			"quality_score": update.NewQualityScore,
+		}); err != nil {
+			return errors.NewDatabaseError(err)
+		}
+		event.TemplateID = template.ID
+	}
+
+	log := s.logger.Info
+	if whatsapp.QualityRank(event.CurrentScore) < whatsapp.QualityRank(event.PreviousScore) {
+		log = s.logger.Warn
+	}
+	log("Template quality score changed",
+		zap.String("name", event.Name),
+		zap.String("language", event.Language),
+		zap.String("previous", event.PreviousScore),
+		zap.String("current", event.CurrentScore),
+	)
+
+	s.bus.Publish(ctx, events.TemplateQualityChanged, event)
+	return nil
+}
+
+// findWebhookTemplate finds the template a webhook refers to by its WhatsApp ID,
+// falling back to name and language. It returns nil if the template is not stored.
+func (s *TemplateService) findWebhookTemplate(ctx context.Context, waTemplateID, name, language string) (*models.Template, error) {
+	if waTemplateID != "" {
+		template, err := s.templateRepo.FindByWhatsAppTemplateID(ctx, waTemplateID)
+		if err == nil {
+			return template, nil
+		}
+		if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.NewDatabaseError(err)
+		}
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	template, err := s.templateRepo.FindByName(ctx, name, language)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.NewDatabaseError(err)
+	}
+	return template, nil
+}
+
+// StartSync starts the periodic template sync if a business account is configured
+func (s *TemplateService) StartSync() {
+	if !s.waClient.HasBusinessAccount() || s.syncInterval <= 0 {
+		s.logger.Info("Template sync disabled")
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.syncInterval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(s.ctx, templateSyncTimeout)
+			if _, err := s.SyncTemplates(ctx); err != nil && s.ctx.Err() == nil {
+				s.logger.Error("Periodic template sync failed", zap.Error(err))
+			}
+			cancel()
+
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// StopSync stops the periodic template sync, aborting a sync that is in progress
+func (s *TemplateService) StopSync() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// applyDefinition updates a local template from its WhatsApp definition and reports whether anything changed
+func (s *TemplateService) applyDefinition(ctx context.Context, template *models.Template, definition *whatsapp.TemplateDefinition, now time.Time) (bool, error) {
+	status := strings.ToLower(definition.Status)
+	category := strings.ToLower(definition.Category)
+	reason := rejectionReason(definition.RejectedReason)
+	quality := qualityScore(definition.QualityScore)
+
+	changed := template.WhatsAppTemplateID != definition.ID ||
+		template.Status != status ||
+		template.Category != category ||
+		template.RejectionReason != reason ||
+		template.QualityScore != quality
+
+	updates := map[string]interface{}{
+		"whatsapp_template_id": definition.ID,
+		"status":               status,
+		"category":             category,
+		"rejection_reason":     reason,
+		"quality_score":        quality,
+		"last_synced_at":       now,
+		"components":           templateComponents(definition.Components),
+	}
+	if template.Status != status {
+		updates["status_changed_at"] = now
+	}
+	if body := definition.BodyText(); body != "" {
+		changed = changed || template.Content != body
+		updates["content"] = body
+	}
+
+	return changed, s.templateRepo.UpdateFields(ctx, template.ID, template, updates)
+}
+
+// templateDefinition converts a local template into a WhatsApp template submission.
+// Templates without structured components are submitted as a body-only template.
+func templateDefinition(template *models.Template) *whatsapp.TemplateDefinition {
+	definition := &whatsapp.TemplateDefinition{
+		Name:     template.Name,
+		Language: template.Language,
+		Category: strings.ToUpper(template.Category),
+	}
+
+	if len(template.Components) > 0 {
+		for _, component := range template.Components {
+			buttons := make([]whatsapp.TemplateDefinitionButton, len(component.Buttons))
+			for i, button := range component.Buttons {
+				buttons[i] = whatsapp.TemplateDefinitionButton{
+					Type:        strings.ToUpper(button.Type),
+					Text:        button.Text,
+					URL:         button.URL,
+					PhoneNumber: button.PhoneNumber,
+					Example:     button.Example,
+				}
+			}
+			definition.Components = append(definition.Components, whatsapp.TemplateDefinitionComponent{
+				Type:    strings.ToUpper(component.Type),
+				Format:  strings.ToUpper(component.Format),
+				Text:    component.Text,
+				Example: component.Example,
+				Buttons: buttons,
+			})
+		}
+		return definition
+	}
+
+	body := whatsapp.TemplateDefinitionComponent{
+		Type: "BODY",
+		Text: template.Content,
+	}
+	if len(template.Parameters) > 0 {
+		body.Example = map[string]interface{}{
+			"body_text": [][]string{template.Parameters},
+		}
+	}
+	definition.Components = []whatsapp.TemplateDefinitionComponent{body}
+
+	return definition
+}
+
+// templateComponents converts WhatsApp definition components for local storage
+func templateComponents(components []whatsapp.TemplateDefinitionComponent) models.TemplateComponents {
+	result := make(models.TemplateComponents, len(components))
+	for i, component := range components {
+		buttons := make([]models.TemplateButton, len(component.Buttons))
+		for j, button := range component.Buttons {
+			buttons[j] = models.TemplateButton{
+				Type:        button.Type,
+				Text:        button.Text,
+				URL:         button.URL,
+				PhoneNumber: button.PhoneNumber,
+				Example:     button.Example,
+			}
+		}
+		result[i] = models.TemplateComponent{
+			Type:    component.Type,
+			Format:  component.Format,
+			Text:    component.Text,
+			Example: component.Example,
+			Buttons: buttons,
+		}
+	}
+	return result
+}
+
+// templateFromDefinition creates a local template for one that only exists in WhatsApp
+func templateFromDefinition(definition *whatsapp.TemplateDefinition, now time.Time) *models.Template {
+	return &models.Template{
+		WhatsAppTemplateID: definition.ID,
+		Name:               definition.Name,
+		Language:           definition.Language,
+		Category:           strings.ToLower(definition.Category),
+		Status:             strings.ToLower(definition.Status),
+		RejectionReason:    rejectionReason(definition.RejectedReason),
+		QualityScore:       qualityScore(definition.QualityScore),
+		Content:            definition.BodyText(),
+		Components:         templateComponents(definition.Components),
+		LastSyncedAt:       &now,
+		StatusChangedAt:    &now,
+	}
+}
+
+// qualityScore returns the score of a template quality rating, or an empty string if it has none
+func qualityScore(quality *whatsapp.TemplateQualityScore) string {
+	if quality == nil {
+		return ""
+	}
+	return quality.Score
+}
+
+// templateStatusFromEvent maps a template status webhook event to the status it
+// leaves the template in. FLAGGED only warns that the template will be paused
+// or disabled, so it does not change the status.
+func templateStatusFromEvent(event string) (string, bool) {
+	switch event {
+	case "", "FLAGGED":
+		return "", false
+	case "REINSTATED":
+		return models.TemplateStatusApproved, true
+	default:
+		return strings.ToLower(event), true
+	}
+}
+
+// rejectionReason normalises the reason WhatsApp reports, which is "NONE" for templates that were not rejected
+func rejectionReason(reason string) string {
+	if reason == "NONE" {
+		return ""
+	}
+	return reason
 }
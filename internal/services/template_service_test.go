@@ -0,0 +1,82 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/events"
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"go.uber.org/zap"
+)
+
+func TestApplyStatusUpdateSkipsOutdatedChanges(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	log := zap.NewNop()
+
+	bus := events.NewBus(log)
+	var published []string
+	bus.Subscribe(events.TemplateStatusChanged, func(ctx context.Context, event events.Event) {
+		published = append(published, event.Payload.(services.TemplateStatusChangedEvent).Status)
+	})
+
+	service := services.NewTemplateService(repositories.NewTemplateRepository(db), nil, bus, 0, log)
+
+	template := &models.Template{
+		WhatsAppTemplateID: "1234567890",
+		Name:               "order_update",
+		Language:           "en_US",
+		Category:           models.TemplateCategoryUtility,
+		Status:             models.TemplateStatusPending,
+		Content:            "Your order {{1}} has shipped",
+	}
+	if err := db.Create(template).Error; err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	sentAt := time.Unix(1700000000, 0)
+	apply := func(event string, at time.Time) {
+		t.Helper()
+		update := &whatsapp.TemplateStatusUpdate{
+			Event:                   event,
+			MessageTemplateID:       "1234567890",
+			MessageTemplateName:     "order_update",
+			MessageTemplateLanguage: "en_US",
+		}
+		if err := service.ApplyStatusUpdate(ctx, update, at); err != nil {
+			t.Fatalf("ApplyStatusUpdate(%s) failed: %v", event, err)
+		}
+	}
+	stored := func() *models.Template {
+		t.Helper()
+		var stored models.Template
+		if err := db.First(&stored, "id = ?", template.ID).Error; err != nil {
+			t.Fatalf("Failed to load template: %v", err)
+		}
+		return &stored
+	}
+
+	apply("APPROVED", sentAt)
+	if s := stored(); s.Status != models.TemplateStatusApproved || s.StatusChangedAt == nil || !s.StatusChangedAt.Equal(sentAt) {
+		t.Fatalf("Expected the template to be approved at %v, got %s at %v", sentAt, s.Status, s.StatusChangedAt)
+	}
+
+	// A PENDING sent before the approval but delivered after it must not undo it
+	apply("PENDING", sentAt.Add(-time.Minute))
+	if s := stored(); s.Status != models.TemplateStatusApproved || !s.StatusChangedAt.Equal(sentAt) {
+		t.Errorf("Expected the outdated update to be skipped, got %s at %v", s.Status, s.StatusChangedAt)
+	}
+
+	apply("PAUSED", sentAt.Add(time.Minute))
+	if s := stored(); s.Status != models.TemplateStatusPaused || !s.StatusChangedAt.Equal(sentAt.Add(time.Minute)) {
+		t.Errorf("Expected the newer update to be applied, got %s at %v", s.Status, s.StatusChangedAt)
+	}
+
+	if len(published) != 2 || published[0] != models.TemplateStatusApproved || published[1] != models.TemplateStatusPaused {
+		t.Errorf("Expected approved and paused to be published, got %v", published)
+	}
+}
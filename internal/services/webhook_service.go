@@ -0,0 +1,657 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/events"
+	"github.com/ashok/vibecoded-wa-client/internal/metrics"
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Webhook processing settings
+const (
+	webhookWorkers        = 4
+	webhookQueue          = 500
+	webhookMaxAttempts    = 8
+	webhookRetryBackoff   = 30 * time.Second
+	webhookMaxBackoff     = time.Hour
+	webhookSweepInterval  = 30 * time.Second
+	webhookSweepBatch     = 100
+	webhookProcessTimeout = 2 * time.Minute
+	webhookStatusTimeout  = 5 * time.Second
+
+	// Security log settings: at most one rejection is stored per source IP and
+	// reason in each window, for up to webhookRejectionSources sources at a time,
+	// and entries are kept for webhookRejectionRetention
+	webhookRejectionWindow    = time.Minute
+	webhookRejectionSources   = 10000
+	webhookRejectionRetention = 30 * 24 * time.Hour
+	webhookRejectionPurge     = time.Hour
+
+	// webhookStaleAfter is how long an event may stay claimed before it is
+	// assumed abandoned by its worker
+	webhookStaleAfter = webhookProcessTimeout + 3*time.Minute
+)
+
+// errPermanent marks processing errors that retrying cannot fix
+var errPermanent = stderrors.New("permanent failure")
+
+// AccountUpdatedEvent is published for an account_update webhook, such as a
+// verification, restriction or policy violation of the business account
+type AccountUpdatedEvent struct {
+	BusinessAccountID string          `json:"business_account_id"`
+	PhoneNumber       string          `json:"phone_number,omitempty"`
+	Event             string          `json:"event"`
+	Details           json.RawMessage `json:"details"` // the full webhook value, including ban and restriction info
+}
+
+// AccountAlertEvent is published for an account_alerts webhook
+type AccountAlertEvent struct {
+	BusinessAccountID string `json:"business_account_id"`
+	whatsapp.AccountAlert
+}
+
+// CapabilityUpdatedEvent is published when the messaging capabilities of the
+// business account change
+type CapabilityUpdatedEvent struct {
+	BusinessAccountID string `json:"business_account_id"`
+	whatsapp.BusinessCapabilityUpdate
+}
+
+// pendingPublish is an event bus notice held back until the webhook event it
+// came from is stored as processed
+type pendingPublish struct {
+	eventType events.Type
+	payload   interface{}
+}
+
+// WebhookService stores incoming webhooks and processes them in the background.
+// Payloads are persisted before WhatsApp gets its response, so a failure while
+// handling them is retried with backoff instead of losing the delivery.
+type WebhookService struct {
+	eventRepo          *repositories.WebhookEventRepository
+	rejectionRepo      *repositories.WebhookRejectionRepository
+	messageService     *MessageService
+	templateService    *TemplateService
+	phoneNumberService *PhoneNumberService
+	bus                *events.Bus
+	logger             *zap.Logger
+
+	rejections *rejectionThrottle
+
+	queue  chan string
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(
+	eventRepo *repositories.WebhookEventRepository,
+	rejectionRepo *repositories.WebhookRejectionRepository,
+	messageService *MessageService,
+	templateService *TemplateService,
+	phoneNumberService *PhoneNumberService,
+	bus *events.Bus,
+	logger *zap.Logger,
+) *WebhookService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookService{
+		eventRepo:          eventRepo,
+		rejectionRepo:      rejectionRepo,
+		messageService:     messageService,
+		templateService:    templateService,
+		phoneNumberService: phoneNumberService,
+		bus:                bus,
+		logger:             logger,
+		rejections:         newRejectionThrottle(webhookRejectionWindow, webhookRejectionSources),
+		queue:              make(chan string, webhookQueue),
+		ctx:                ctx,
+		cancel:             cancel,
+	}
+}
+
+// Receive stores a webhook payload and schedules it for processing. Payloads that
+// are not valid JSON are rejected; everything else is kept, even if it later fails.
+func (s *WebhookService) Receive(ctx context.Context, body []byte) (*models.WebhookEvent, error) {
+	payload, err := whatsapp.ParseWebhook(body)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid webhook payload")
+	}
+
+	event := &models.WebhookEvent{
+		Object:  payload.Object,
+		Payload: models.RawJSON(body),
+		Status:  models.WebhookEventStatusPending,
+	}
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+
+	s.enqueue(event.ID)
+	return event, nil
+}
+
+// GetEvent gets a webhook event by ID
+func (s *WebhookService) GetEvent(ctx context.Context, eventID string) (*models.WebhookEvent, error) {
+	var event models.WebhookEvent
+	if err := s.eventRepo.FindByID(ctx, eventID, &event); err != nil {
+		return nil, errors.NewNotFound("Webhook event", eventID)
+	}
+	return &event, nil
+}
+
+// ListEvents lists webhook events with filters
+func (s *WebhookService) ListEvents(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.WebhookEvent, error) {
+	events, err := s.eventRepo.ListWithFilters(ctx, filters, pagination)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	return events, nil
+}
+
+// ReprocessEvent schedules a webhook event to be processed again with a fresh
+// attempt budget, whatever its current status
+func (s *WebhookService) ReprocessEvent(ctx context.Context, eventID string) (*models.WebhookEvent, error) {
+	if _, err := s.GetEvent(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	requeued, err := s.eventRepo.Requeue(ctx, eventID)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	if !requeued {
+		return nil, errors.NewConflict("webhook event is being processed")
+	}
+
+	s.logger.Info("Webhook event requeued", zap.String("event_id", eventID))
+	s.enqueue(eventID)
+
+	return s.GetEvent(ctx, eventID)
+}
+
+// ReprocessEvents schedules every failed or dead webhook event in the given
+// status to be processed again and returns how many were requeued
+func (s *WebhookService) ReprocessEvents(ctx context.Context, status string) (int, error) {
+	if status != models.WebhookEventStatusFailed && status != models.WebhookEventStatusDead {
+		return 0, errors.NewBadRequest("status must be failed or dead")
+	}
+
+	ids, err := s.eventRepo.RequeueByStatus(ctx, status)
+	if err != nil {
+		return 0, errors.NewDatabaseError(err)
+	}
+
+	s.logger.Info("Webhook events requeued", zap.String("status", status), zap.Int("count", len(ids)))
+	for _, id := range ids {
+		s.enqueue(id)
+	}
+
+	return len(ids), nil
+}
+
+// RecordRejection adds a refused webhook request to the security log. Only one
+// rejection per source IP and reason is stored in each window; the others are
+// counted on the next stored entry. Failing to store it is logged rather than
+// returned, since the request is refused anyway.
+func (s *WebhookService) RecordRejection(ctx context.Context, rejection *models.WebhookRejection) {
+	metrics.WebhookRejections.Inc(rejection.Reason)
+
+	store, suppressed := s.rejections.allow(rejection.SourceIP+"|"+rejection.Reason, time.Now())
+	if !store {
+		return
+	}
+	rejection.Suppressed = suppressed
+	s.logger.Warn("Webhook request rejected",
+		zap.String("reason", rejection.Reason),
+		zap.String("source_ip", rejection.SourceIP),
+		zap.String("remote_ip", rejection.RemoteIP),
+		zap.String("user_agent", rejection.UserAgent),
+		zap.Int64("body_size", rejection.BodySize),
+		zap.Int64("suppressed", rejection.Suppressed),
+	)
+
+	if err := s.rejectionRepo.Create(ctx, rejection); err != nil {
+		s.logger.Error("Failed to record webhook rejection", zap.Error(err))
+	}
+}
+
+// ListRejections lists refused webhook requests with filters
+func (s *WebhookService) ListRejections(ctx context.Context, filters map[string]interface{}, pagination *utils.Pagination) ([]*models.WebhookRejection, error) {
+	rejections, err := s.rejectionRepo.ListWithFilters(ctx, filters, pagination)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err)
+	}
+	return rejections, nil
+}
+
+// enqueue hands an event to the workers. If the queue is full the event stays
+// pending and is picked up by the next sweep.
+func (s *WebhookService) enqueue(eventID string) {
+	select {
+	case s.queue <- eventID:
+	default:
+		s.logger.Warn("Webhook queue full, deferring to sweep", zap.String("event_id", eventID))
+	}
+}
+
+// StartWorkers starts the background processing workers
+func (s *WebhookService) StartWorkers() {
+	for i := 0; i < webhookWorkers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+
+	s.wg.Add(2)
+	go s.sweeper()
+	go s.rejectionPurger()
+}
+
+// StopWorkers cancels in-flight processing and waits for the workers to exit.
+// Interrupted events are left pending and resume on the next start.
+func (s *WebhookService) StopWorkers() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// worker processes queued events until stopped
+func (s *WebhookService) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case eventID := <-s.queue:
+			s.processEvent(s.ctx, eventID)
+		}
+	}
+}
+
+// sweeper periodically reclaims abandoned events and queues pending events that
+// never made it onto the queue and failed events whose retry is due
+func (s *WebhookService) sweeper() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(webhookSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		s.sweepDue(s.ctx)
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *WebhookService) sweepDue(ctx context.Context) {
+	s.reclaimStale(ctx)
+
+	due, err := s.eventRepo.FindDue(ctx, time.Now().UTC(), webhookSweepBatch)
+	if err != nil {
+		s.logger.Error("Failed to list due webhook events", zap.Error(err))
+		return
+	}
+	for _, event := range due {
+		select {
+		case s.queue <- event.ID:
+		default:
+			return
+		}
+	}
+}
+
+// rejectionPurger periodically removes security log entries older than the retention
+func (s *WebhookService) rejectionPurger() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(webhookRejectionPurge)
+	defer ticker.Stop()
+
+	for {
+		s.purgeRejections(s.ctx)
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *WebhookService) purgeRejections(ctx context.Context) {
+	n, err := s.rejectionRepo.DeleteOlderThan(ctx, time.Now().UTC().Add(-webhookRejectionRetention))
+	if err != nil {
+		s.logger.Error("Failed to purge old webhook rejections", zap.Error(err))
+	} else if n > 0 {
+		s.logger.Info("Purged old webhook rejections", zap.Int64("count", n))
+	}
+}
+
+// reclaimStale returns events abandoned by a crashed or restarted worker to
+// pending. Only old claims are reclaimed, so events still being processed by
+// another instance are left alone.
+func (s *WebhookService) reclaimStale(ctx context.Context) {
+	n, err := s.eventRepo.ReclaimStale(ctx, time.Now().UTC().Add(-webhookStaleAfter))
+	if err != nil {
+		s.logger.Error("Failed to reclaim abandoned webhook events", zap.Error(err))
+	} else if n > 0 {
+		s.logger.Info("Resumed abandoned webhook events", zap.Int64("count", n))
+	}
+}
+
+// processEvent claims an event, handles its payload and records the outcome.
+// Failed events are retried with exponential backoff until they run out of
+// attempts, after which they are dead-lettered for manual reprocessing.
+func (s *WebhookService) processEvent(ctx context.Context, eventID string) {
+	claimed, err := s.eventRepo.Claim(ctx, eventID, time.Now().UTC())
+	if err != nil {
+		s.logger.Error("Failed to claim webhook event", zap.Error(err), zap.String("event_id", eventID))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	var event models.WebhookEvent
+	if err := s.eventRepo.FindByID(ctx, eventID, &event); err != nil {
+		// Release the claim so the event is retried instead of staying processing
+		s.logger.Error("Failed to load webhook event", zap.Error(err), zap.String("event_id", eventID))
+		s.updateStatus(eventID, map[string]interface{}{
+			"status":          models.WebhookEventStatusFailed,
+			"next_attempt_at": time.Now().UTC().Add(webhookRetryBackoff),
+			"last_error":      err.Error(),
+		})
+		return
+	}
+
+	event.Attempts++
+	processCtx, cancel := context.WithTimeout(ctx, webhookProcessTimeout)
+	publishes, err := s.handle(processCtx, &event)
+	cancel()
+
+	now := time.Now().UTC()
+	updates := map[string]interface{}{"attempts": event.Attempts}
+
+	switch {
+	case err == nil:
+		updates["status"] = models.WebhookEventStatusProcessed
+		updates["processed_at"] = now
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = ""
+		if event.PublishedAt == nil && len(publishes) > 0 {
+			updates["published_at"] = now
+		} else {
+			publishes = nil
+		}
+		metrics.WebhookEvents.Inc("processed")
+
+	case ctx.Err() != nil:
+		// Shutting down: leave the event pending so it resumes after restart
+		updates["status"] = models.WebhookEventStatusPending
+		updates["attempts"] = event.Attempts - 1
+		updates["last_error"] = err.Error()
+
+	case stderrors.Is(err, errPermanent) || event.Attempts >= webhookMaxAttempts:
+		updates["status"] = models.WebhookEventStatusDead
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = err.Error()
+		metrics.WebhookEvents.Inc("dead")
+		s.logger.Error("Giving up on webhook event",
+			zap.Error(err),
+			zap.String("event_id", event.ID),
+			zap.Int("attempts", event.Attempts),
+		)
+
+	default:
+		nextAttempt := now.Add(webhookBackoff(event.Attempts))
+		updates["status"] = models.WebhookEventStatusFailed
+		updates["next_attempt_at"] = nextAttempt
+		updates["last_error"] = err.Error()
+		metrics.WebhookEvents.Inc("failed")
+		s.logger.Warn("Webhook event processing failed, will retry",
+			zap.Error(err),
+			zap.String("event_id", event.ID),
+			zap.Int("attempt", event.Attempts),
+			zap.Time("next_attempt_at", nextAttempt),
+		)
+	}
+
+	// Account notices are published once the event is stored as processed, and
+	// only the first time, so retries and reprocessing do not repeat them
+	if err := s.updateStatus(event.ID, updates); err != nil {
+		return
+	}
+	for _, publish := range publishes {
+		s.bus.Publish(ctx, publish.eventType, publish.payload)
+	}
+}
+
+// handle processes every message, status and account-level change in a webhook
+// payload. Items are handled independently so one bad item does not hold back
+// the others; the errors are joined so the event is retried. Messages, statuses
+// and template or phone number changes handled by an earlier attempt are
+// recognised as duplicates, so retrying a payload is safe. Account notices that
+// have no stored state to deduplicate them are returned for publishing instead.
+func (s *WebhookService) handle(ctx context.Context, event *models.WebhookEvent) ([]pendingPublish, error) {
+	payload, err := whatsapp.ParseWebhook(event.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errPermanent, err)
+	}
+
+	// Messages and statuses that cannot be parsed would fail the same way on every
+	// retry, so they are logged and skipped and the rest of the payload is handled
+	messageEvents, err := whatsapp.ParseMessageEvent(payload)
+	if err != nil {
+		s.logger.Error("Skipping unparsable webhook messages", zap.Error(err), zap.String("event_id", event.ID))
+	}
+	statusEvents, err := whatsapp.ParseStatusEvent(payload)
+	if err != nil {
+		s.logger.Error("Skipping unparsable webhook statuses", zap.Error(err), zap.String("event_id", event.ID))
+	}
+	accountEvents := whatsapp.ParseAccountEvents(payload)
+
+	// Errors for a change as a whole have nothing to apply them to, so they are
+	// logged with the change for investigation
+	for _, changeError := range whatsapp.ParseChangeErrors(payload) {
+		for _, webhookError := range changeError.Errors {
+			s.logger.Error("WhatsApp reported a webhook error",
+				zap.String("event_id", event.ID),
+				zap.String("phone_number_id", changeError.PhoneNumberID),
+				zap.Int("code", webhookError.Code),
+				zap.String("title", webhookError.Title),
+				zap.String("message", webhookError.Message),
+				zap.String("details", webhookError.ErrorData.Details),
+				zap.ByteString("value", changeError.Raw),
+			)
+		}
+	}
+
+	var errs []error
+	for _, messageEvent := range messageEvents {
+		if err := s.messageService.ProcessIncomingMessage(ctx, messageEvent); err != nil {
+			errs = append(errs, fmt.Errorf("message %s: %w", messageEvent.MessageID, err))
+		}
+	}
+	for _, statusEvent := range statusEvents {
+		if err := s.messageService.UpdateMessageStatus(ctx, statusEvent); err != nil {
+			errs = append(errs, fmt.Errorf("status of %s: %w", statusEvent.MessageID, err))
+		}
+	}
+	var publishes []pendingPublish
+	for _, accountEvent := range accountEvents {
+		publish, err := s.handleAccountEvent(ctx, accountEvent)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s change: %w", accountEvent.Field, err))
+		}
+		if publish != nil {
+			publishes = append(publishes, *publish)
+		}
+	}
+
+	return publishes, stderrors.Join(errs...)
+}
+
+// handleAccountEvent applies a change to an account-level webhook field. Template
+// and phone number changes update the stored records; account updates, alerts
+// and capability changes are logged and returned for publishing on the event
+// bus. Changes whose value could not be decoded are logged with the value and skipped.
+func (s *WebhookService) handleAccountEvent(ctx context.Context, event *whatsapp.AccountEvent) (*pendingPublish, error) {
+	switch {
+	case event.DecodeError != "":
+		s.logger.Error("Skipping undecodable webhook change",
+			zap.String("field", event.Field),
+			zap.String("business_account_id", event.BusinessAccountID),
+			zap.String("error", event.DecodeError),
+			zap.ByteString("value", event.Raw),
+		)
+
+	case event.TemplateStatus != nil:
+		return nil, s.templateService.ApplyStatusUpdate(ctx, event.TemplateStatus, event.Timestamp)
+
+	case event.TemplateQuality != nil:
+		return nil, s.templateService.ApplyQualityUpdate(ctx, event.TemplateQuality)
+
+	case event.PhoneNumberQuality != nil:
+		return nil, s.phoneNumberService.ApplyQualityUpdate(ctx, event.PhoneNumberQuality)
+
+	case event.PhoneNumberName != nil:
+		return nil, s.phoneNumberService.ApplyNameUpdate(ctx, event.PhoneNumberName)
+
+	case event.Account != nil:
+		s.logger.Warn("Business account updated",
+			zap.String("business_account_id", event.BusinessAccountID),
+			zap.String("event", event.Account.Event),
+			zap.ByteString("details", event.Raw),
+		)
+		return &pendingPublish{eventType: events.AccountUpdated, payload: AccountUpdatedEvent{
+			BusinessAccountID: event.BusinessAccountID,
+			PhoneNumber:       event.Account.PhoneNumber,
+			Event:             event.Account.Event,
+			Details:           event.Raw,
+		}}, nil
+
+	case event.Alert != nil:
+		s.logger.Warn("Business account alert",
+			zap.String("business_account_id", event.BusinessAccountID),
+			zap.String("severity", event.Alert.AlertSeverity),
+			zap.String("type", event.Alert.AlertType),
+			zap.String("description", event.Alert.AlertDescription),
+		)
+		return &pendingPublish{eventType: events.AccountAlertRaised, payload: AccountAlertEvent{
+			BusinessAccountID: event.BusinessAccountID,
+			AccountAlert:      *event.Alert,
+		}}, nil
+
+	case event.Capability != nil:
+		s.logger.Info("Business account capabilities updated",
+			zap.String("business_account_id", event.BusinessAccountID),
+			zap.Int("max_daily_conversation_per_phone", event.Capability.MaxDailyConversationPerPhone),
+			zap.Int("max_phone_numbers_per_business", event.Capability.MaxPhoneNumbersPerBusiness),
+		)
+		return &pendingPublish{eventType: events.AccountCapabilityUpdated, payload: CapabilityUpdatedEvent{
+			BusinessAccountID:        event.BusinessAccountID,
+			BusinessCapabilityUpdate: *event.Capability,
+		}}, nil
+
+	default:
+		s.logger.Info("Ignoring unsupported webhook field",
+			zap.String("field", event.Field),
+			zap.String("business_account_id", event.BusinessAccountID),
+		)
+	}
+	return nil, nil
+}
+
+// updateStatus records the outcome of an attempt. It uses its own deadline so the
+// result is saved even when processing was cancelled by shutdown.
+func (s *WebhookService) updateStatus(eventID string, updates map[string]interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookStatusTimeout)
+	defer cancel()
+
+	err := s.eventRepo.UpdateFields(ctx, eventID, &models.WebhookEvent{}, updates)
+	if err != nil {
+		s.logger.Error("Failed to update webhook event", zap.Error(err), zap.String("event_id", eventID))
+	}
+	return err
+}
+
+// webhookBackoff returns the delay before the next attempt, doubling from
+// webhookRetryBackoff up to webhookMaxBackoff
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookRetryBackoff
+	for i := 1; i < attempts && delay < webhookMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxBackoff {
+		delay = webhookMaxBackoff
+	}
+	return delay
+}
+
+// rejectionThrottle limits how often rejections from the same source and reason
+// are stored, so a flood of forged requests cannot grow the security log without
+// bound. It tracks at most maxKeys sources; beyond that new sources are not
+// stored until old windows expire.
+type rejectionThrottle struct {
+	mu      sync.Mutex
+	window  time.Duration
+	maxKeys int
+	entries map[string]*rejectionWindow
+}
+
+// rejectionWindow is the current window of one source and reason
+type rejectionWindow struct {
+	start      time.Time
+	suppressed int64
+}
+
+func newRejectionThrottle(window time.Duration, maxKeys int) *rejectionThrottle {
+	return &rejectionThrottle{
+		window:  window,
+		maxKeys: maxKeys,
+		entries: make(map[string]*rejectionWindow),
+	}
+}
+
+// allow reports whether a rejection for key should be stored and, if so, how
+// many rejections for key were suppressed since the last stored one
+func (t *rejectionThrottle) allow(key string, now time.Time) (bool, int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	if ok && now.Sub(entry.start) < t.window {
+		entry.suppressed++
+		return false, 0
+	}
+	if ok {
+		suppressed := entry.suppressed
+		*entry = rejectionWindow{start: now}
+		return true, suppressed
+	}
+
+	if len(t.entries) >= t.maxKeys {
+		for k, e := range t.entries {
+			if now.Sub(e.start) >= t.window {
+				delete(t.entries, k)
+			}
+		}
+		if len(t.entries) >= t.maxKeys {
+			return false, 0
+		}
+	}
+	t.entries[key] = &rejectionWindow{start: now}
+	return true, 0
+}
@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/events"
+	"github.com/ashok/vibecoded-wa-client/internal/models"
+	"github.com/ashok/vibecoded-wa-client/internal/repositories"
+	"github.com/ashok/vibecoded-wa-client/internal/services"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp/fake"
+	"go.uber.org/zap"
+)
+
+// newWebhookService wires a webhook service to the message service of env and
+// starts its workers
+func newWebhookService(t *testing.T, env *messageServiceEnv, bus *events.Bus) *services.WebhookService {
+	t.Helper()
+
+	log := zap.NewNop()
+	service := services.NewWebhookService(
+		repositories.NewWebhookEventRepository(env.db),
+		repositories.NewWebhookRejectionRepository(env.db),
+		env.service,
+		nil, nil,
+		bus,
+		log,
+	)
+	service.StartWorkers()
+	t.Cleanup(service.StopWorkers)
+	return service
+}
+
+// waitForEvent waits until a stored webhook event leaves pending and processing
+func waitForEvent(t *testing.T, service *services.WebhookService, eventID string) *models.WebhookEvent {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		event, err := service.GetEvent(context.Background(), eventID)
+		if err != nil {
+			t.Fatalf("GetEvent failed: %v", err)
+		}
+		if event.Status != models.WebhookEventStatusPending && event.Status != models.WebhookEventStatusProcessing {
+			return event
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for webhook event %s", eventID)
+	return nil
+}
+
+func TestWebhookWithUnparsableStatusProcessesOtherItems(t *testing.T) {
+	env := newMessageServiceEnv(t)
+	service := newWebhookService(t, env, events.NewBus(zap.NewNop()))
+
+	body := fmt.Sprintf(`{
+		"object": "whatsapp_business_account",
+		"entry": [{
+			"id": "waba-1",
+			"changes": [{
+				"field": "messages",
+				"value": {
+					"messaging_product": "whatsapp",
+					"metadata": {"display_phone_number": %q, "phone_number_id": %q},
+					"contacts": [{"wa_id": "15551234567", "profile": {"name": "Alice"}}],
+					"messages": [{"id": "wamid.good", "from": "15551234567", "timestamp": "1700000000",
+						"type": "text", "text": {"body": "hello"}}],
+					"statuses": [{"id": "wamid.other", "status": "delivered", "timestamp": "not-a-time",
+						"recipient_id": "15551234567"}]
+				}
+			}]
+		}]
+	}`, fake.DefaultDisplayPhoneNumber, fake.DefaultPhoneNumberID)
+
+	received, err := service.Receive(context.Background(), []byte(body))
+	if err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+
+	event := waitForEvent(t, service, received.ID)
+	if event.Status != models.WebhookEventStatusProcessed {
+		t.Fatalf("Expected the event to be processed, got %s: %s", event.Status, event.LastError)
+	}
+
+	var message models.Message
+	if err := env.db.Where("whatsapp_message_id = ?", "wamid.good").First(&message).Error; err != nil {
+		t.Fatalf("Expected the valid message to be stored: %v", err)
+	}
+	if message.Content != "hello" {
+		t.Errorf("Unexpected message content %q", message.Content)
+	}
+}
+
+func TestFailedWebhookEventIsNotClaimedBeforeRetryIsDue(t *testing.T) {
+	db := newTestDB(t)
+	repo := repositories.NewWebhookEventRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	nextAttempt := now.Add(time.Minute)
+	event := &models.WebhookEvent{
+		Payload:       models.RawJSON(`{}`),
+		Status:        models.WebhookEventStatusFailed,
+		Attempts:      1,
+		NextAttemptAt: &nextAttempt,
+	}
+	if err := db.Create(event).Error; err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+
+	// A duplicate queue entry right after the failure must not skip the backoff
+	if claimed, err := repo.Claim(ctx, event.ID, now); err != nil || claimed {
+		t.Fatalf("Expected no claim before the retry is due, got %v (%v)", claimed, err)
+	}
+	if claimed, err := repo.Claim(ctx, event.ID, nextAttempt); err != nil || !claimed {
+		t.Fatalf("Expected the due retry to be claimed, got %v (%v)", claimed, err)
+	}
+}
+
+func TestAccountNoticesArePublishedOnceAcrossReprocessing(t *testing.T) {
+	env := newMessageServiceEnv(t)
+	bus := events.NewBus(zap.NewNop())
+	var published atomic.Int32
+	bus.Subscribe(events.AccountAlertRaised, func(ctx context.Context, event events.Event) {
+		published.Add(1)
+	})
+	service := newWebhookService(t, env, bus)
+	ctx := context.Background()
+
+	body := `{
+		"object": "whatsapp_business_account",
+		"entry": [{
+			"id": "waba-1",
+			"time": 1700000000,
+			"changes": [{"field": "account_alerts", "value": {
+				"entity_type": "WABA", "entity_id": "waba-1", "alert_severity": "WARNING",
+				"alert_status": "ACTIVE", "alert_type": "OBA_APPROVED", "alert_description": "Approved"}}]
+		}]
+	}`
+	received, err := service.Receive(ctx, []byte(body))
+	if err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if event := waitForEvent(t, service, received.ID); event.Status != models.WebhookEventStatusProcessed {
+		t.Fatalf("Expected the event to be processed, got %s: %s", event.Status, event.LastError)
+	}
+
+	if _, err := service.ReprocessEvent(ctx, received.ID); err != nil {
+		t.Fatalf("ReprocessEvent failed: %v", err)
+	}
+	if event := waitForEvent(t, service, received.ID); event.Status != models.WebhookEventStatusProcessed {
+		t.Fatalf("Expected the reprocessed event to be processed, got %s: %s", event.Status, event.LastError)
+	}
+
+	// Stopping waits for in-flight processing, including publishing
+	service.StopWorkers()
+	if n := published.Load(); n != 1 {
+		t.Errorf("Expected the alert to be published once, got %d", n)
+	}
+}
@@ -0,0 +1,96 @@
+// Package storage provides object storage for media files and call recordings.
+// Objects are addressed by slash-separated keys, independent of the backend.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/config"
+)
+
+// Storage backend types
+const (
+	TypeLocal = "local"
+	TypeS3    = "s3"
+	TypeMinIO = "minio"
+)
+
+var (
+	// ErrNotFound is returned when an object does not exist
+	ErrNotFound = errors.New("storage: object not found")
+
+	// ErrPresignNotSupported is returned by backends that cannot issue presigned URLs
+	ErrPresignNotSupported = errors.New("storage: presigned URLs not supported")
+)
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// Storage is implemented by every object storage backend
+type Storage interface {
+	// Put stores an object. Size may be -1 if unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens an object for reading. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+
+	// Delete removes an object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// Stat returns object metadata without reading its content
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+
+	// PresignGet returns a URL that grants temporary read access to an object
+	PresignGet(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// NewFromConfig creates the storage backend selected by cfg.Type.
+// Local storage is rooted at localPath; S3-compatible storage keeps
+// objects under prefix in the configured bucket so media and
+// recordings can share one bucket.
+func NewFromConfig(cfg config.StorageConfig, localPath, prefix string) (Storage, error) {
+	switch cfg.Type {
+	case "", TypeLocal:
+		return NewLocalStorage(localPath)
+	case TypeS3, TypeMinIO:
+		if cfg.Type == TypeMinIO && cfg.S3Endpoint == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT is required for minio storage")
+		}
+		return NewS3Storage(S3Config{
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Endpoint:  cfg.S3Endpoint,
+			Prefix:    prefix,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", cfg.Type)
+	}
+}
+
+// validateKey rejects keys that could escape the storage root
+func validateKey(key string) error {
+	if key == "" {
+		return errors.New("storage: key is required")
+	}
+	if strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("storage: invalid key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("storage: invalid key %q", key)
+		}
+	}
+	return nil
+}
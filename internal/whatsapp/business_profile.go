@@ -0,0 +1,253 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Business profile field limits enforced by WhatsApp
+const (
+	MaxProfileAboutLength       = 139
+	MaxProfileAddressLength     = 256
+	MaxProfileDescriptionLength = 512
+	MaxProfileEmailLength       = 128
+	MaxProfileWebsiteLength     = 256
+	MaxProfileWebsites          = 2
+	MaxProfilePictureSize       = 5 * 1024 * 1024
+)
+
+// businessProfileFields are the fields requested when reading the profile
+const businessProfileFields = "about,address,description,email,profile_picture_url,websites,vertical"
+
+// BusinessVerticals lists the industries accepted for the profile vertical
+var BusinessVerticals = []string{
+	"UNDEFINED", "OTHER", "AUTO", "BEAUTY", "APPAREL", "EDU", "ENTERTAIN",
+	"EVENT_PLAN", "FINANCE", "GROCERY", "GOVT", "HOTEL", "HEALTH", "NONPROFIT",
+	"PROF_SERVICES", "RETAIL", "TRAVEL", "RESTAURANT", "NOT_A_BIZ",
+}
+
+// BusinessProfile represents the public WhatsApp business profile of the phone number
+type BusinessProfile struct {
+	About             string   `json:"about"`
+	Address           string   `json:"address"`
+	Description       string   `json:"description"`
+	Email             string   `json:"email"`
+	ProfilePictureURL string   `json:"profile_picture_url,omitempty"`
+	Websites          []string `json:"websites"`
+	Vertical          string   `json:"vertical"`
+}
+
+// BusinessProfileUpdate describes a partial profile update. Nil fields are left
+// unchanged; empty values clear the field.
+type BusinessProfileUpdate struct {
+	About                *string  `json:"about,omitempty"`
+	Address              *string  `json:"address,omitempty"`
+	Description          *string  `json:"description,omitempty"`
+	Email                *string  `json:"email,omitempty"`
+	Websites             []string `json:"websites,omitempty"`
+	Vertical             *string  `json:"vertical,omitempty"`
+	ProfilePictureHandle string   `json:"profile_picture_handle,omitempty"`
+}
+
+// Validate checks the update against WhatsApp's field limits
+func (u *BusinessProfileUpdate) Validate() error {
+	if u.About != nil && len([]rune(*u.About)) > MaxProfileAboutLength {
+		return fmt.Errorf("about must be at most %d characters", MaxProfileAboutLength)
+	}
+	if u.Address != nil && len([]rune(*u.Address)) > MaxProfileAddressLength {
+		return fmt.Errorf("address must be at most %d characters", MaxProfileAddressLength)
+	}
+	if u.Description != nil && len([]rune(*u.Description)) > MaxProfileDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", MaxProfileDescriptionLength)
+	}
+	if u.Email != nil && *u.Email != "" {
+		if len(*u.Email) > MaxProfileEmailLength {
+			return fmt.Errorf("email must be at most %d characters", MaxProfileEmailLength)
+		}
+		if _, err := mail.ParseAddress(*u.Email); err != nil {
+			return fmt.Errorf("email is not a valid address")
+		}
+	}
+	if len(u.Websites) > MaxProfileWebsites {
+		return fmt.Errorf("at most %d websites are allowed", MaxProfileWebsites)
+	}
+	for _, website := range u.Websites {
+		if len(website) > MaxProfileWebsiteLength {
+			return fmt.Errorf("websites must be at most %d characters", MaxProfileWebsiteLength)
+		}
+		if !strings.HasPrefix(website, "http://") && !strings.HasPrefix(website, "https://") {
+			return fmt.Errorf("website %q must start with http:// or https://", website)
+		}
+	}
+	if u.Vertical != nil && *u.Vertical != "" && !isBusinessVertical(*u.Vertical) {
+		return fmt.Errorf("vertical must be one of %s", strings.Join(BusinessVerticals, ", "))
+	}
+	return nil
+}
+
+// IsEmpty reports whether the update changes nothing
+func (u *BusinessProfileUpdate) IsEmpty() bool {
+	return u.About == nil && u.Address == nil && u.Description == nil && u.Email == nil &&
+		u.Websites == nil && u.Vertical == nil && u.ProfilePictureHandle == ""
+}
+
+func isBusinessVertical(vertical string) bool {
+	for _, v := range BusinessVerticals {
+		if v == vertical {
+			return true
+		}
+	}
+	return false
+}
+
+// businessProfileResponse wraps the profile returned by Graph
+type businessProfileResponse struct {
+	Data []BusinessProfile `json:"data"`
+}
+
+// uploadSessionResponse is returned when a resumable upload session is created
+type uploadSessionResponse struct {
+	ID string `json:"id"`
+}
+
+// uploadHandleResponse is returned once the file content has been uploaded
+type uploadHandleResponse struct {
+	Handle string `json:"h"`
+}
+
+// GetBusinessProfile reads the business profile of the phone number
+func (c *Client) GetBusinessProfile(ctx context.Context) (*BusinessProfile, error) {
+	var resp businessProfileResponse
+	endpoint := fmt.Sprintf("/%s/whatsapp_business_profile", c.phoneNumberID)
+	if err := c.get(ctx, endpoint, map[string]string{"fields": businessProfileFields}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, errors.NewWhatsAppError(fmt.Errorf("response did not include a business profile"))
+	}
+
+	profile := resp.Data[0]
+	if profile.Websites == nil {
+		profile.Websites = []string{}
+	}
+	return &profile, nil
+}
+
+// UpdateBusinessProfile applies a partial update to the business profile
+func (c *Client) UpdateBusinessProfile(ctx context.Context, update *BusinessProfileUpdate) error {
+	if err := update.Validate(); err != nil {
+		return errors.NewBadRequest(err.Error())
+	}
+
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+	}
+	setIfPresent := func(key string, value *string) {
+		if value != nil {
+			payload[key] = *value
+		}
+	}
+	setIfPresent("about", update.About)
+	setIfPresent("address", update.Address)
+	setIfPresent("description", update.Description)
+	setIfPresent("email", update.Email)
+	setIfPresent("vertical", update.Vertical)
+	if update.Websites != nil {
+		payload["websites"] = update.Websites
+	}
+	if update.ProfilePictureHandle != "" {
+		payload["profile_picture_handle"] = update.ProfilePictureHandle
+	}
+
+	var result SuccessResponse
+	if err := c.post(ctx, fmt.Sprintf("/%s/whatsapp_business_profile", c.phoneNumberID), payload, &result); err != nil {
+		return err
+	}
+	if !result.Success {
+		return errors.NewWhatsAppError(fmt.Errorf("business profile update was not accepted"))
+	}
+
+	c.logger.Info("Business profile updated")
+	return nil
+}
+
+// HasApp reports whether an app ID is configured, which resumable uploads require
+func (c *Client) HasApp() bool {
+	return c.appID != ""
+}
+
+// UploadProfilePicture uploads an image through the resumable upload API and
+// returns the handle to set as profile_picture_handle
+func (c *Client) UploadProfilePicture(ctx context.Context, filename, mimeType string, data []byte) (string, error) {
+	if !c.HasApp() {
+		return "", errors.NewBadRequest("WhatsApp app ID is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.mediaTimeout)
+	defer cancel()
+
+	sessionID, err := c.createUploadSession(ctx, filename, mimeType, int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+
+	// Upload the whole file in one request starting at offset 0
+	resp, err := c.request(ctx).
+		SetHeader("Authorization", "OAuth "+c.apiToken).
+		SetHeader("Content-Type", mimeType).
+		SetHeader("file_offset", "0").
+		SetBody(data).
+		Post("/" + sessionID)
+	if err != nil {
+		c.logger.Error("Failed to upload profile picture", zap.Error(err))
+		return "", errors.NewMediaUploadError(err)
+	}
+	if resp.IsError() {
+		return "", c.parseError(resp)
+	}
+
+	var handle uploadHandleResponse
+	if err := json.Unmarshal(resp.Body(), &handle); err != nil {
+		return "", errors.NewInternalError(err)
+	}
+	if handle.Handle == "" {
+		return "", errors.NewMediaUploadError(fmt.Errorf("response did not include an upload handle"))
+	}
+
+	return handle.Handle, nil
+}
+
+// createUploadSession starts a resumable upload for a file of the given size and type
+func (c *Client) createUploadSession(ctx context.Context, filename, mimeType string, length int64) (string, error) {
+	resp, err := c.request(ctx).
+		SetQueryParams(map[string]string{
+			"file_name":   filename,
+			"file_length": strconv.FormatInt(length, 10),
+			"file_type":   mimeType,
+		}).
+		Post(fmt.Sprintf("/%s/uploads", c.appID))
+	if err != nil {
+		c.logger.Error("Failed to create upload session", zap.Error(err))
+		return "", errors.NewMediaUploadError(err)
+	}
+	if resp.IsError() {
+		return "", c.parseError(resp)
+	}
+
+	var session uploadSessionResponse
+	if err := json.Unmarshal(resp.Body(), &session); err != nil {
+		return "", errors.NewInternalError(err)
+	}
+	if session.ID == "" {
+		return "", errors.NewMediaUploadError(fmt.Errorf("response did not include an upload session ID"))
+	}
+
+	return session.ID, nil
+}
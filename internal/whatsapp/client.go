@@ -1,6 +1,7 @@
 package whatsapp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -10,21 +11,36 @@ import (
 	"go.uber.org/zap"
 )
 
+// Default deadlines for Graph API operations, including retries
+const (
+	DefaultRequestTimeout = 30 * time.Second
+	DefaultMediaTimeout   = 2 * time.Minute
+)
+
 // Config holds WhatsApp client configuration
 type Config struct {
-	APIToken      string
-	PhoneNumberID string
-	APIBaseURL    string
-	APIVersion    string
-	Logger        *zap.Logger
+	APIToken          string
+	PhoneNumberID     string
+	BusinessAccountID string // required for template management
+	AppID             string // required for resumable uploads such as the profile picture
+	APIBaseURL        string
+	APIVersion        string
+	RequestTimeout    time.Duration // deadline for API calls, defaults to DefaultRequestTimeout
+	MediaTimeout      time.Duration // deadline for media uploads and downloads, defaults to DefaultMediaTimeout
+	Logger            *zap.Logger
 }
 
 // Client represents a WhatsApp API client
 type Client struct {
-	httpClient    *resty.Client
-	phoneNumberID string
-	baseURL       string
-	logger        *zap.Logger
+	httpClient        *resty.Client
+	apiToken          string
+	phoneNumberID     string
+	businessAccountID string
+	appID             string
+	baseURL           string
+	requestTimeout    time.Duration
+	mediaTimeout      time.Duration
+	logger            *zap.Logger
 }
 
 // NewClient creates a new WhatsApp client
@@ -46,25 +62,39 @@ func NewClient(config Config) (*Client, error) {
 		apiVersion = "v18.0"
 	}
 
+	requestTimeout := config.RequestTimeout
+	if requestTimeout == 0 {
+		requestTimeout = DefaultRequestTimeout
+	}
+
+	mediaTimeout := config.MediaTimeout
+	if mediaTimeout == 0 {
+		mediaTimeout = DefaultMediaTimeout
+	}
+
 	httpClient := resty.New()
 	httpClient.SetBaseURL(fmt.Sprintf("%s/%s", baseURL, apiVersion))
 	httpClient.SetHeader("Authorization", fmt.Sprintf("Bearer %s", config.APIToken))
 	httpClient.SetHeader("Content-Type", "application/json")
-	httpClient.SetTimeout(30 * time.Second)
 	httpClient.SetRetryCount(3)
 	httpClient.SetRetryWaitTime(1 * time.Second)
 	httpClient.SetRetryMaxWaitTime(5 * time.Second)
 
 	return &Client{
-		httpClient:    httpClient,
-		phoneNumberID: config.PhoneNumberID,
-		baseURL:       fmt.Sprintf("%s/%s/%s", baseURL, apiVersion, config.PhoneNumberID),
-		logger:        config.Logger,
+		httpClient:        httpClient,
+		apiToken:          config.APIToken,
+		phoneNumberID:     config.PhoneNumberID,
+		businessAccountID: config.BusinessAccountID,
+		appID:             config.AppID,
+		baseURL:           fmt.Sprintf("%s/%s/%s", baseURL, apiVersion, config.PhoneNumberID),
+		requestTimeout:    requestTimeout,
+		mediaTimeout:      mediaTimeout,
+		logger:            config.Logger,
 	}, nil
 }
 
 // SendTextMessage sends a text message
-func (c *Client) SendTextMessage(to, text string) (*MessageResponse, error) {
+func (c *Client) SendTextMessage(ctx context.Context, to, text string, opts ...SendOption) (*MessageResponse, error) {
 	payload := map[string]interface{}{
 		"messaging_product": "whatsapp",
 		"recipient_type":    "individual",
@@ -75,17 +105,28 @@ func (c *Client) SendTextMessage(to, text string) (*MessageResponse, error) {
 		},
 	}
 
-	return c.sendMessage(payload)
+	return c.sendMessage(ctx, payload, opts...)
 }
 
-// SendMediaMessage sends a media message (image, document, audio, video)
-func (c *Client) SendMediaMessage(to, mediaURL, caption string, mediaType MediaType) (*MessageResponse, error) {
-	mediaObj := map[string]interface{}{
-		"link": mediaURL,
+// SendMediaMessage sends a media message (image, document, audio, video).
+// The media is referenced either by a public link or by an uploaded media ID.
+func (c *Client) SendMediaMessage(ctx context.Context, to string, media *MediaObject, mediaType MediaType, opts ...SendOption) (*MessageResponse, error) {
+	mediaObj := map[string]interface{}{}
+
+	switch {
+	case media.ID != "":
+		mediaObj["id"] = media.ID
+	case media.Link != "":
+		mediaObj["link"] = media.Link
+	default:
+		return nil, errors.NewBadRequest("media requires an id or a link")
 	}
 
-	if caption != "" && (mediaType == MediaTypeImage || mediaType == MediaTypeVideo || mediaType == MediaTypeDocument) {
-		mediaObj["caption"] = caption
+	if media.Caption != "" && (mediaType == MediaTypeImage || mediaType == MediaTypeVideo || mediaType == MediaTypeDocument) {
+		mediaObj["caption"] = media.Caption
+	}
+	if media.Filename != "" && mediaType == MediaTypeDocument {
+		mediaObj["filename"] = media.Filename
 	}
 
 	payload := map[string]interface{}{
@@ -96,26 +137,13 @@ func (c *Client) SendMediaMessage(to, mediaURL, caption string, mediaType MediaT
 		string(mediaType):   mediaObj,
 	}
 
-	return c.sendMessage(payload)
+	return c.sendMessage(ctx, payload, opts...)
 }
 
-// SendTemplateMessage sends a template message
-func (c *Client) SendTemplateMessage(to, templateName, language string, params []string) (*MessageResponse, error) {
-	components := []map[string]interface{}{}
-
-	if len(params) > 0 {
-		parameters := make([]map[string]interface{}, len(params))
-		for i, param := range params {
-			parameters[i] = map[string]interface{}{
-				"type": "text",
-				"text": param,
-			}
-		}
-
-		components = append(components, map[string]interface{}{
-			"type":       "body",
-			"parameters": parameters,
-		})
+// SendTemplateMessage sends a template message with the given component parameters
+func (c *Client) SendTemplateMessage(ctx context.Context, to, templateName, language string, components []TemplateComponent, opts ...SendOption) (*MessageResponse, error) {
+	if components == nil {
+		components = []TemplateComponent{}
 	}
 
 	payload := map[string]interface{}{
@@ -132,11 +160,116 @@ func (c *Client) SendTemplateMessage(to, templateName, language string, params [
 		},
 	}
 
-	return c.sendMessage(payload)
+	return c.sendMessage(ctx, payload, opts...)
+}
+
+// SendLocationMessage sends a location pin
+func (c *Client) SendLocationMessage(ctx context.Context, to string, location *Location, opts ...SendOption) (*MessageResponse, error) {
+	if err := location.Validate(); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	locationObj := map[string]interface{}{
+		"latitude":  location.Latitude,
+		"longitude": location.Longitude,
+	}
+	if location.Name != "" {
+		locationObj["name"] = location.Name
+	}
+	if location.Address != "" {
+		locationObj["address"] = location.Address
+	}
+
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                to,
+		"type":              "location",
+		"location":          locationObj,
+	}
+
+	return c.sendMessage(ctx, payload, opts...)
+}
+
+// SendContactsMessage sends one or more contact cards
+func (c *Client) SendContactsMessage(ctx context.Context, to string, contacts []ContactCard, opts ...SendOption) (*MessageResponse, error) {
+	if len(contacts) == 0 {
+		return nil, errors.NewBadRequest("at least one contact is required")
+	}
+	for i := range contacts {
+		if err := contacts[i].Validate(); err != nil {
+			return nil, errors.NewBadRequest(err.Error())
+		}
+	}
+
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                to,
+		"type":              "contacts",
+		"contacts":          contacts,
+	}
+
+	return c.sendMessage(ctx, payload, opts...)
+}
+
+// SendInteractiveMessage sends an interactive message (reply buttons, list or CTA URL)
+func (c *Client) SendInteractiveMessage(ctx context.Context, to string, interactive *Interactive, opts ...SendOption) (*MessageResponse, error) {
+	if err := interactive.Validate(); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                to,
+		"type":              "interactive",
+		"interactive":       interactive,
+	}
+
+	return c.sendMessage(ctx, payload, opts...)
+}
+
+// SendReaction reacts to a message with an emoji; an empty emoji removes the reaction
+func (c *Client) SendReaction(ctx context.Context, to, messageID, emoji string) (*MessageResponse, error) {
+	if messageID == "" {
+		return nil, errors.NewBadRequest("message ID is required for reactions")
+	}
+
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                to,
+		"type":              "reaction",
+		"reaction": map[string]string{
+			"message_id": messageID,
+			"emoji":      emoji,
+		},
+	}
+
+	return c.sendMessage(ctx, payload)
+}
+
+// SendOption customizes an outbound message payload
+type SendOption func(payload map[string]interface{})
+
+// WithReplyTo quotes the given WhatsApp message ID in the outbound message
+func WithReplyTo(messageID string) SendOption {
+	return func(payload map[string]interface{}) {
+		if messageID != "" {
+			payload["context"] = map[string]string{
+				"message_id": messageID,
+			}
+		}
+	}
 }
 
 // sendMessage sends a message to WhatsApp API
-func (c *Client) sendMessage(payload map[string]interface{}) (*MessageResponse, error) {
+func (c *Client) sendMessage(ctx context.Context, payload map[string]interface{}, opts ...SendOption) (*MessageResponse, error) {
+	for _, opt := range opts {
+		opt(payload)
+	}
+
 	endpoint := fmt.Sprintf("/%s/messages", c.phoneNumberID)
 
 	c.logger.Debug("Sending message to WhatsApp",
@@ -144,57 +277,152 @@ func (c *Client) sendMessage(payload map[string]interface{}) (*MessageResponse,
 		zap.Any("payload", payload),
 	)
 
-	resp, err := c.httpClient.R().
-		SetBody(payload).
+	var msgResp MessageResponse
+	if err := c.post(ctx, endpoint, payload, &msgResp); err != nil {
+		return nil, err
+	}
+
+	if len(msgResp.Messages) == 0 {
+		c.logger.Error("WhatsApp API returned no message ID")
+		return nil, errors.NewWhatsAppError(fmt.Errorf("response did not include a message ID"))
+	}
+
+	c.logger.Info("Message sent successfully",
+		zap.String("message_id", msgResp.Messages[0].ID),
+	)
+
+	return &msgResp, nil
+}
+
+// MarkAsRead marks an inbound message, and every message before it, as read.
+// When showTyping is set the customer also sees a typing indicator.
+func (c *Client) MarkAsRead(ctx context.Context, messageID string, showTyping bool) error {
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"status":            "read",
+		"message_id":        messageID,
+	}
+	if showTyping {
+		payload["typing_indicator"] = map[string]string{
+			"type": "text",
+		}
+	}
+
+	var result SuccessResponse
+	if err := c.post(ctx, fmt.Sprintf("/%s/messages", c.phoneNumberID), payload, &result); err != nil {
+		return err
+	}
+	if !result.Success {
+		return errors.NewWhatsAppError(fmt.Errorf("read receipt for %s was not accepted", messageID))
+	}
+
+	return nil
+}
+
+// post sends a JSON request to the WhatsApp API and decodes the response into result
+func (c *Client) post(ctx context.Context, endpoint string, body interface{}, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.request(ctx).
+		SetBody(body).
 		Post(endpoint)
 
 	if err != nil {
-		c.logger.Error("Failed to send message", zap.Error(err))
-		return nil, errors.NewWhatsAppError(err)
+		c.logger.Error("WhatsApp API request failed",
+			zap.String("endpoint", endpoint),
+			zap.Error(err),
+		)
+		return errors.NewWhatsAppError(err)
 	}
 
 	if resp.IsError() {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(resp.Body(), &errResp); err == nil {
-			c.logger.Error("WhatsApp API error",
-				zap.Int("code", errResp.Error.Code),
-				zap.String("message", errResp.Error.Message),
-				zap.String("type", errResp.Error.Type),
-			)
-			return nil, errors.NewWhatsAppError(fmt.Errorf("%s: %s", errResp.Error.Type, errResp.Error.Message))
+		return c.parseError(resp)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(resp.Body(), result); err != nil {
+			c.logger.Error("Failed to parse response", zap.Error(err))
+			return errors.NewInternalError(err)
 		}
+	}
 
-		c.logger.Error("WhatsApp API error",
-			zap.Int("status", resp.StatusCode()),
-			zap.String("body", string(resp.Body())),
+	return nil
+}
+
+// get sends a GET request to the WhatsApp API and decodes the response into result
+func (c *Client) get(ctx context.Context, endpoint string, query map[string]string, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.request(ctx).
+		SetQueryParams(query).
+		Get(endpoint)
+
+	if err != nil {
+		c.logger.Error("WhatsApp API request failed",
+			zap.String("endpoint", endpoint),
+			zap.Error(err),
 		)
-		return nil, errors.NewWhatsAppError(fmt.Errorf("WhatsApp API returned status %d", resp.StatusCode()))
+		return errors.NewWhatsAppError(err)
 	}
 
-	var msgResp MessageResponse
-	if err := json.Unmarshal(resp.Body(), &msgResp); err != nil {
+	if resp.IsError() {
+		return c.parseError(resp)
+	}
+
+	if err := json.Unmarshal(resp.Body(), result); err != nil {
 		c.logger.Error("Failed to parse response", zap.Error(err))
-		return nil, errors.NewInternalError(err)
+		return errors.NewInternalError(err)
 	}
 
-	c.logger.Info("Message sent successfully",
-		zap.String("message_id", msgResp.Messages[0].ID),
-	)
+	return nil
+}
 
-	return &msgResp, nil
+// parseError converts an error response from the WhatsApp API into a
+// classified application error
+func (c *Client) parseError(resp *resty.Response) error {
+	apiErr := newAPIError(resp)
+
+	if apiErr.Code != 0 {
+		c.logger.Error("WhatsApp API error",
+			zap.Int("code", apiErr.Code),
+			zap.Int("subcode", apiErr.Subcode),
+			zap.String("message", apiErr.Message),
+			zap.String("type", apiErr.Type),
+			zap.String("category", string(apiErr.Category)),
+			zap.String("fbtrace_id", apiErr.FBTraceID),
+		)
+	} else {
+		c.logger.Error("WhatsApp API error",
+			zap.Int("status", resp.StatusCode()),
+			zap.String("body", string(resp.Body())),
+		)
+	}
+
+	return apiErr.AppError()
+}
+
+// request starts a Graph API request bound to ctx that is retried only on
+// transient failures
+func (c *Client) request(ctx context.Context) *resty.Request {
+	return c.httpClient.R().SetContext(ctx).AddRetryCondition(retryOnTransientError)
 }
 
 // GetMessageStatus gets the delivery status of a message
-func (c *Client) GetMessageStatus(messageID string) (*MessageStatus, error) {
+func (c *Client) GetMessageStatus(ctx context.Context, messageID string) (*MessageStatus, error) {
 	endpoint := fmt.Sprintf("/%s", messageID)
 
-	resp, err := c.httpClient.R().Get(endpoint)
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.request(ctx).Get(endpoint)
 	if err != nil {
 		return nil, errors.NewWhatsAppError(err)
 	}
 
 	if resp.IsError() {
-		return nil, errors.NewWhatsAppError(fmt.Errorf("failed to get message status: %d", resp.StatusCode()))
+		return nil, c.parseError(resp)
 	}
 
 	var status MessageStatus
@@ -205,9 +433,9 @@ func (c *Client) GetMessageStatus(messageID string) (*MessageStatus, error) {
 	return &status, nil
 }
 
-// SetTimeout sets the HTTP client timeout
+// SetTimeout sets the deadline applied to each API call, including retries
 func (c *Client) SetTimeout(duration time.Duration) {
-	c.httpClient.SetTimeout(duration)
+	c.requestTimeout = duration
 }
 
 // SetRetryPolicy sets the retry policy for the HTTP client
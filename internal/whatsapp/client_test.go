@@ -0,0 +1,333 @@
+package whatsapp_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp/fake"
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func newTestClient(t *testing.T, opts fake.Options) (*whatsapp.Client, *fake.Server) {
+	t.Helper()
+
+	srv := fake.NewServer(opts).Start()
+	t.Cleanup(srv.Close)
+
+	client, err := whatsapp.NewClient(srv.ClientConfig())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.SetRetryPolicy(3, time.Millisecond)
+	return client, srv
+}
+
+func TestSendTextMessage(t *testing.T) {
+	client, srv := newTestClient(t, fake.Options{})
+
+	resp, err := client.SendTextMessage(context.Background(), "15551234567", "hello", whatsapp.WithReplyTo("wamid.previous"))
+	if err != nil {
+		t.Fatalf("SendTextMessage failed: %v", err)
+	}
+	if len(resp.Messages) != 1 || resp.Messages[0].ID == "" {
+		t.Fatalf("Expected a message ID, got %+v", resp)
+	}
+
+	messages := srv.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 sent message, got %d", len(messages))
+	}
+	sent := messages[0]
+	if sent.ID != resp.Messages[0].ID || sent.To != "15551234567" || sent.Type != "text" {
+		t.Errorf("Unexpected sent message: %+v", sent)
+	}
+
+	text, _ := sent.Payload["text"].(map[string]interface{})
+	if text["body"] != "hello" {
+		t.Errorf("Expected body hello, got %v", text["body"])
+	}
+	replyContext, _ := sent.Payload["context"].(map[string]interface{})
+	if replyContext["message_id"] != "wamid.previous" {
+		t.Errorf("Expected reply context wamid.previous, got %v", replyContext["message_id"])
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 1 || requests[0].Path != "/"+fake.DefaultPhoneNumberID+"/messages" {
+		t.Fatalf("Unexpected requests: %+v", requests)
+	}
+	if auth := requests[0].Header.Get("Authorization"); auth != "Bearer fake-token" {
+		t.Errorf("Expected bearer token, got %q", auth)
+	}
+}
+
+func TestMarkAsRead(t *testing.T) {
+	client, srv := newTestClient(t, fake.Options{})
+
+	if err := client.MarkAsRead(context.Background(), "wamid.inbound", true); err != nil {
+		t.Fatalf("MarkAsRead failed: %v", err)
+	}
+	if len(srv.Messages()) != 0 {
+		t.Errorf("Read receipts should not be recorded as sent messages")
+	}
+
+	var payload map[string]interface{}
+	if err := srv.Requests()[0].JSON(&payload); err != nil {
+		t.Fatalf("Failed to decode request: %v", err)
+	}
+	if payload["status"] != "read" || payload["message_id"] != "wamid.inbound" {
+		t.Errorf("Unexpected read receipt payload: %v", payload)
+	}
+}
+
+func TestMediaRoundTrip(t *testing.T) {
+	client, _ := newTestClient(t, fake.Options{})
+	ctx := context.Background()
+	data := []byte("fake image bytes")
+
+	mediaID, err := client.UploadMedia(ctx, "photo.jpg", "image/jpeg", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("UploadMedia failed: %v", err)
+	}
+
+	info, err := client.GetMediaURL(ctx, mediaID)
+	if err != nil {
+		t.Fatalf("GetMediaURL failed: %v", err)
+	}
+	if info.MimeType != "image/jpeg" || info.FileSize != int64(len(data)) {
+		t.Errorf("Unexpected media info: %+v", info)
+	}
+
+	downloaded, err := client.DownloadMedia(ctx, info.URL, whatsapp.MaxMediaSize(whatsapp.MediaTypeImage))
+	if err != nil {
+		t.Fatalf("DownloadMedia failed: %v", err)
+	}
+	if !bytes.Equal(downloaded, data) {
+		t.Errorf("Downloaded content does not match the upload")
+	}
+	if !whatsapp.VerifySHA256(downloaded, info.SHA256) {
+		t.Errorf("SHA256 %s does not match the content", info.SHA256)
+	}
+
+	if _, err := client.DownloadMedia(ctx, info.URL, 4); err == nil {
+		t.Errorf("Expected an error for media over the size limit")
+	}
+}
+
+func TestTemplateLifecycle(t *testing.T) {
+	client, srv := newTestClient(t, fake.Options{})
+	ctx := context.Background()
+
+	// More templates than fit on one page, to exercise pagination
+	for i := 0; i < 120; i++ {
+		srv.AddTemplate(whatsapp.TemplateDefinition{
+			Name:     "seeded_" + string(rune('a'+i%26)) + string(rune('a'+i/26)),
+			Language: "en_US",
+			Category: "UTILITY",
+			Status:   "APPROVED",
+		})
+	}
+
+	created, err := client.CreateTemplate(ctx, &whatsapp.TemplateDefinition{
+		Name:     "order_update",
+		Language: "en_US",
+		Category: "UTILITY",
+		Components: []whatsapp.TemplateDefinitionComponent{
+			{Type: "BODY", Text: "Your order {{1}} has shipped"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTemplate failed: %v", err)
+	}
+	if created.ID == "" || created.Status != "PENDING" {
+		t.Errorf("Unexpected create response: %+v", created)
+	}
+
+	if _, err := client.CreateTemplate(ctx, &whatsapp.TemplateDefinition{Name: "order_update", Language: "en_US", Category: "UTILITY"}); err == nil {
+		t.Errorf("Expected an error creating a duplicate template")
+	}
+
+	srv.SetTemplateStatus("order_update", "APPROVED")
+
+	templates, err := client.ListTemplates(ctx)
+	if err != nil {
+		t.Fatalf("ListTemplates failed: %v", err)
+	}
+	if len(templates) != 121 {
+		t.Fatalf("Expected 121 templates across pages, got %d", len(templates))
+	}
+	last := templates[len(templates)-1]
+	if last.Name != "order_update" || last.Status != "APPROVED" || last.BodyText() != "Your order {{1}} has shipped" {
+		t.Errorf("Unexpected template: %+v", last)
+	}
+
+	if err := client.DeleteTemplate(ctx, "order_update", created.ID); err != nil {
+		t.Fatalf("DeleteTemplate failed: %v", err)
+	}
+	if got := len(srv.Templates()); got != 120 {
+		t.Errorf("Expected 120 templates after delete, got %d", got)
+	}
+}
+
+func TestGetPhoneNumber(t *testing.T) {
+	client, _ := newTestClient(t, fake.Options{DisplayPhoneNumber: "+1 555-010-1234"})
+
+	info, err := client.GetPhoneNumber(context.Background())
+	if err != nil {
+		t.Fatalf("GetPhoneNumber failed: %v", err)
+	}
+	if info.ID != fake.DefaultPhoneNumberID || info.DisplayPhoneNumber != "+1 555-010-1234" {
+		t.Errorf("Unexpected phone number: %+v", info)
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		name     string
+		failure  fake.Failure
+		code     string
+		status   int
+		requests int
+	}{
+		{
+			name:     "reengagement required is not retried",
+			failure:  fake.Failure{Code: whatsapp.ErrorCodeReengagementRequired, Message: "Re-engagement message"},
+			code:     errors.ErrReengagementRequired,
+			status:   http.StatusUnprocessableEntity,
+			requests: 1,
+		},
+		{
+			name:     "invalid parameter is not retried",
+			failure:  fake.Failure{Code: whatsapp.ErrorCodeInvalidParameter, Message: "Invalid parameter"},
+			code:     errors.ErrWhatsAppInvalidRequest,
+			status:   http.StatusBadRequest,
+			requests: 1,
+		},
+		{
+			name:     "rate limit is retried until it gives up",
+			failure:  fake.Failure{Status: http.StatusTooManyRequests, Code: whatsapp.ErrorCodeRateLimited, Times: 10},
+			code:     errors.ErrWhatsAppRateLimited,
+			status:   http.StatusTooManyRequests,
+			requests: 4,
+		},
+		{
+			name:     "invalid token",
+			failure:  fake.Failure{Status: http.StatusUnauthorized, Code: whatsapp.ErrorCodeAccessTokenExpired},
+			code:     errors.ErrWhatsAppAuth,
+			status:   http.StatusBadGateway,
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, srv := newTestClient(t, fake.Options{})
+			srv.FailNext(tt.failure)
+
+			_, err := client.SendTextMessage(context.Background(), "15551234567", "hello")
+			appErr, ok := err.(*errors.AppError)
+			if !ok {
+				t.Fatalf("Expected an AppError, got %v", err)
+			}
+			if appErr.Code != tt.code || appErr.StatusCode != tt.status {
+				t.Errorf("Expected %s/%d, got %s/%d", tt.code, tt.status, appErr.Code, appErr.StatusCode)
+			}
+			if got := len(srv.Requests()); got != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, got)
+			}
+		})
+	}
+}
+
+func TestTransientErrorRecovers(t *testing.T) {
+	client, srv := newTestClient(t, fake.Options{})
+	srv.FailNext(fake.Failure{Status: http.StatusServiceUnavailable, Code: whatsapp.ErrorCodeServiceUnavailable, Times: 2})
+
+	if _, err := client.SendTextMessage(context.Background(), "15551234567", "hello"); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if got := len(srv.Requests()); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+	if got := len(srv.Messages()); got != 1 {
+		t.Errorf("Expected 1 sent message, got %d", got)
+	}
+}
+
+func TestSendNotRetriedAfterTransportError(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// Drop the connection after the request was received, as if the
+		// response was lost on the way back
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := whatsapp.NewClient(whatsapp.Config{
+		APIToken:      "fake-token",
+		PhoneNumberID: fake.DefaultPhoneNumberID,
+		APIBaseURL:    srv.URL,
+		APIVersion:    "v18.0",
+		Logger:        zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.SetRetryPolicy(3, time.Millisecond)
+
+	if _, err := client.SendTextMessage(context.Background(), "15551234567", "hello"); err == nil {
+		t.Fatalf("Expected the send to fail")
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected the send not to be retried, got %d requests", got)
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	if _, err := client.GetMessageStatus(context.Background(), "wamid.1"); err == nil {
+		t.Fatalf("Expected the lookup to fail")
+	}
+	if got := atomic.LoadInt32(&requests); got != 4 {
+		t.Errorf("Expected the lookup to be retried, got %d requests", got)
+	}
+}
+
+func TestAccessTokenRequired(t *testing.T) {
+	srv := fake.NewServer(fake.Options{AccessToken: "secret-token"}).Start()
+	t.Cleanup(srv.Close)
+
+	config := srv.ClientConfig()
+	config.APIToken = "wrong-token"
+	client, err := whatsapp.NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.SendTextMessage(context.Background(), "15551234567", "hello")
+	apiErr, ok := whatsapp.AsAPIError(err)
+	if !ok || apiErr.Category != whatsapp.ErrorCategoryAuthentication {
+		t.Fatalf("Expected an authentication error, got %v", err)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	client, srv := newTestClient(t, fake.Options{Latency: 200 * time.Millisecond})
+	client.SetTimeout(50 * time.Millisecond)
+	client.SetRetryPolicy(0, time.Millisecond)
+
+	if _, err := client.SendTextMessage(context.Background(), "15551234567", "hello"); err == nil {
+		t.Fatalf("Expected the request to time out")
+	}
+	if got := len(srv.Messages()); got != 0 {
+		t.Errorf("Expected no sent messages, got %d", got)
+	}
+}
@@ -0,0 +1,120 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Limits imposed by the WhatsApp Cloud API on commerce messages
+const (
+	MaxProductListSections = 10
+	MaxProductListItems    = 30
+)
+
+// ProductItem references a catalog product by its retailer ID (the SKU)
+type ProductItem struct {
+	ProductRetailerID string `json:"product_retailer_id"`
+}
+
+// Order represents an order placed from a catalog, cart or product message
+type Order struct {
+	CatalogID    string             `json:"catalog_id"`
+	Text         string             `json:"text,omitempty"`
+	ProductItems []OrderProductItem `json:"product_items"`
+}
+
+// OrderProductItem represents a line item of an inbound order
+type OrderProductItem struct {
+	ProductRetailerID string  `json:"product_retailer_id"`
+	Quantity          int     `json:"quantity"`
+	ItemPrice         float64 `json:"item_price"`
+	Currency          string  `json:"currency"`
+}
+
+// Total returns the sum of the line items; all items of an order share one currency
+func (o *Order) Total() float64 {
+	var total float64
+	for _, item := range o.ProductItems {
+		total += item.ItemPrice * float64(item.Quantity)
+	}
+	return total
+}
+
+// Currency returns the currency of the order's line items
+func (o *Order) Currency() string {
+	if len(o.ProductItems) == 0 {
+		return ""
+	}
+	return o.ProductItems[0].Currency
+}
+
+func (i *Interactive) validateProduct() error {
+	if i.Header != nil {
+		return errors.New("product messages do not support headers")
+	}
+	if i.Action.CatalogID == "" || i.Action.ProductRetailerID == "" {
+		return errors.New("product messages require catalog_id and product_retailer_id")
+	}
+	return nil
+}
+
+func (i *Interactive) validateProductList() error {
+	if i.Header == nil || i.Header.Type != "text" {
+		return errors.New("product_list messages require a text header")
+	}
+	if i.Action.CatalogID == "" {
+		return errors.New("product_list messages require catalog_id")
+	}
+
+	sections := i.Action.Sections
+	if len(sections) == 0 {
+		return errors.New("at least one product section is required")
+	}
+	if len(sections) > MaxProductListSections {
+		return fmt.Errorf("at most %d product sections are allowed, got %d", MaxProductListSections, len(sections))
+	}
+
+	totalItems := 0
+	seen := make(map[string]bool)
+	for sIdx, section := range sections {
+		if section.Title == "" {
+			return fmt.Errorf("section %d: title is required", sIdx+1)
+		}
+		if err := checkLength(section.Title, "section title", MaxSectionTitleLength); err != nil {
+			return err
+		}
+		if len(section.Rows) > 0 {
+			return fmt.Errorf("section %d: product_list sections take product_items, not rows", sIdx+1)
+		}
+		if len(section.ProductItems) == 0 {
+			return fmt.Errorf("section %d: at least one product item is required", sIdx+1)
+		}
+		for pIdx, item := range section.ProductItems {
+			if item.ProductRetailerID == "" {
+				return fmt.Errorf("section %d product %d: product_retailer_id is required", sIdx+1, pIdx+1)
+			}
+			if seen[item.ProductRetailerID] {
+				return fmt.Errorf("duplicate product_retailer_id: %s", item.ProductRetailerID)
+			}
+			seen[item.ProductRetailerID] = true
+		}
+		totalItems += len(section.ProductItems)
+	}
+	if totalItems > MaxProductListItems {
+		return fmt.Errorf("at most %d products are allowed, got %d", MaxProductListItems, totalItems)
+	}
+	return nil
+}
+
+func (i *Interactive) validateCatalog() error {
+	if i.Header != nil {
+		return errors.New("catalog messages do not support headers")
+	}
+	if i.Action.Name == "" {
+		i.Action.Name = string(InteractiveTypeCatalog)
+	}
+	if i.Action.Name != string(InteractiveTypeCatalog) {
+		return fmt.Errorf("unsupported action name for catalog_message: %s", i.Action.Name)
+	}
+	return nil
+}
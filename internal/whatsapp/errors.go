@@ -0,0 +1,233 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"github.com/go-resty/resty/v2"
+)
+
+// ErrorCategory groups Graph API errors by how a caller should react to them
+type ErrorCategory string
+
+const (
+	ErrorCategoryRateLimited          ErrorCategory = "rate_limited"
+	ErrorCategoryReengagementRequired ErrorCategory = "reengagement_required"
+	ErrorCategoryRecipientUnavailable ErrorCategory = "recipient_unavailable"
+	ErrorCategoryAuthentication       ErrorCategory = "authentication"
+	ErrorCategoryTemplate             ErrorCategory = "template"
+	ErrorCategoryInvalidRequest       ErrorCategory = "invalid_request"
+	ErrorCategoryTemporary            ErrorCategory = "temporary"
+	ErrorCategoryUnknown              ErrorCategory = "unknown"
+)
+
+// Graph API error codes that are classified explicitly
+// See https://developers.facebook.com/docs/whatsapp/cloud-api/support/error-codes
+const (
+	ErrorCodeUnknown              = 1
+	ErrorCodeServiceUnavailable   = 2
+	ErrorCodeAppRateLimited       = 4
+	ErrorCodePermissionDenied     = 10
+	ErrorCodeInvalidParameter     = 100
+	ErrorCodeAccessTokenExpired   = 190
+	ErrorCodeBusinessRateLimited  = 80007
+	ErrorCodeRateLimited          = 130429
+	ErrorCodeGenericUserError     = 131000
+	ErrorCodeParameterMissing     = 131008
+	ErrorCodeParameterInvalid     = 131009
+	ErrorCodeServiceOverloaded    = 131016
+	ErrorCodeRecipientUnavailable = 131026
+	ErrorCodeReengagementRequired = 131047
+	ErrorCodeSpamRateLimited      = 131048
+	ErrorCodePairRateLimited      = 131056
+	ErrorCodeServerUnavailable    = 133004
+)
+
+// APIError is a classified error returned by the Graph API
+type APIError struct {
+	StatusCode int           `json:"status_code"`
+	Code       int           `json:"code"`
+	Subcode    int           `json:"subcode,omitempty"`
+	Type       string        `json:"type,omitempty"`
+	Message    string        `json:"message"`
+	Details    string        `json:"details,omitempty"`
+	FBTraceID  string        `json:"fbtrace_id,omitempty"`
+	Category   ErrorCategory `json:"category"`
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("WhatsApp API returned status %d", e.StatusCode)
+	}
+	if e.Details != "" {
+		return fmt.Sprintf("%s (#%d): %s: %s", e.Type, e.Code, e.Message, e.Details)
+	}
+	return fmt.Sprintf("%s (#%d): %s", e.Type, e.Code, e.Message)
+}
+
+// Retryable reports whether the same request may succeed if sent again later
+func (e *APIError) Retryable() bool {
+	switch e.Category {
+	case ErrorCategoryRateLimited, ErrorCategoryTemporary:
+		return true
+	case ErrorCategoryUnknown:
+		return e.StatusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
+// ClassifyError maps a Graph API error code, or the HTTP status when no code
+// is available, to an error category
+func ClassifyError(code, statusCode int) ErrorCategory {
+	switch {
+	case code == ErrorCodeRateLimited, code == ErrorCodePairRateLimited,
+		code == ErrorCodeSpamRateLimited, code == ErrorCodeAppRateLimited,
+		code == ErrorCodeBusinessRateLimited:
+		return ErrorCategoryRateLimited
+	case code == ErrorCodeReengagementRequired:
+		return ErrorCategoryReengagementRequired
+	case code == ErrorCodeRecipientUnavailable:
+		return ErrorCategoryRecipientUnavailable
+	case code == ErrorCodeAccessTokenExpired, code == ErrorCodePermissionDenied:
+		return ErrorCategoryAuthentication
+	case code >= 132000 && code < 133000:
+		return ErrorCategoryTemplate
+	case code == ErrorCodeInvalidParameter, code == ErrorCodeParameterMissing,
+		code == ErrorCodeParameterInvalid:
+		return ErrorCategoryInvalidRequest
+	case code == ErrorCodeUnknown, code == ErrorCodeServiceUnavailable,
+		code == ErrorCodeGenericUserError, code == ErrorCodeServiceOverloaded,
+		code == ErrorCodeServerUnavailable:
+		return ErrorCategoryTemporary
+	}
+
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorCategoryRateLimited
+	case statusCode == http.StatusUnauthorized:
+		return ErrorCategoryAuthentication
+	case statusCode >= http.StatusInternalServerError:
+		return ErrorCategoryTemporary
+	default:
+		return ErrorCategoryUnknown
+	}
+}
+
+// newAPIError builds a classified error from a Graph API error response
+func newAPIError(resp *resty.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode()}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(resp.Body(), &errResp); err == nil && errResp.Error.Message != "" {
+		apiErr.Code = errResp.Error.Code
+		apiErr.Subcode = errResp.Error.ErrorSubcode
+		apiErr.Type = errResp.Error.Type
+		apiErr.Message = errResp.Error.Message
+		apiErr.Details = errResp.Error.ErrorData.Details
+		apiErr.FBTraceID = errResp.Error.FBTraceID
+	}
+
+	apiErr.Category = ClassifyError(apiErr.Code, apiErr.StatusCode)
+	return apiErr
+}
+
+// AppError converts the error into an application error carrying the Graph
+// error fields in its details
+func (e *APIError) AppError() *errors.AppError {
+	code, message, status := errors.ErrWhatsAppAPI, "WhatsApp API error", http.StatusBadGateway
+
+	switch e.Category {
+	case ErrorCategoryRateLimited:
+		code, message, status = errors.ErrWhatsAppRateLimited, "WhatsApp rate limit reached, retry later", http.StatusTooManyRequests
+	case ErrorCategoryReengagementRequired:
+		code, message, status = errors.ErrReengagementRequired, "More than 24 hours have passed since the customer last replied, send a template message instead", http.StatusUnprocessableEntity
+	case ErrorCategoryRecipientUnavailable:
+		code, message, status = errors.ErrRecipientUnavailable, "Recipient is not reachable on WhatsApp", http.StatusUnprocessableEntity
+	case ErrorCategoryAuthentication:
+		message = "WhatsApp access token is invalid or lacks permission"
+		code = errors.ErrWhatsAppAuth
+	case ErrorCategoryTemplate:
+		code, message, status = errors.ErrWhatsAppTemplate, "WhatsApp rejected the template message", http.StatusUnprocessableEntity
+	case ErrorCategoryInvalidRequest:
+		code, message, status = errors.ErrWhatsAppInvalidRequest, "WhatsApp rejected the request parameters", http.StatusBadRequest
+	case ErrorCategoryTemporary:
+		status = http.StatusServiceUnavailable
+	}
+
+	details := map[string]interface{}{
+		"whatsapp_error": e.Error(),
+		"category":       string(e.Category),
+		"retryable":      e.Retryable(),
+		"status_code":    e.StatusCode,
+	}
+	if e.Code != 0 {
+		details["whatsapp_code"] = e.Code
+		details["whatsapp_type"] = e.Type
+		details["whatsapp_message"] = e.Message
+	}
+	if e.Subcode != 0 {
+		details["whatsapp_subcode"] = e.Subcode
+	}
+	if e.Details != "" {
+		details["whatsapp_details"] = e.Details
+	}
+	if e.FBTraceID != "" {
+		details["fbtrace_id"] = e.FBTraceID
+	}
+
+	return errors.NewAppError(code, message, status).WithError(e).WithDetails(details)
+}
+
+// AsAPIError extracts a classified Graph API error from err, if there is one
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if stderrors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsRetryable reports whether an operation that failed with err is worth
+// retrying. Errors that were not classified by the Graph API, such as network
+// failures and timeouts, are assumed to be transient.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apiErr, ok := AsAPIError(err); ok {
+		return apiErr.Retryable()
+	}
+	return !stderrors.Is(err, context.Canceled)
+}
+
+// retryOnTransientError is the resty retry condition for Graph API calls:
+// transport failures and retryable API errors are retried, permanent ones are
+// not. A POST that failed in transport may already have been acted on, such as
+// a message that was sent before the connection dropped, so it is only retried
+// when the connection could not be established.
+func retryOnTransientError(resp *resty.Response, err error) bool {
+	if err != nil {
+		if resp != nil && resp.Request != nil && resp.Request.Method == http.MethodPost {
+			return isConnectError(err)
+		}
+		return true
+	}
+	if resp == nil || !resp.IsError() {
+		return false
+	}
+	return newAPIError(resp).Retryable()
+}
+
+// isConnectError reports whether err happened while dialling, before any part
+// of the request was sent
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	return stderrors.As(err, &opErr) && opErr.Op == "dial"
+}
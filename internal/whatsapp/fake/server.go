@@ -0,0 +1,753 @@
+// Package fake implements an in-process fake of the WhatsApp Cloud (Graph) API
+// for tests and local development. It serves the messages, media, template and
+// phone number endpoints used by whatsapp.Client, records every request for
+// assertions, can inject errors and latency, and emits signed webhooks back to
+// a configured URL.
+package fake
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Defaults used when Options leaves a field empty
+const (
+	DefaultPhoneNumberID      = "100000000000001"
+	DefaultDisplayPhoneNumber = "+1 555-000-0001"
+	DefaultBusinessAccountID  = "200000000000001"
+	DefaultAPIVersion         = "v18.0"
+)
+
+// controlPrefix is the path prefix of the endpoints that drive the fake itself
+const controlPrefix = "/_fake"
+
+// Options configures a fake Graph API server
+type Options struct {
+	PhoneNumberID      string
+	DisplayPhoneNumber string
+	BusinessAccountID  string
+	APIVersion         string
+	AccessToken        string        // when set, requests must carry it as a bearer token
+	AppSecret          string        // signs emitted webhooks with X-Hub-Signature-256
+	WebhookURL         string        // where emitted webhooks are delivered
+	Latency            time.Duration // delay added to every Graph API request
+	AutoStatuses       bool          // emit sent and delivered statuses for each sent message
+}
+
+// Request is a Graph API request received by the fake
+type Request struct {
+	Method     string      `json:"method"`
+	Path       string      `json:"path"` // without the API version prefix
+	Query      url.Values  `json:"query,omitempty"`
+	Header     http.Header `json:"-"`
+	Body       []byte      `json:"body,omitempty"`
+	ReceivedAt time.Time   `json:"received_at"`
+}
+
+// JSON decodes the request body into v
+func (r Request) JSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// SentMessage is a message accepted by the messages endpoint
+type SentMessage struct {
+	ID      string                 `json:"id"`
+	To      string                 `json:"to"`
+	Type    string                 `json:"type"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// Failure makes matching requests fail with a Graph API error
+type Failure struct {
+	Method  string `json:"method,omitempty"` // empty matches any method
+	Path    string `json:"path,omitempty"`   // path prefix without the API version, empty matches any path
+	Status  int    `json:"status"`           // HTTP status, defaults to 400
+	Code    int    `json:"code"`
+	Subcode int    `json:"subcode,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message,omitempty"`
+	Times   int    `json:"times,omitempty"` // number of requests to fail, defaults to 1
+}
+
+type mediaObject struct {
+	id       string
+	mimeType string
+	sha256   string
+	data     []byte
+}
+
+// Server is a fake Graph API server. It implements http.Handler and can be
+// mounted on any listener, or started on a local port with Start.
+type Server struct {
+	// URL is the base URL of the server once started, to be used as the
+	// client's APIBaseURL
+	URL string
+
+	opts          Options
+	httpServer    *httptest.Server
+	webhookClient *http.Client
+	wg            sync.WaitGroup
+
+	mu        sync.Mutex
+	seq       int
+	requests  []Request
+	messages  []SentMessage
+	failures  []*Failure
+	media     map[string]*mediaObject
+	templates []whatsapp.TemplateDefinition
+}
+
+// NewServer creates a fake Graph API server
+func NewServer(opts Options) *Server {
+	if opts.PhoneNumberID == "" {
+		opts.PhoneNumberID = DefaultPhoneNumberID
+	}
+	if opts.DisplayPhoneNumber == "" {
+		opts.DisplayPhoneNumber = DefaultDisplayPhoneNumber
+	}
+	if opts.BusinessAccountID == "" {
+		opts.BusinessAccountID = DefaultBusinessAccountID
+	}
+	if opts.APIVersion == "" {
+		opts.APIVersion = DefaultAPIVersion
+	}
+
+	return &Server{
+		opts:          opts,
+		webhookClient: &http.Client{Timeout: 10 * time.Second},
+		media:         make(map[string]*mediaObject),
+	}
+}
+
+// Start serves the fake on a random local port and sets URL
+func (s *Server) Start() *Server {
+	s.httpServer = httptest.NewServer(s)
+	s.URL = s.httpServer.URL
+	return s
+}
+
+// Close waits for pending webhooks and stops a server started with Start
+func (s *Server) Close() {
+	s.wg.Wait()
+	if s.httpServer != nil {
+		s.httpServer.Close()
+	}
+}
+
+// ClientConfig returns a client configuration pointing at the fake
+func (s *Server) ClientConfig() whatsapp.Config {
+	token := s.opts.AccessToken
+	if token == "" {
+		token = "fake-token"
+	}
+	return whatsapp.Config{
+		APIToken:          token,
+		PhoneNumberID:     s.opts.PhoneNumberID,
+		BusinessAccountID: s.opts.BusinessAccountID,
+		APIBaseURL:        s.URL,
+		APIVersion:        s.opts.APIVersion,
+		Logger:            zap.NewNop(),
+	}
+}
+
+// SetWebhookURL changes where emitted webhooks are delivered
+func (s *Server) SetWebhookURL(webhookURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts.WebhookURL = webhookURL
+}
+
+// SetLatency changes the delay added to every Graph API request
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts.Latency = latency
+}
+
+// FailNext queues a failure for the next matching requests
+func (s *Server) FailNext(failure Failure) {
+	if failure.Status == 0 {
+		failure.Status = http.StatusBadRequest
+	}
+	if failure.Times == 0 {
+		failure.Times = 1
+	}
+	if failure.Type == "" {
+		failure.Type = "OAuthException"
+	}
+	if failure.Message == "" {
+		failure.Message = "Injected failure"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &failure)
+}
+
+// Requests returns the Graph API requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Messages returns the messages accepted so far
+func (s *Server) Messages() []SentMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SentMessage(nil), s.messages...)
+}
+
+// Reset clears recorded requests, messages and pending failures.
+// Stored media and templates are kept.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.messages = nil
+	s.failures = nil
+}
+
+// AddMedia stores media as if it had been uploaded and returns its ID,
+// for inbound media messages that reference it
+func (s *Server) AddMedia(data []byte, mimeType string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addMediaLocked(data, mimeType)
+}
+
+// AddTemplate stores a template in the business account
+func (s *Server) AddTemplate(template whatsapp.TemplateDefinition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if template.ID == "" {
+		template.ID = s.nextID("")
+	}
+	s.templates = append(s.templates, template)
+}
+
+// SetTemplateStatus changes the review status of every language of a template
+func (s *Server) SetTemplateStatus(name, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.templates {
+		if s.templates[i].Name == name {
+			s.templates[i].Status = status
+		}
+	}
+}
+
+// Templates returns the templates in the business account
+func (s *Server) Templates() []whatsapp.TemplateDefinition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]whatsapp.TemplateDefinition(nil), s.templates...)
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, controlPrefix+"/") {
+		s.serveControl(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, 100, 0, "Could not read request body")
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	versionPrefix := "/" + s.opts.APIVersion
+	if !strings.HasPrefix(r.URL.Path, versionPrefix+"/") {
+		writeError(w, http.StatusNotFound, 100, 0, "Unknown path: "+r.URL.Path)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, versionPrefix)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method:     r.Method,
+		Path:       path,
+		Query:      r.URL.Query(),
+		Header:     r.Header.Clone(),
+		Body:       body,
+		ReceivedAt: time.Now().UTC(),
+	})
+	latency := s.opts.Latency
+	failure := s.takeFailureLocked(r.Method, path)
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if s.opts.AccessToken != "" && r.Header.Get("Authorization") != "Bearer "+s.opts.AccessToken {
+		writeErrorType(w, http.StatusUnauthorized, 190, 0, "OAuthException", "Invalid OAuth access token")
+		return
+	}
+
+	if failure != nil {
+		writeErrorType(w, failure.Status, failure.Code, failure.Subcode, failure.Type, failure.Message)
+		return
+	}
+
+	s.route(w, r, path)
+}
+
+// route dispatches a Graph API request by path
+func (s *Server) route(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case len(segments) == 2 && segments[1] == "messages" && r.Method == http.MethodPost:
+		s.handleMessages(w, r, segments[0])
+	case len(segments) == 2 && segments[1] == "media" && r.Method == http.MethodPost:
+		s.handleUpload(w, r, segments[0])
+	case len(segments) == 2 && segments[1] == "message_templates":
+		s.handleTemplates(w, r, segments[0])
+	case len(segments) == 2 && segments[1] == "phone_numbers" && r.Method == http.MethodGet:
+		s.handlePhoneNumbers(w, segments[0])
+	case len(segments) == 3 && segments[0] == "media" && segments[2] == "download" && r.Method == http.MethodGet:
+		s.handleDownload(w, segments[1])
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		s.handleObject(w, segments[0])
+	default:
+		writeError(w, http.StatusBadRequest, 100, 0, fmt.Sprintf("Unsupported %s request", strings.ToLower(r.Method)))
+	}
+}
+
+func (s *Server) handleMessages(w http.ResponseWriter, r *http.Request, phoneNumberID string) {
+	if !s.checkPhoneNumber(w, phoneNumberID) {
+		return
+	}
+
+	var payload map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, 100, 0, "Invalid JSON payload")
+		return
+	}
+
+	// Read receipts and typing indicators share the endpoint with sends
+	if status, _ := payload["status"].(string); status == "read" {
+		writeJSON(w, http.StatusOK, whatsapp.SuccessResponse{Success: true})
+		return
+	}
+
+	to, _ := payload["to"].(string)
+	msgType, _ := payload["type"].(string)
+	if to == "" {
+		writeError(w, http.StatusBadRequest, 100, 0, "The parameter to is required.")
+		return
+	}
+	if msgType == "" {
+		msgType = "text"
+	}
+
+	s.mu.Lock()
+	message := SentMessage{
+		ID:      "wamid." + s.nextID("FAKE"),
+		To:      to,
+		Type:    msgType,
+		Payload: payload,
+	}
+	s.messages = append(s.messages, message)
+	s.mu.Unlock()
+
+	waID := strings.TrimPrefix(to, "+")
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"contacts":          []map[string]string{{"input": to, "wa_id": waID}},
+		"messages":          []map[string]string{{"id": message.ID}},
+	})
+
+	if s.opts.AutoStatuses {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			for _, status := range []string{"sent", "delivered"} {
+				if err := s.EmitStatus(ctx, message.ID, waID, status); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request, phoneNumberID string) {
+	if !s.checkPhoneNumber(w, phoneNumberID) {
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, 100, 0, "The parameter file is required.")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, 100, 0, "Could not read uploaded file")
+		return
+	}
+
+	mimeType := r.FormValue("type")
+	if mimeType == "" {
+		mimeType = header.Header.Get("Content-Type")
+	}
+
+	s.mu.Lock()
+	id := s.addMediaLocked(data, mimeType)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, whatsapp.MediaUploadResponse{ID: id})
+}
+
+func (s *Server) handleDownload(w http.ResponseWriter, mediaID string) {
+	s.mu.Lock()
+	media, ok := s.media[mediaID]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, 100, 33, "Media not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", media.mimeType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(media.data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(media.data)
+}
+
+// handleObject serves GET /{id} for the phone number and stored media
+func (s *Server) handleObject(w http.ResponseWriter, id string) {
+	if id == s.opts.PhoneNumberID {
+		writeJSON(w, http.StatusOK, s.phoneNumberInfo())
+		return
+	}
+
+	s.mu.Lock()
+	media, ok := s.media[id]
+	s.mu.Unlock()
+	if ok {
+		writeJSON(w, http.StatusOK, whatsapp.MediaURLResponse{
+			MessagingProduct: "whatsapp",
+			URL:              fmt.Sprintf("%s/%s/media/%s/download", s.URL, s.opts.APIVersion, media.id),
+			MimeType:         media.mimeType,
+			SHA256:           media.sha256,
+			FileSize:         int64(len(media.data)),
+			ID:               media.id,
+		})
+		return
+	}
+
+	writeError(w, http.StatusBadRequest, 100, 33, fmt.Sprintf("Unsupported get request. Object with ID '%s' does not exist", id))
+}
+
+func (s *Server) handlePhoneNumbers(w http.ResponseWriter, businessAccountID string) {
+	if !s.checkBusinessAccount(w, businessAccountID) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": []whatsapp.PhoneNumberInfo{s.phoneNumberInfo()},
+	})
+}
+
+func (s *Server) handleTemplates(w http.ResponseWriter, r *http.Request, businessAccountID string) {
+	if !s.checkBusinessAccount(w, businessAccountID) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.listTemplates(w, r)
+	case http.MethodPost:
+		s.createTemplate(w, r)
+	case http.MethodDelete:
+		s.deleteTemplate(w, r)
+	default:
+		writeError(w, http.StatusBadRequest, 100, 0, "Unsupported request method")
+	}
+}
+
+func (s *Server) listTemplates(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 25
+	}
+	start, _ := strconv.Atoi(r.URL.Query().Get("after"))
+
+	s.mu.Lock()
+	templates := append([]whatsapp.TemplateDefinition(nil), s.templates...)
+	s.mu.Unlock()
+
+	if start > len(templates) {
+		start = len(templates)
+	}
+	end := start + limit
+	if end > len(templates) {
+		end = len(templates)
+	}
+
+	paging := map[string]interface{}{
+		"cursors": map[string]string{"before": strconv.Itoa(start), "after": strconv.Itoa(end)},
+	}
+	if end < len(templates) {
+		query := r.URL.Query()
+		query.Set("after", strconv.Itoa(end))
+		paging["next"] = s.URL + r.URL.Path + "?" + query.Encode()
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":   templates[start:end],
+		"paging": paging,
+	})
+}
+
+func (s *Server) createTemplate(w http.ResponseWriter, r *http.Request) {
+	var template whatsapp.TemplateDefinition
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		writeError(w, http.StatusBadRequest, 100, 0, "Invalid JSON payload")
+		return
+	}
+	if template.Name == "" || template.Language == "" || template.Category == "" {
+		writeError(w, http.StatusBadRequest, 100, 0, "The parameters name, language and category are required.")
+		return
+	}
+
+	s.mu.Lock()
+	for _, existing := range s.templates {
+		if existing.Name == template.Name && existing.Language == template.Language {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, 100, 2388024, "Message template already exists with this name and language")
+			return
+		}
+	}
+	template.ID = s.nextID("")
+	template.Status = "PENDING"
+	s.templates = append(s.templates, template)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, whatsapp.CreateTemplateResponse{
+		ID:       template.ID,
+		Status:   template.Status,
+		Category: template.Category,
+	})
+}
+
+func (s *Server) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	templateID := r.URL.Query().Get("hsm_id")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, 100, 0, "The parameter name is required.")
+		return
+	}
+
+	s.mu.Lock()
+	kept := s.templates[:0]
+	deleted := 0
+	for _, template := range s.templates {
+		if template.Name == name && (templateID == "" || template.ID == templateID) {
+			deleted++
+			continue
+		}
+		kept = append(kept, template)
+	}
+	s.templates = kept
+	s.mu.Unlock()
+
+	if deleted == 0 {
+		writeError(w, http.StatusBadRequest, 100, 2593002, "Message template not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, whatsapp.SuccessResponse{Success: true})
+}
+
+func (s *Server) phoneNumberInfo() whatsapp.PhoneNumberInfo {
+	return whatsapp.PhoneNumberInfo{
+		ID:                     s.opts.PhoneNumberID,
+		DisplayPhoneNumber:     s.opts.DisplayPhoneNumber,
+		VerifiedName:           "Fake Business",
+		NameStatus:             "APPROVED",
+		QualityRating:          "GREEN",
+		MessagingLimitTier:     "TIER_1K",
+		Status:                 "CONNECTED",
+		CodeVerificationStatus: "VERIFIED",
+		PlatformType:           "CLOUD_API",
+	}
+}
+
+func (s *Server) checkPhoneNumber(w http.ResponseWriter, phoneNumberID string) bool {
+	if phoneNumberID != s.opts.PhoneNumberID {
+		writeError(w, http.StatusBadRequest, 100, 33, fmt.Sprintf("Unsupported post request. Object with ID '%s' does not exist", phoneNumberID))
+		return false
+	}
+	return true
+}
+
+func (s *Server) checkBusinessAccount(w http.ResponseWriter, businessAccountID string) bool {
+	if businessAccountID != s.opts.BusinessAccountID {
+		writeError(w, http.StatusBadRequest, 100, 33, fmt.Sprintf("Object with ID '%s' does not exist", businessAccountID))
+		return false
+	}
+	return true
+}
+
+// takeFailureLocked returns the first queued failure matching the request,
+// consuming one of its repetitions
+func (s *Server) takeFailureLocked(method, path string) *Failure {
+	for i, failure := range s.failures {
+		if failure.Method != "" && !strings.EqualFold(failure.Method, method) {
+			continue
+		}
+		if failure.Path != "" && !strings.HasPrefix(path, failure.Path) {
+			continue
+		}
+
+		failure.Times--
+		if failure.Times <= 0 {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+		}
+		return failure
+	}
+	return nil
+}
+
+func (s *Server) addMediaLocked(data []byte, mimeType string) string {
+	sum := sha256.Sum256(data)
+	id := s.nextID("")
+	s.media[id] = &mediaObject{
+		id:       id,
+		mimeType: mimeType,
+		sha256:   hex.EncodeToString(sum[:]),
+		data:     append([]byte(nil), data...),
+	}
+	return id
+}
+
+// nextID returns a unique numeric ID, as Graph object IDs are
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s%d", prefix, 900000000000000+s.seq)
+}
+
+// serveControl handles the endpoints that drive the fake over HTTP, for use
+// from the fakegraph binary during local development
+func (s *Server) serveControl(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, controlPrefix)
+
+	switch {
+	case path == "/requests" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Requests())
+	case path == "/messages" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Messages())
+	case path == "/templates" && r.Method == http.MethodGet:
+		templates := s.Templates()
+		sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+		writeJSON(w, http.StatusOK, templates)
+	case path == "/reset" && r.Method == http.MethodPost:
+		s.Reset()
+		writeJSON(w, http.StatusOK, whatsapp.SuccessResponse{Success: true})
+	case path == "/failures" && r.Method == http.MethodPost:
+		var failure Failure
+		if err := json.NewDecoder(r.Body).Decode(&failure); err != nil {
+			writeError(w, http.StatusBadRequest, 100, 0, "Invalid JSON payload")
+			return
+		}
+		s.FailNext(failure)
+		writeJSON(w, http.StatusOK, whatsapp.SuccessResponse{Success: true})
+	case path == "/templates/status" && r.Method == http.MethodPost:
+		var req struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" || req.Status == "" {
+			writeError(w, http.StatusBadRequest, 100, 0, "name and status are required")
+			return
+		}
+		s.SetTemplateStatus(req.Name, req.Status)
+		writeJSON(w, http.StatusOK, whatsapp.SuccessResponse{Success: true})
+	case path == "/webhooks/message" && r.Method == http.MethodPost:
+		var req struct {
+			From string `json:"from"`
+			Name string `json:"name"`
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.From == "" || req.Text == "" {
+			writeError(w, http.StatusBadRequest, 100, 0, "from and text are required")
+			return
+		}
+		id, err := s.EmitText(r.Context(), req.From, req.Name, req.Text)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, 1, 0, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"id": id})
+	case path == "/webhooks/status" && r.Method == http.MethodPost:
+		var req struct {
+			MessageID   string `json:"message_id"`
+			RecipientID string `json:"recipient_id"`
+			Status      string `json:"status"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MessageID == "" || req.Status == "" {
+			writeError(w, http.StatusBadRequest, 100, 0, "message_id and status are required")
+			return
+		}
+		if err := s.EmitStatus(r.Context(), req.MessageID, req.RecipientID, req.Status); err != nil {
+			writeError(w, http.StatusBadGateway, 1, 0, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, whatsapp.SuccessResponse{Success: true})
+	default:
+		writeError(w, http.StatusNotFound, 100, 0, "Unknown control endpoint: "+r.URL.Path)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status, code, subcode int, message string) {
+	writeErrorType(w, status, code, subcode, "OAuthException", message)
+}
+
+// writeErrorType writes an error in the Graph API error format
+func writeErrorType(w http.ResponseWriter, status, code, subcode int, errType, message string) {
+	var resp whatsapp.ErrorResponse
+	resp.Error.Message = message
+	resp.Error.Type = errType
+	resp.Error.Code = code
+	resp.Error.ErrorSubcode = subcode
+	resp.Error.FBTraceID = "fake" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	writeJSON(w, status, resp)
+}
+
+// sign returns the X-Hub-Signature-256 value for a webhook body
+func (s *Server) sign(body []byte) string {
+	return utils.ComputeHMAC(body, []byte(s.opts.AppSecret))
+}
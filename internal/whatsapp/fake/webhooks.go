@@ -0,0 +1,133 @@
+package fake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+)
+
+// webhookValue mirrors the value of a messages webhook change
+type webhookValue struct {
+	MessagingProduct string                  `json:"messaging_product"`
+	Metadata         whatsapp.MetadataValue  `json:"metadata"`
+	Contacts         []whatsapp.ContactValue `json:"contacts,omitempty"`
+	Messages         []whatsapp.MessageValue `json:"messages,omitempty"`
+	Statuses         []whatsapp.StatusValue  `json:"statuses,omitempty"`
+}
+
+// EmitMessage delivers an inbound message webhook from a contact. The
+// message ID and timestamp are filled in when empty.
+func (s *Server) EmitMessage(ctx context.Context, contactName string, message whatsapp.MessageValue) error {
+	if message.ID == "" {
+		s.mu.Lock()
+		message.ID = "wamid." + s.nextID("IN")
+		s.mu.Unlock()
+	}
+	if message.Timestamp == "" {
+		message.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	contact := whatsapp.ContactValue{WaID: message.From}
+	contact.Profile.Name = contactName
+
+	return s.emit(ctx, webhookValue{
+		Contacts: []whatsapp.ContactValue{contact},
+		Messages: []whatsapp.MessageValue{message},
+	})
+}
+
+// EmitText delivers an inbound text message webhook and returns its message ID
+func (s *Server) EmitText(ctx context.Context, from, contactName, text string) (string, error) {
+	message := whatsapp.MessageValue{From: from, Type: "text"}
+	message.Text = &struct {
+		Body string `json:"body"`
+	}{Body: text}
+
+	s.mu.Lock()
+	message.ID = "wamid." + s.nextID("IN")
+	s.mu.Unlock()
+
+	return message.ID, s.EmitMessage(ctx, contactName, message)
+}
+
+// EmitStatus delivers a status webhook for a sent message
+func (s *Server) EmitStatus(ctx context.Context, messageID, recipientID, status string) error {
+	return s.EmitStatusValue(ctx, whatsapp.StatusValue{
+		ID:          messageID,
+		Status:      status,
+		RecipientID: recipientID,
+	})
+}
+
+// EmitStatusValue delivers a status webhook, e.g. a failed status with errors.
+// The timestamp is filled in when empty.
+func (s *Server) EmitStatusValue(ctx context.Context, status whatsapp.StatusValue) error {
+	if status.Timestamp == "" {
+		status.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	return s.emit(ctx, webhookValue{Statuses: []whatsapp.StatusValue{status}})
+}
+
+// emit wraps a change value in a webhook envelope for the business account
+// and posts it, signed, to the webhook URL
+func (s *Server) emit(ctx context.Context, value webhookValue) error {
+	value.MessagingProduct = "whatsapp"
+	value.Metadata = whatsapp.MetadataValue{
+		DisplayPhoneNumber: s.opts.DisplayPhoneNumber,
+		PhoneNumberID:      s.opts.PhoneNumberID,
+	}
+
+	payload := map[string]interface{}{
+		"object": "whatsapp_business_account",
+		"entry": []map[string]interface{}{{
+			"id": s.opts.BusinessAccountID,
+			"changes": []map[string]interface{}{{
+				"value": value,
+				"field": "messages",
+			}},
+		}},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return s.PostWebhook(ctx, body)
+}
+
+// PostWebhook posts a raw webhook body to the webhook URL, signed with the
+// app secret when one is configured
+func (s *Server) PostWebhook(ctx context.Context, body []byte) error {
+	s.mu.Lock()
+	webhookURL := s.opts.WebhookURL
+	s.mu.Unlock()
+	if webhookURL == "" {
+		return fmt.Errorf("fake: no webhook URL configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.opts.AppSecret != "" {
+		req.Header.Set("X-Hub-Signature-256", s.sign(body))
+	}
+
+	resp, err := s.webhookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fake: deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("fake: webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
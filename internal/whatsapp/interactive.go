@@ -0,0 +1,368 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// InteractiveType represents the type of an interactive message
+type InteractiveType string
+
+const (
+	InteractiveTypeButton InteractiveType = "button"
+	InteractiveTypeList   InteractiveType = "list"
+	InteractiveTypeCTAURL InteractiveType = "cta_url"
+	InteractiveTypeFlow   InteractiveType = "flow"
+
+	InteractiveTypeProduct     InteractiveType = "product"
+	InteractiveTypeProductList InteractiveType = "product_list"
+	InteractiveTypeCatalog     InteractiveType = "catalog_message"
+)
+
+// Flow actions: navigate opens a screen with initial data, data_exchange asks the
+// flow's endpoint for the first screen
+const (
+	FlowActionNavigate     = "navigate"
+	FlowActionDataExchange = "data_exchange"
+)
+
+// FlowMessageVersion is the flow message version sent when none is given
+const FlowMessageVersion = "3"
+
+// Limits imposed by the WhatsApp Cloud API on interactive messages
+const (
+	MaxReplyButtons         = 3
+	MaxListRows             = 10
+	MaxListSections         = 10
+	MaxButtonTitleLength    = 20
+	MaxButtonIDLength       = 256
+	MaxRowTitleLength       = 24
+	MaxRowDescriptionLength = 72
+	MaxRowIDLength          = 200
+	MaxSectionTitleLength   = 24
+	MaxHeaderTextLength     = 60
+	MaxBodyTextLength       = 1024
+	MaxFooterTextLength     = 60
+	MaxActionButtonLength   = 20
+	MaxCTADisplayTextLength = 20
+	MaxFlowCTALength        = 30
+)
+
+// Interactive represents the interactive object of an outbound message
+type Interactive struct {
+	Type   InteractiveType    `json:"type"`
+	Header *InteractiveHeader `json:"header,omitempty"`
+	Body   *InteractiveText   `json:"body,omitempty"`
+	Footer *InteractiveText   `json:"footer,omitempty"`
+	Action InteractiveAction  `json:"action"`
+}
+
+// InteractiveHeader represents the optional header of an interactive message
+type InteractiveHeader struct {
+	Type     string       `json:"type"` // text, image, video, document
+	Text     string       `json:"text,omitempty"`
+	Image    *MediaObject `json:"image,omitempty"`
+	Video    *MediaObject `json:"video,omitempty"`
+	Document *MediaObject `json:"document,omitempty"`
+}
+
+// InteractiveText represents a body or footer text block
+type InteractiveText struct {
+	Text string `json:"text"`
+}
+
+// InteractiveAction represents the action block of an interactive message
+type InteractiveAction struct {
+	Buttons    []ReplyButton          `json:"buttons,omitempty"`
+	Button     string                 `json:"button,omitempty"`
+	Sections   []ListSection          `json:"sections,omitempty"`
+	Name       string                 `json:"name,omitempty"`
+	Parameters *InteractiveParameters `json:"parameters,omitempty"`
+
+	// Commerce messages reference products of a catalog
+	CatalogID         string `json:"catalog_id,omitempty"`
+	ProductRetailerID string `json:"product_retailer_id,omitempty"`
+}
+
+// ReplyButton represents a quick reply button
+type ReplyButton struct {
+	Type  string `json:"type"` // always "reply"
+	Reply struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	} `json:"reply"`
+}
+
+// ListSection represents a section of a list message
+type ListSection struct {
+	Title        string        `json:"title,omitempty"`
+	Rows         []ListRow     `json:"rows,omitempty"`
+	ProductItems []ProductItem `json:"product_items,omitempty"` // product_list messages only
+}
+
+// ListRow represents a selectable row of a list message
+type ListRow struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// InteractiveParameters holds the parameters of a named action
+type InteractiveParameters struct {
+	DisplayText string `json:"display_text,omitempty"`
+	URL         string `json:"url,omitempty"`
+
+	// Flow parameters
+	FlowMessageVersion string             `json:"flow_message_version,omitempty"`
+	FlowToken          string             `json:"flow_token,omitempty"`
+	FlowID             string             `json:"flow_id,omitempty"`
+	FlowName           string             `json:"flow_name,omitempty"`
+	FlowCTA            string             `json:"flow_cta,omitempty"`
+	FlowAction         string             `json:"flow_action,omitempty"` // navigate or data_exchange
+	FlowActionPayload  *FlowActionPayload `json:"flow_action_payload,omitempty"`
+	Mode               string             `json:"mode,omitempty"` // draft or published
+
+	// Catalog parameters
+	ThumbnailProductRetailerID string `json:"thumbnail_product_retailer_id,omitempty"`
+}
+
+// FlowActionPayload selects the first screen of a flow and the data it opens with
+type FlowActionPayload struct {
+	Screen string                 `json:"screen"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+}
+
+// MediaObject represents a media reference in a message payload
+type MediaObject struct {
+	ID       string `json:"id,omitempty"`
+	Link     string `json:"link,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// Validate checks the interactive message against the Cloud API limits
+func (i *Interactive) Validate() error {
+	// Single product messages are the only ones where the body is optional
+	if i.Body == nil || i.Body.Text == "" {
+		if i.Type != InteractiveTypeProduct {
+			return errors.New("interactive body text is required")
+		}
+	} else if err := checkLength(i.Body.Text, "body text", MaxBodyTextLength); err != nil {
+		return err
+	}
+	if i.Footer != nil {
+		if err := checkLength(i.Footer.Text, "footer text", MaxFooterTextLength); err != nil {
+			return err
+		}
+	}
+	if i.Header != nil {
+		if err := i.Header.validate(); err != nil {
+			return err
+		}
+	}
+
+	switch i.Type {
+	case InteractiveTypeButton:
+		return i.validateButtons()
+	case InteractiveTypeList:
+		return i.validateList()
+	case InteractiveTypeCTAURL:
+		return i.validateCTAURL()
+	case InteractiveTypeFlow:
+		return i.validateFlow()
+	case InteractiveTypeProduct:
+		return i.validateProduct()
+	case InteractiveTypeProductList:
+		return i.validateProductList()
+	case InteractiveTypeCatalog:
+		return i.validateCatalog()
+	default:
+		return fmt.Errorf("unsupported interactive type: %s", i.Type)
+	}
+}
+
+func (h *InteractiveHeader) validate() error {
+	switch h.Type {
+	case "text":
+		if h.Text == "" {
+			return errors.New("header text is required for text headers")
+		}
+		return checkLength(h.Text, "header text", MaxHeaderTextLength)
+	case "image":
+		return validateHeaderMedia(h.Image, h.Type)
+	case "video":
+		return validateHeaderMedia(h.Video, h.Type)
+	case "document":
+		return validateHeaderMedia(h.Document, h.Type)
+	default:
+		return fmt.Errorf("unsupported header type: %s", h.Type)
+	}
+}
+
+func validateHeaderMedia(media *MediaObject, headerType string) error {
+	if media == nil || (media.ID == "" && media.Link == "") {
+		return fmt.Errorf("%s header requires a media id or link", headerType)
+	}
+	return nil
+}
+
+func (i *Interactive) validateButtons() error {
+	buttons := i.Action.Buttons
+	if len(buttons) == 0 {
+		return errors.New("at least one reply button is required")
+	}
+	if len(buttons) > MaxReplyButtons {
+		return fmt.Errorf("at most %d reply buttons are allowed, got %d", MaxReplyButtons, len(buttons))
+	}
+
+	seen := make(map[string]bool, len(buttons))
+	for idx := range buttons {
+		button := &buttons[idx]
+		if button.Type == "" {
+			button.Type = "reply"
+		}
+		if button.Type != "reply" {
+			return fmt.Errorf("button %d: unsupported button type: %s", idx+1, button.Type)
+		}
+		if button.Reply.ID == "" || button.Reply.Title == "" {
+			return fmt.Errorf("button %d: id and title are required", idx+1)
+		}
+		if err := checkLength(button.Reply.ID, "button id", MaxButtonIDLength); err != nil {
+			return err
+		}
+		if err := checkLength(button.Reply.Title, "button title", MaxButtonTitleLength); err != nil {
+			return err
+		}
+		if seen[button.Reply.ID] {
+			return fmt.Errorf("duplicate button id: %s", button.Reply.ID)
+		}
+		seen[button.Reply.ID] = true
+	}
+	return nil
+}
+
+func (i *Interactive) validateList() error {
+	if i.Action.Button == "" {
+		return errors.New("list button text is required")
+	}
+	if err := checkLength(i.Action.Button, "list button text", MaxActionButtonLength); err != nil {
+		return err
+	}
+	if i.Header != nil && i.Header.Type != "text" {
+		return errors.New("list messages only support text headers")
+	}
+
+	sections := i.Action.Sections
+	if len(sections) == 0 {
+		return errors.New("at least one list section is required")
+	}
+	if len(sections) > MaxListSections {
+		return fmt.Errorf("at most %d list sections are allowed, got %d", MaxListSections, len(sections))
+	}
+
+	totalRows := 0
+	seen := make(map[string]bool)
+	for sIdx, section := range sections {
+		if len(sections) > 1 && section.Title == "" {
+			return fmt.Errorf("section %d: title is required when there are multiple sections", sIdx+1)
+		}
+		if err := checkLength(section.Title, "section title", MaxSectionTitleLength); err != nil {
+			return err
+		}
+		if len(section.Rows) == 0 {
+			return fmt.Errorf("section %d: at least one row is required", sIdx+1)
+		}
+		for rIdx, row := range section.Rows {
+			if row.ID == "" || row.Title == "" {
+				return fmt.Errorf("section %d row %d: id and title are required", sIdx+1, rIdx+1)
+			}
+			if err := checkLength(row.ID, "row id", MaxRowIDLength); err != nil {
+				return err
+			}
+			if err := checkLength(row.Title, "row title", MaxRowTitleLength); err != nil {
+				return err
+			}
+			if err := checkLength(row.Description, "row description", MaxRowDescriptionLength); err != nil {
+				return err
+			}
+			if seen[row.ID] {
+				return fmt.Errorf("duplicate row id: %s", row.ID)
+			}
+			seen[row.ID] = true
+		}
+		totalRows += len(section.Rows)
+	}
+	if totalRows > MaxListRows {
+		return fmt.Errorf("at most %d list rows are allowed, got %d", MaxListRows, totalRows)
+	}
+	return nil
+}
+
+func (i *Interactive) validateCTAURL() error {
+	if i.Action.Name == "" {
+		i.Action.Name = string(InteractiveTypeCTAURL)
+	}
+	if i.Action.Name != string(InteractiveTypeCTAURL) {
+		return fmt.Errorf("unsupported action name for cta_url: %s", i.Action.Name)
+	}
+	params := i.Action.Parameters
+	if params == nil || params.DisplayText == "" || params.URL == "" {
+		return errors.New("cta_url requires display_text and url parameters")
+	}
+	return checkLength(params.DisplayText, "display text", MaxCTADisplayTextLength)
+}
+
+func (i *Interactive) validateFlow() error {
+	if i.Action.Name == "" {
+		i.Action.Name = string(InteractiveTypeFlow)
+	}
+	if i.Action.Name != string(InteractiveTypeFlow) {
+		return fmt.Errorf("unsupported action name for flow: %s", i.Action.Name)
+	}
+	if i.Header != nil && i.Header.Type != "text" {
+		return errors.New("flow messages only support text headers")
+	}
+
+	params := i.Action.Parameters
+	if params == nil {
+		return errors.New("flow requires action parameters")
+	}
+	if (params.FlowID == "") == (params.FlowName == "") {
+		return errors.New("flow requires exactly one of flow_id or flow_name")
+	}
+	if params.FlowCTA == "" {
+		return errors.New("flow requires flow_cta")
+	}
+	if err := checkLength(params.FlowCTA, "flow cta", MaxFlowCTALength); err != nil {
+		return err
+	}
+	if params.FlowMessageVersion == "" {
+		params.FlowMessageVersion = FlowMessageVersion
+	}
+	if params.Mode != "" && params.Mode != "draft" && params.Mode != "published" {
+		return fmt.Errorf("unsupported flow mode: %s", params.Mode)
+	}
+
+	switch params.FlowAction {
+	case "", FlowActionNavigate:
+		if params.FlowActionPayload == nil || params.FlowActionPayload.Screen == "" {
+			return errors.New("navigate flows require flow_action_payload.screen")
+		}
+	case FlowActionDataExchange:
+		if params.FlowActionPayload != nil {
+			return errors.New("data_exchange flows do not take a flow_action_payload")
+		}
+	default:
+		return fmt.Errorf("unsupported flow action: %s", params.FlowAction)
+	}
+	return nil
+}
+
+// checkLength validates the length of a field in characters
+func checkLength(value, field string, max int) error {
+	if utf8.RuneCountInString(value) > max {
+		return fmt.Errorf("%s exceeds maximum length of %d characters", field, max)
+	}
+	return nil
+}
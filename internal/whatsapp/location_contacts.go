@@ -0,0 +1,131 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validate checks that the location's coordinates are in range. (0, 0) is a
+// valid coordinate, so presence is checked when the request is bound.
+func (l *Location) Validate() error {
+	if l.Latitude < -90 || l.Latitude > 90 {
+		return fmt.Errorf("invalid latitude: %f", l.Latitude)
+	}
+	if l.Longitude < -180 || l.Longitude > 180 {
+		return fmt.Errorf("invalid longitude: %f", l.Longitude)
+	}
+	return nil
+}
+
+// Validate checks that the contact card has the fields required by the Cloud API
+func (c *ContactCard) Validate() error {
+	if c.Name.FormattedName == "" {
+		return errors.New("contact formatted_name is required")
+	}
+	if c.Name.FirstName == "" && c.Name.LastName == "" && c.Name.MiddleName == "" &&
+		c.Name.Prefix == "" && c.Name.Suffix == "" {
+		return errors.New("contact requires at least one of first_name, last_name, middle_name, prefix or suffix")
+	}
+	for i, phone := range c.Phones {
+		if phone.Phone == "" {
+			return fmt.Errorf("contact phone %d: phone is required", i+1)
+		}
+	}
+	return nil
+}
+
+// VCard renders the contact card as a vCard 3.0 document
+func (c *ContactCard) VCard() string {
+	var b strings.Builder
+
+	b.WriteString("BEGIN:VCARD\r\n")
+	b.WriteString("VERSION:3.0\r\n")
+	writeVCardLine(&b, "FN", c.Name.FormattedName)
+	writeVCardRaw(&b, "N", strings.Join([]string{
+		escapeVCard(c.Name.LastName),
+		escapeVCard(c.Name.FirstName),
+		escapeVCard(c.Name.MiddleName),
+		escapeVCard(c.Name.Prefix),
+		escapeVCard(c.Name.Suffix),
+	}, ";"))
+
+	if c.Org != nil && (c.Org.Company != "" || c.Org.Department != "") {
+		writeVCardRaw(&b, "ORG", escapeVCard(c.Org.Company)+";"+escapeVCard(c.Org.Department))
+	}
+	if c.Org != nil && c.Org.Title != "" {
+		writeVCardLine(&b, "TITLE", c.Org.Title)
+	}
+
+	for _, phone := range c.Phones {
+		name := "TEL"
+		if phone.Type != "" {
+			name += ";TYPE=" + strings.ToUpper(phone.Type)
+		}
+		if phone.WaID != "" {
+			name += ";waid=" + phone.WaID
+		}
+		writeVCardLine(&b, name, phone.Phone)
+	}
+	for _, email := range c.Emails {
+		name := "EMAIL"
+		if email.Type != "" {
+			name += ";TYPE=" + strings.ToUpper(email.Type)
+		}
+		writeVCardLine(&b, name, email.Email)
+	}
+	for _, url := range c.URLs {
+		name := "URL"
+		if url.Type != "" {
+			name += ";TYPE=" + strings.ToUpper(url.Type)
+		}
+		writeVCardLine(&b, name, url.URL)
+	}
+	for _, addr := range c.Addresses {
+		name := "ADR"
+		if addr.Type != "" {
+			name += ";TYPE=" + strings.ToUpper(addr.Type)
+		}
+		writeVCardRaw(&b, name, strings.Join([]string{
+			"", "",
+			escapeVCard(addr.Street),
+			escapeVCard(addr.City),
+			escapeVCard(addr.State),
+			escapeVCard(addr.Zip),
+			escapeVCard(addr.Country),
+		}, ";"))
+	}
+	if c.Birthday != "" {
+		writeVCardLine(&b, "BDAY", c.Birthday)
+	}
+
+	b.WriteString("END:VCARD\r\n")
+	return b.String()
+}
+
+// writeVCardLine writes a single vCard property, escaping the value
+func writeVCardLine(b *strings.Builder, name, value string) {
+	writeVCardRaw(b, name, escapeVCard(value))
+}
+
+// writeVCardRaw writes a single vCard property whose value is already escaped
+func writeVCardRaw(b *strings.Builder, name, value string) {
+	if strings.Trim(value, ";") == "" {
+		return
+	}
+	b.WriteString(name)
+	b.WriteString(":")
+	b.WriteString(value)
+	b.WriteString("\r\n")
+}
+
+// escapeVCard escapes special characters in a vCard value
+func escapeVCard(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		"\n", `\n`,
+		",", `\,`,
+		";", `\;`,
+	)
+	return replacer.Replace(value)
+}
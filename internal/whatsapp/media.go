@@ -0,0 +1,163 @@
+package whatsapp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Maximum media sizes accepted by WhatsApp, in bytes
+const (
+	MaxImageSize    int64 = 5 << 20
+	MaxVideoSize    int64 = 16 << 20
+	MaxAudioSize    int64 = 16 << 20
+	MaxDocumentSize int64 = 100 << 20
+)
+
+// MediaTypeForMime returns the WhatsApp media type used to send a file of the given MIME type
+func MediaTypeForMime(mimeType string) MediaType {
+	switch {
+	case mimeType == "image/jpeg" || mimeType == "image/png":
+		return MediaTypeImage
+	case strings.HasPrefix(mimeType, "video/"):
+		return MediaTypeVideo
+	case strings.HasPrefix(mimeType, "audio/"):
+		return MediaTypeAudio
+	default:
+		return MediaTypeDocument
+	}
+}
+
+// MaxMediaSize returns the maximum size WhatsApp accepts for a media type
+func MaxMediaSize(mediaType MediaType) int64 {
+	switch mediaType {
+	case MediaTypeImage:
+		return MaxImageSize
+	case MediaTypeVideo:
+		return MaxVideoSize
+	case MediaTypeAudio:
+		return MaxAudioSize
+	default:
+		return MaxDocumentSize
+	}
+}
+
+// UploadMedia uploads a file to WhatsApp and returns the media ID to reference in messages
+func (c *Client) UploadMedia(ctx context.Context, filename, mimeType string, r io.Reader) (string, error) {
+	endpoint := fmt.Sprintf("/%s/media", c.phoneNumberID)
+
+	ctx, cancel := context.WithTimeout(ctx, c.mediaTimeout)
+	defer cancel()
+
+	// The multipart body cannot be replayed, so uploads are not retried on API errors
+	resp, err := c.httpClient.R().SetContext(ctx).
+		SetMultipartField("file", filename, mimeType, r).
+		SetFormData(map[string]string{
+			"messaging_product": "whatsapp",
+			"type":              mimeType,
+		}).
+		Post(endpoint)
+
+	if err != nil {
+		c.logger.Error("Failed to upload media", zap.Error(err))
+		return "", errors.NewMediaUploadError(err)
+	}
+
+	if resp.IsError() {
+		return "", c.parseError(resp)
+	}
+
+	var uploadResp MediaUploadResponse
+	if err := json.Unmarshal(resp.Body(), &uploadResp); err != nil {
+		return "", errors.NewInternalError(err)
+	}
+	if uploadResp.ID == "" {
+		return "", errors.NewMediaUploadError(fmt.Errorf("response did not include a media ID"))
+	}
+
+	c.logger.Info("Media uploaded successfully",
+		zap.String("media_id", uploadResp.ID),
+		zap.String("mime_type", mimeType),
+	)
+
+	return uploadResp.ID, nil
+}
+
+// GetMediaURL resolves a media ID to a short-lived download URL
+func (c *Client) GetMediaURL(ctx context.Context, mediaID string) (*MediaURLResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.request(ctx).Get(fmt.Sprintf("/%s", mediaID))
+	if err != nil {
+		c.logger.Error("Failed to resolve media URL", zap.Error(err), zap.String("media_id", mediaID))
+		return nil, errors.NewWhatsAppError(err)
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	var mediaResp MediaURLResponse
+	if err := json.Unmarshal(resp.Body(), &mediaResp); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	if mediaResp.URL == "" {
+		return nil, errors.NewWhatsAppError(fmt.Errorf("response did not include a media URL"))
+	}
+
+	return &mediaResp, nil
+}
+
+// DownloadMedia downloads media content from a URL returned by GetMediaURL.
+// The URL requires the same bearer token as the Graph API.
+func (c *Client) DownloadMedia(ctx context.Context, url string, maxSize int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.mediaTimeout)
+	defer cancel()
+
+	resp, err := c.httpClient.R().SetContext(ctx).
+		SetDoNotParseResponse(true).
+		Get(url)
+	if err != nil {
+		c.logger.Error("Failed to download media", zap.Error(err))
+		return nil, errors.NewWhatsAppError(err)
+	}
+
+	body := resp.RawBody()
+	defer body.Close()
+
+	if resp.IsError() {
+		// The body is not buffered, so the error is classified by status alone
+		return nil, newAPIError(resp).AppError()
+	}
+
+	// Read one extra byte to detect content over the limit
+	data, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, errors.NewWhatsAppError(err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, errors.NewWhatsAppError(fmt.Errorf("media exceeds maximum size of %d bytes", maxSize))
+	}
+
+	return data, nil
+}
+
+// VerifySHA256 checks data against a SHA256 digest as sent by WhatsApp,
+// which may be hex or base64 encoded depending on the endpoint
+func VerifySHA256(data []byte, expected string) bool {
+	sum := sha256.Sum256(data)
+	if strings.EqualFold(hex.EncodeToString(sum[:]), expected) {
+		return true
+	}
+	return base64.StdEncoding.EncodeToString(sum[:]) == expected ||
+		base64.RawStdEncoding.EncodeToString(sum[:]) == expected
+}
@@ -1,6 +1,9 @@
 package whatsapp
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // MediaType represents the type of media
 type MediaType string
@@ -37,6 +40,26 @@ type MessageResponse struct {
 	} `json:"messages"`
 }
 
+// SuccessResponse represents a plain success acknowledgement from WhatsApp API
+type SuccessResponse struct {
+	Success bool `json:"success"`
+}
+
+// MediaURLResponse represents the response when resolving a media ID
+type MediaURLResponse struct {
+	MessagingProduct string `json:"messaging_product"`
+	URL              string `json:"url"`
+	MimeType         string `json:"mime_type"`
+	SHA256           string `json:"sha256"`
+	FileSize         int64  `json:"file_size"`
+	ID               string `json:"id"`
+}
+
+// MediaUploadResponse represents the response to a media upload
+type MediaUploadResponse struct {
+	ID string `json:"id"`
+}
+
 // MessageStatus represents the status of a message
 type MessageStatus struct {
 	ID        string    `json:"id"`
@@ -46,20 +69,124 @@ type MessageStatus struct {
 
 // WebhookPayload represents incoming webhook data
 type WebhookPayload struct {
-	Object string `json:"object"`
-	Entry  []struct {
-		ID      string `json:"id"`
-		Changes []struct {
-			Value struct {
-				MessagingProduct string          `json:"messaging_product"`
-				Metadata         MetadataValue   `json:"metadata"`
-				Contacts         []ContactValue  `json:"contacts,omitempty"`
-				Messages         []MessageValue  `json:"messages,omitempty"`
-				Statuses         []StatusValue   `json:"statuses,omitempty"`
-			} `json:"value"`
-			Field string `json:"field"`
-		} `json:"changes"`
-	} `json:"entry"`
+	Object string         `json:"object"`
+	Entry  []WebhookEntry `json:"entry"`
+}
+
+// Webhook fields WhatsApp reports changes for. Messages and statuses arrive on
+// the messages field; the others describe the business account.
+const (
+	WebhookFieldMessages                 = "messages"
+	WebhookFieldTemplateStatusUpdate     = "message_template_status_update"
+	WebhookFieldTemplateQualityUpdate    = "message_template_quality_update"
+	WebhookFieldPhoneNumberQualityUpdate = "phone_number_quality_update"
+	WebhookFieldPhoneNumberNameUpdate    = "phone_number_name_update"
+	WebhookFieldAccountUpdate            = "account_update"
+	WebhookFieldAccountAlerts            = "account_alerts"
+	WebhookFieldBusinessCapabilityUpdate = "business_capability_update"
+)
+
+// WebhookEntry holds the changes of one business account
+type WebhookEntry struct {
+	ID      string          `json:"id"` // business account ID
+	Time    int64           `json:"time,omitempty"`
+	Changes []WebhookChange `json:"changes"`
+}
+
+// WebhookChange is a change to one webhook field
+type WebhookChange struct {
+	Value ChangeValue `json:"value"`
+	Field string      `json:"field"`
+}
+
+// ChangeValue is the value of a change. Messages and statuses are decoded here;
+// Raw keeps the original JSON so account-level fields can be decoded by field.
+type ChangeValue struct {
+	MessagingProduct string         `json:"messaging_product"`
+	Metadata         MetadataValue  `json:"metadata"`
+	Contacts         []ContactValue `json:"contacts,omitempty"`
+	Messages         []MessageValue `json:"messages,omitempty"`
+	Statuses         []StatusValue  `json:"statuses,omitempty"`
+	Errors           []WebhookError `json:"errors,omitempty"` // errors not tied to a single message or status
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a change value and keeps a copy of its raw JSON
+func (v *ChangeValue) UnmarshalJSON(data []byte) error {
+	type changeValue ChangeValue
+	var value changeValue
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*v = ChangeValue(value)
+	v.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// TemplateStatusUpdate is the value of a message_template_status_update change
+type TemplateStatusUpdate struct {
+	Event                   string      `json:"event"` // APPROVED, REJECTED, PENDING, PAUSED, DISABLED, FLAGGED, REINSTATED, ...
+	MessageTemplateID       json.Number `json:"message_template_id"`
+	MessageTemplateName     string      `json:"message_template_name"`
+	MessageTemplateLanguage string      `json:"message_template_language"`
+	Reason                  string      `json:"reason,omitempty"` // rejection reason, NONE if there is none
+	OtherInfo               *struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	} `json:"other_info,omitempty"`
+	DisableInfo *struct {
+		DisableDate string `json:"disable_date"`
+	} `json:"disable_info,omitempty"`
+}
+
+// TemplateQualityUpdate is the value of a message_template_quality_update change
+type TemplateQualityUpdate struct {
+	PreviousQualityScore    string      `json:"previous_quality_score"` // GREEN, YELLOW, RED, UNKNOWN
+	NewQualityScore         string      `json:"new_quality_score"`
+	MessageTemplateID       json.Number `json:"message_template_id"`
+	MessageTemplateName     string      `json:"message_template_name"`
+	MessageTemplateLanguage string      `json:"message_template_language"`
+}
+
+// PhoneNumberQualityUpdate is the value of a phone_number_quality_update change
+type PhoneNumberQualityUpdate struct {
+	DisplayPhoneNumber string `json:"display_phone_number"`
+	Event              string `json:"event"`         // FLAGGED, UNFLAGGED, UPGRADE, DOWNGRADE, ONBOARDING
+	CurrentLimit       string `json:"current_limit"` // TIER_250, TIER_1K, ..., TIER_UNLIMITED
+	OldLimit           string `json:"old_limit,omitempty"`
+}
+
+// PhoneNumberNameUpdate is the value of a phone_number_name_update change
+type PhoneNumberNameUpdate struct {
+	DisplayPhoneNumber    string `json:"display_phone_number"`
+	Decision              string `json:"decision"` // APPROVED, REJECTED, DEFERRED
+	RequestedVerifiedName string `json:"requested_verified_name"`
+	RejectionReason       string `json:"rejection_reason,omitempty"`
+}
+
+// AccountUpdate is the value of an account_update change. Ban, violation and
+// restriction details vary by event and are kept in the event's Raw JSON.
+type AccountUpdate struct {
+	PhoneNumber string `json:"phone_number,omitempty"`
+	Event       string `json:"event"` // VERIFIED_ACCOUNT, DISABLED_UPDATE, ACCOUNT_VIOLATION, ACCOUNT_RESTRICTION, ...
+}
+
+// AccountAlert is the value of an account_alerts change
+type AccountAlert struct {
+	EntityType       string `json:"entity_type"`
+	EntityID         string `json:"entity_id"`
+	AlertSeverity    string `json:"alert_severity"` // CRITICAL, WARNING, INFORMATIONAL
+	AlertStatus      string `json:"alert_status"`
+	AlertType        string `json:"alert_type"`
+	AlertDescription string `json:"alert_description"`
+}
+
+// BusinessCapabilityUpdate is the value of a business_capability_update change
+type BusinessCapabilityUpdate struct {
+	MaxDailyConversationPerPhone int `json:"max_daily_conversation_per_phone,omitempty"`
+	MaxPhoneNumbersPerBusiness   int `json:"max_phone_numbers_per_business,omitempty"`
+	MaxPhoneNumbersPerWABA       int `json:"max_phone_numbers_per_waba,omitempty"`
 }
 
 // MetadataValue represents webhook metadata
@@ -76,7 +203,9 @@ type ContactValue struct {
 	WaID string `json:"wa_id"`
 }
 
-// MessageValue represents a message in webhook
+// MessageValue represents a message in webhook. Every documented inbound type
+// is modelled; Raw keeps the original JSON so types added by WhatsApp later are
+// not lost.
 type MessageValue struct {
 	From      string `json:"from"`
 	ID        string `json:"id"`
@@ -85,44 +214,227 @@ type MessageValue struct {
 	Text      *struct {
 		Body string `json:"body"`
 	} `json:"text,omitempty"`
-	Image *struct {
-		Caption  string `json:"caption,omitempty"`
-		MimeType string `json:"mime_type"`
-		SHA256   string `json:"sha256"`
-		ID       string `json:"id"`
-	} `json:"image,omitempty"`
-	Document *struct {
-		Caption  string `json:"caption,omitempty"`
-		Filename string `json:"filename,omitempty"`
-		MimeType string `json:"mime_type"`
-		SHA256   string `json:"sha256"`
-		ID       string `json:"id"`
-	} `json:"document,omitempty"`
-	Audio *struct {
-		MimeType string `json:"mime_type"`
-		SHA256   string `json:"sha256"`
-		ID       string `json:"id"`
-		Voice    bool   `json:"voice"`
-	} `json:"audio,omitempty"`
-	Video *struct {
-		Caption  string `json:"caption,omitempty"`
-		MimeType string `json:"mime_type"`
-		SHA256   string `json:"sha256"`
-		ID       string `json:"id"`
-	} `json:"video,omitempty"`
+	Image       *MediaValue         `json:"image,omitempty"`
+	Document    *MediaValue         `json:"document,omitempty"`
+	Audio       *MediaValue         `json:"audio,omitempty"`
+	Video       *MediaValue         `json:"video,omitempty"`
+	Sticker     *MediaValue         `json:"sticker,omitempty"`
+	Location    *Location           `json:"location,omitempty"`
+	Contacts    []ContactCard       `json:"contacts,omitempty"`
+	Reaction    *Reaction           `json:"reaction,omitempty"`
+	Interactive *InboundInteractive `json:"interactive,omitempty"`
+	Button      *ButtonValue        `json:"button,omitempty"`
+	Order       *Order              `json:"order,omitempty"`
+	System      *SystemValue        `json:"system,omitempty"`
+	Referral    *Referral           `json:"referral,omitempty"`
+	Errors      []WebhookError      `json:"errors,omitempty"`
+	Context     *MessageContext     `json:"context,omitempty"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a message and keeps a copy of its raw JSON
+func (m *MessageValue) UnmarshalJSON(data []byte) error {
+	type messageValue MessageValue
+	var value messageValue
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*m = MessageValue(value)
+	m.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MediaValue represents the media object of an image, document, audio, video or sticker message
+type MediaValue struct {
+	ID       string `json:"id"`
+	MimeType string `json:"mime_type"`
+	SHA256   string `json:"sha256"`
+	Caption  string `json:"caption,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Voice    bool   `json:"voice,omitempty"`    // audio recorded as a voice note
+	Animated bool   `json:"animated,omitempty"` // animated sticker
+}
+
+// InboundInteractive represents the reply to an interactive message
+type InboundInteractive struct {
+	Type        string            `json:"type"` // button_reply, list_reply, or nfm_reply for flow responses
+	ButtonReply *InteractiveReply `json:"button_reply,omitempty"`
+	ListReply   *InteractiveReply `json:"list_reply,omitempty"`
+	NFMReply    *NFMReply         `json:"nfm_reply,omitempty"`
+}
+
+// InteractiveReply is the button or list row a user picked
+type InteractiveReply struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// ButtonValue is the quick reply button a user tapped on a template message
+type ButtonValue struct {
+	Payload string `json:"payload"`
+	Text    string `json:"text"`
+}
+
+// SystemValue is a notice about the customer, such as a change of phone number
+type SystemValue struct {
+	Body     string `json:"body"`
+	Type     string `json:"type"` // user_changed_number, customer_identity_changed
+	Identity string `json:"identity,omitempty"`
+	WaID     string `json:"wa_id,omitempty"`
+	NewWaID  string `json:"new_wa_id,omitempty"`
+	Customer string `json:"customer,omitempty"`
+}
+
+// Referral describes the click-to-WhatsApp ad or post a conversation started from
+type Referral struct {
+	SourceURL    string `json:"source_url"`
+	SourceID     string `json:"source_id"`
+	SourceType   string `json:"source_type"` // ad or post
+	Headline     string `json:"headline,omitempty"`
+	Body         string `json:"body,omitempty"`
+	MediaType    string `json:"media_type,omitempty"` // image or video
+	ImageURL     string `json:"image_url,omitempty"`
+	VideoURL     string `json:"video_url,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	CtwaClid     string `json:"ctwa_clid,omitempty"` // click ID for conversion reporting
+}
+
+// WebhookError describes why WhatsApp could not deliver a message, such as
+// a message type the Cloud API does not support
+type WebhookError struct {
+	Code      int       `json:"code"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message,omitempty"`
+	ErrorData ErrorData `json:"error_data,omitempty"`
+}
+
+// NFMReply carries the data submitted when a user completes a flow
+type NFMReply struct {
+	Name         string `json:"name"`
+	Body         string `json:"body"`
+	ResponseJSON string `json:"response_json"`
+}
+
+// FlowReply is a parsed flow response. If response_json cannot be decoded,
+// Response is empty and ResponseJSON and ParseError keep what was received.
+type FlowReply struct {
+	Name         string
+	Body         string
+	FlowToken    string
+	Response     map[string]interface{}
+	ResponseJSON string
+	ParseError   string
+}
+
+// Reaction represents an emoji reaction to a message
+type Reaction struct {
+	MessageID string `json:"message_id"`
+	Emoji     string `json:"emoji,omitempty"`
+}
+
+// MessageContext references the message an inbound message replies to
+type MessageContext struct {
+	From                string `json:"from,omitempty"`
+	ID                  string `json:"id,omitempty"`
+	Forwarded           bool   `json:"forwarded,omitempty"`
+	FrequentlyForwarded bool   `json:"frequently_forwarded,omitempty"`
+}
+
+// Location represents a location shared in a message
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+	URL       string  `json:"url,omitempty"`
+}
+
+// ContactCard represents a contact card shared in a message
+type ContactCard struct {
+	Name      ContactName      `json:"name"`
+	Phones    []ContactPhone   `json:"phones,omitempty"`
+	Emails    []ContactEmail   `json:"emails,omitempty"`
+	URLs      []ContactURL     `json:"urls,omitempty"`
+	Addresses []ContactAddress `json:"addresses,omitempty"`
+	Org       *ContactOrg      `json:"org,omitempty"`
+	Birthday  string           `json:"birthday,omitempty"` // YYYY-MM-DD
+}
+
+// ContactName represents the name of a shared contact
+type ContactName struct {
+	FormattedName string `json:"formatted_name"`
+	FirstName     string `json:"first_name,omitempty"`
+	LastName      string `json:"last_name,omitempty"`
+	MiddleName    string `json:"middle_name,omitempty"`
+	Prefix        string `json:"prefix,omitempty"`
+	Suffix        string `json:"suffix,omitempty"`
+}
+
+// ContactPhone represents a phone number of a shared contact
+type ContactPhone struct {
+	Phone string `json:"phone"`
+	WaID  string `json:"wa_id,omitempty"`
+	Type  string `json:"type,omitempty"` // CELL, MAIN, IPHONE, HOME, WORK
+}
+
+// ContactEmail represents an email address of a shared contact
+type ContactEmail struct {
+	Email string `json:"email"`
+	Type  string `json:"type,omitempty"` // HOME, WORK
+}
+
+// ContactURL represents a website of a shared contact
+type ContactURL struct {
+	URL  string `json:"url"`
+	Type string `json:"type,omitempty"` // HOME, WORK
+}
+
+// ContactAddress represents a postal address of a shared contact
+type ContactAddress struct {
+	Street      string `json:"street,omitempty"`
+	City        string `json:"city,omitempty"`
+	State       string `json:"state,omitempty"`
+	Zip         string `json:"zip,omitempty"`
+	Country     string `json:"country,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
+	Type        string `json:"type,omitempty"` // HOME, WORK
+}
+
+// ContactOrg represents the organization of a shared contact
+type ContactOrg struct {
+	Company    string `json:"company,omitempty"`
+	Department string `json:"department,omitempty"`
+	Title      string `json:"title,omitempty"`
 }
 
 // StatusValue represents a status update in webhook
 type StatusValue struct {
-	ID          string `json:"id"`
-	Status      string `json:"status"`
-	Timestamp   string `json:"timestamp"`
-	RecipientID string `json:"recipient_id"`
-	Errors      []struct {
-		Code    int    `json:"code"`
-		Title   string `json:"title"`
-		Message string `json:"message,omitempty"`
-	} `json:"errors,omitempty"`
+	ID           string             `json:"id"`
+	Status       string             `json:"status"`
+	Timestamp    string             `json:"timestamp"`
+	RecipientID  string             `json:"recipient_id"`
+	Conversation *ConversationValue `json:"conversation,omitempty"`
+	Pricing      *PricingValue      `json:"pricing,omitempty"`
+	Errors       []WebhookError     `json:"errors,omitempty"`
+}
+
+// ConversationValue is the conversation a status update was charged to
+type ConversationValue struct {
+	ID                  string `json:"id"`
+	ExpirationTimestamp string `json:"expiration_timestamp,omitempty"` // sent with the first status of a conversation
+	Origin              struct {
+		Type string `json:"type"` // marketing, utility, authentication, service, referral_conversion
+	} `json:"origin"`
+}
+
+// PricingValue describes how a message is charged
+type PricingValue struct {
+	Billable     bool   `json:"billable"`
+	PricingModel string `json:"pricing_model"` // CBP or PMP
+	Category     string `json:"category"`
+	Type         string `json:"type,omitempty"` // regular, free_customer_service, free_entry_point
 }
 
 // MessageEvent represents a parsed incoming message event
@@ -135,30 +447,111 @@ type MessageEvent struct {
 	MediaID     string
 	MediaURL    string
 	MimeType    string
+	SHA256      string
 	Caption     string
 	Filename    string
 	ContactName string
+	Voice       bool // audio recorded as a voice note
+	Animated    bool // animated sticker
+	Location    *Location
+	Contacts    []ContactCard
+	Reaction    *Reaction
+	Reply       *Reply // button, list or template quick reply
+	FlowReply   *FlowReply
+	Order       *Order
+	System      *SystemValue
+	Referral    *Referral
+	Errors      []WebhookError
+	ReplyToID   string // WhatsApp ID of the quoted message
+	Forwarded   bool
+
+	// Raw is the original message JSON, kept for types we do not model
+	Raw json.RawMessage
+
+	// Business phone number that received the message, from the webhook metadata
+	PhoneNumberID      string
+	DisplayPhoneNumber string
+}
+
+// Reply kinds
+const (
+	ReplyTypeButton     = "button_reply" // reply button of an interactive message
+	ReplyTypeList       = "list_reply"   // row of an interactive list
+	ReplyTypeQuickReply = "quick_reply"  // quick reply button of a template
+)
+
+// Reply is the option a user picked in reply to an interactive or template message.
+// For template quick replies, ID is the button payload.
+type Reply struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// AccountEvent represents a parsed change to an account-level webhook field.
+// Exactly one of the typed values is set for a known field, unless its value
+// could not be decoded, in which case DecodeError says why; Raw always holds
+// the original value.
+type AccountEvent struct {
+	Field             string
+	BusinessAccountID string
+	Timestamp         time.Time // when WhatsApp sent the change, zero if unknown
+
+	TemplateStatus     *TemplateStatusUpdate
+	TemplateQuality    *TemplateQualityUpdate
+	PhoneNumberQuality *PhoneNumberQualityUpdate
+	PhoneNumberName    *PhoneNumberNameUpdate
+	Account            *AccountUpdate
+	Alert              *AccountAlert
+	Capability         *BusinessCapabilityUpdate
+
+	Raw         json.RawMessage
+	DecodeError string
+}
+
+// ChangeErrorEvent holds errors WhatsApp reported for a messages change as a
+// whole rather than for one of its messages or statuses
+type ChangeErrorEvent struct {
+	PhoneNumberID string
+	Errors        []WebhookError
+	Raw           json.RawMessage // the whole change value
 }
 
 // StatusEvent represents a parsed status update event
 type StatusEvent struct {
-	MessageID   string
-	Status      string
-	Timestamp   time.Time
-	RecipientID string
-	ErrorCode   int
-	ErrorTitle  string
-	ErrorMsg    string
+	MessageID    string
+	Status       string
+	Timestamp    time.Time
+	RecipientID  string
+	ErrorCode    int
+	ErrorTitle   string
+	ErrorMsg     string
+	ErrorDetails string
+
+	// Conversation and pricing, present on billable statuses
+	ConversationID        string
+	ConversationOrigin    string
+	ConversationExpiresAt *time.Time
+	Pricing               *PricingValue
+
+	PhoneNumberID string // business phone number that sent the message
 }
 
 // ErrorResponse represents an error from WhatsApp API
 type ErrorResponse struct {
 	Error struct {
-		Message      string `json:"message"`
-		Type         string `json:"type"`
-		Code         int    `json:"code"`
-		ErrorData    string `json:"error_data,omitempty"`
-		ErrorSubcode int    `json:"error_subcode,omitempty"`
-		FBTraceID    string `json:"fbtrace_id,omitempty"`
+		Message      string    `json:"message"`
+		Type         string    `json:"type"`
+		Code         int       `json:"code"`
+		ErrorData    ErrorData `json:"error_data,omitempty"`
+		ErrorSubcode int       `json:"error_subcode,omitempty"`
+		FBTraceID    string    `json:"fbtrace_id,omitempty"`
 	} `json:"error"`
 }
+
+// ErrorData carries the human-readable details of a Graph API error
+type ErrorData struct {
+	MessagingProduct string `json:"messaging_product,omitempty"`
+	Details          string `json:"details,omitempty"`
+}
@@ -0,0 +1,96 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+)
+
+// phoneNumberFields are the fields requested when listing phone numbers
+const phoneNumberFields = "id,display_phone_number,verified_name,name_status,quality_rating,messaging_limit_tier,status,code_verification_status,platform_type,throughput"
+
+// Quality ratings reported for a phone number
+const (
+	QualityRatingGreen   = "GREEN"
+	QualityRatingYellow  = "YELLOW"
+	QualityRatingRed     = "RED"
+	QualityRatingUnknown = "UNKNOWN"
+)
+
+// PhoneNumberInfo describes a phone number registered in the WhatsApp Business Account
+type PhoneNumberInfo struct {
+	ID                     string `json:"id"`
+	DisplayPhoneNumber     string `json:"display_phone_number"`
+	VerifiedName           string `json:"verified_name"`
+	NameStatus             string `json:"name_status"`          // APPROVED, DECLINED, PENDING_REVIEW, ...
+	QualityRating          string `json:"quality_rating"`       // GREEN, YELLOW, RED, UNKNOWN
+	MessagingLimitTier     string `json:"messaging_limit_tier"` // TIER_250, TIER_1K, TIER_10K, TIER_100K, TIER_UNLIMITED
+	Status                 string `json:"status"`               // CONNECTED, FLAGGED, RESTRICTED, ...
+	CodeVerificationStatus string `json:"code_verification_status"`
+	PlatformType           string `json:"platform_type"`
+	Throughput             struct {
+		Level string `json:"level"`
+	} `json:"throughput"`
+}
+
+// phoneNumberListResponse represents one page of the phone number list
+type phoneNumberListResponse struct {
+	Data   []PhoneNumberInfo `json:"data"`
+	Paging struct {
+		Cursors struct {
+			After string `json:"after"`
+		} `json:"cursors"`
+		Next string `json:"next"`
+	} `json:"paging"`
+}
+
+// QualityRank orders quality ratings from worst to best so changes can be compared.
+// Unknown ratings rank as 0.
+func QualityRank(rating string) int {
+	switch rating {
+	case QualityRatingRed:
+		return 1
+	case QualityRatingYellow:
+		return 2
+	case QualityRatingGreen:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// ListPhoneNumbers returns every phone number in the WhatsApp Business Account, following pagination
+func (c *Client) ListPhoneNumbers(ctx context.Context) ([]PhoneNumberInfo, error) {
+	if err := c.requireBusinessAccount(); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/%s/phone_numbers", c.businessAccountID)
+	query := map[string]string{
+		"fields": phoneNumberFields,
+	}
+
+	var numbers []PhoneNumberInfo
+	for {
+		var page phoneNumberListResponse
+		if err := c.get(ctx, endpoint, query, &page); err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, page.Data...)
+
+		if page.Paging.Next == "" || page.Paging.Cursors.After == "" {
+			break
+		}
+		query["after"] = page.Paging.Cursors.After
+	}
+
+	return numbers, nil
+}
+
+// GetPhoneNumber returns the details of the client's own phone number
+func (c *Client) GetPhoneNumber(ctx context.Context) (*PhoneNumberInfo, error) {
+	var info PhoneNumberInfo
+	if err := c.get(ctx, fmt.Sprintf("/%s", c.phoneNumberID), map[string]string{"fields": phoneNumberFields}, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
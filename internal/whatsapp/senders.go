@@ -0,0 +1,184 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Sender is a business phone number that messages can be sent from
+type Sender struct {
+	Name   string
+	Client *Client
+
+	mu          sync.RWMutex
+	phoneNumber string
+}
+
+// NewSender creates a sender. phoneNumber is the display number of the sender;
+// when empty it is resolved from the Graph API by Registry.ResolvePhoneNumbers.
+func NewSender(name, phoneNumber string, client *Client) *Sender {
+	return &Sender{
+		Name:        name,
+		Client:      client,
+		phoneNumber: NormalizePhoneNumber(phoneNumber),
+	}
+}
+
+// PhoneNumberID returns the WhatsApp phone number ID of the sender
+func (s *Sender) PhoneNumberID() string {
+	return s.Client.phoneNumberID
+}
+
+// PhoneNumber returns the sender's phone number in international format without a
+// leading plus, or an empty string if it is not known
+func (s *Sender) PhoneNumber() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.phoneNumber
+}
+
+// ResolvePhoneNumber looks up the sender's display number from the Graph API if it
+// was not configured
+func (s *Sender) ResolvePhoneNumber(ctx context.Context) error {
+	if s.PhoneNumber() != "" {
+		return nil
+	}
+
+	info, err := s.Client.GetPhoneNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if NormalizePhoneNumber(info.DisplayPhoneNumber) == "" {
+		return fmt.Errorf("phone number ID %s has no display phone number", s.PhoneNumberID())
+	}
+
+	s.SetPhoneNumber(info.DisplayPhoneNumber)
+	return nil
+}
+
+// SetPhoneNumber records the display number of the sender, for example from webhook metadata
+func (s *Sender) SetPhoneNumber(displayPhoneNumber string) {
+	phoneNumber := NormalizePhoneNumber(displayPhoneNumber)
+	if phoneNumber == "" {
+		return
+	}
+	s.mu.Lock()
+	s.phoneNumber = phoneNumber
+	s.mu.Unlock()
+}
+
+// Registry holds the sender phone numbers configured for the deployment
+type Registry struct {
+	senders         []*Sender
+	byName          map[string]*Sender
+	byPhoneNumberID map[string]*Sender
+	defaultSender   *Sender
+}
+
+// NewRegistry creates a registry from the given senders. The sender named
+// defaultName is used when no sender is selected; if defaultName is empty the
+// first sender is the default.
+func NewRegistry(senders []*Sender, defaultName string) (*Registry, error) {
+	if len(senders) == 0 {
+		return nil, fmt.Errorf("at least one sender is required")
+	}
+
+	r := &Registry{
+		senders:         senders,
+		byName:          make(map[string]*Sender, len(senders)),
+		byPhoneNumberID: make(map[string]*Sender, len(senders)),
+	}
+
+	for _, sender := range senders {
+		if sender.Name == "" {
+			return nil, fmt.Errorf("sender for phone number ID %s has no name", sender.PhoneNumberID())
+		}
+		if _, exists := r.byName[sender.Name]; exists {
+			return nil, fmt.Errorf("duplicate sender name %q", sender.Name)
+		}
+		if _, exists := r.byPhoneNumberID[sender.PhoneNumberID()]; exists {
+			return nil, fmt.Errorf("duplicate sender phone number ID %s", sender.PhoneNumberID())
+		}
+		r.byName[sender.Name] = sender
+		r.byPhoneNumberID[sender.PhoneNumberID()] = sender
+	}
+
+	r.defaultSender = senders[0]
+	if defaultName != "" {
+		sender, ok := r.byName[defaultName]
+		if !ok {
+			return nil, fmt.Errorf("default sender %q is not configured", defaultName)
+		}
+		r.defaultSender = sender
+	}
+
+	return r, nil
+}
+
+// ResolvePhoneNumbers looks up the display number of every sender that was not
+// configured with one. Senders that cannot be resolved are logged and keep an
+// empty number until a webhook for them reports it.
+func (r *Registry) ResolvePhoneNumbers(ctx context.Context) {
+	for _, sender := range r.senders {
+		if err := sender.ResolvePhoneNumber(ctx); err != nil {
+			sender.Client.logger.Warn("Failed to resolve sender phone number",
+				zap.Error(err),
+				zap.String("sender", sender.Name),
+				zap.String("phone_number_id", sender.PhoneNumberID()),
+			)
+		}
+	}
+}
+
+// Default returns the sender used when none is selected
+func (r *Registry) Default() *Sender {
+	return r.defaultSender
+}
+
+// All returns every configured sender
+func (r *Registry) All() []*Sender {
+	return r.senders
+}
+
+// Get finds a sender by name or phone number ID; an empty selector returns the default sender
+func (r *Registry) Get(selector string) (*Sender, bool) {
+	if selector == "" {
+		return r.defaultSender, true
+	}
+	if sender, ok := r.byName[selector]; ok {
+		return sender, true
+	}
+	sender, ok := r.byPhoneNumberID[selector]
+	return sender, ok
+}
+
+// ForPhoneNumberID finds the sender that owns a WhatsApp phone number ID
+func (r *Registry) ForPhoneNumberID(phoneNumberID string) (*Sender, bool) {
+	sender, ok := r.byPhoneNumberID[phoneNumberID]
+	return sender, ok
+}
+
+// ClientFor returns the client for a phone number ID, falling back to the default
+// sender for records created before multiple senders were supported
+func (r *Registry) ClientFor(phoneNumberID string) *Client {
+	if sender, ok := r.byPhoneNumberID[phoneNumberID]; ok {
+		return sender.Client
+	}
+	return r.defaultSender.Client
+}
+
+// NormalizePhoneNumber strips formatting from a display phone number such as
+// "+1 555-010-1234", leaving only the digits as used in webhook payloads
+func NormalizePhoneNumber(phoneNumber string) string {
+	var b strings.Builder
+	for _, r := range phoneNumber {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
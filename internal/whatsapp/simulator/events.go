@@ -0,0 +1,442 @@
+package simulator
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Event types understood by the simulator. Message types mirror the "type"
+// of an inbound message, except the interactive replies which are split by
+// reply kind; quick_reply is the "button" payload of a template quick reply.
+const (
+	EventText       = "text"
+	EventImage      = "image"
+	EventVideo      = "video"
+	EventAudio      = "audio"
+	EventDocument   = "document"
+	EventSticker    = "sticker"
+	EventLocation   = "location"
+	EventContacts   = "contacts"
+	EventReaction   = "reaction"
+	EventButton     = "button_reply"
+	EventList       = "list_reply"
+	EventQuickReply = "quick_reply"
+	EventFlow       = "flow"
+	EventOrder      = "order"
+	EventStatus     = "status"
+)
+
+// EventTypes lists every event type, in the order they are documented
+var EventTypes = []string{
+	EventText, EventImage, EventVideo, EventAudio, EventDocument, EventSticker,
+	EventLocation, EventContacts, EventReaction, EventButton, EventList,
+	EventQuickReply, EventFlow, EventOrder, EventStatus,
+}
+
+// Message statuses a status event can report
+var Statuses = []string{"sent", "delivered", "read", "failed", "deleted"}
+
+// errorTitles are the titles WhatsApp sends for common failure codes
+var errorTitles = map[int]string{
+	131000: "Something went wrong",
+	131026: "Message undeliverable",
+	131047: "Re-engagement message",
+	131049: "This message was not delivered to maintain healthy ecosystem engagement.",
+	131051: "Unsupported message type",
+	131053: "Media upload error",
+	130429: "Rate limit hit",
+	132001: "Template does not exist",
+}
+
+// Event describes one webhook to simulate. Message events use From, Name and
+// the fields of their type; status events use MessageID, Status and the error
+// fields. Scripts decode steps into Events, so fields carry YAML tags.
+type Event struct {
+	Type  string        `yaml:"type"`
+	Delay time.Duration `yaml:"delay,omitempty"` // wait before sending, in scripts
+
+	// Sender of inbound messages
+	From string `yaml:"from,omitempty"`
+	Name string `yaml:"name,omitempty"`
+
+	ID        string `yaml:"id,omitempty"`       // WhatsApp message ID, generated when empty
+	ReplyTo   string `yaml:"reply_to,omitempty"` // message the inbound message quotes
+	Forwarded bool   `yaml:"forwarded,omitempty"`
+
+	Text string `yaml:"text,omitempty"`
+
+	// Media messages
+	MediaID  string `yaml:"media_id,omitempty"`
+	MimeType string `yaml:"mime_type,omitempty"`
+	Caption  string `yaml:"caption,omitempty"`
+	Filename string `yaml:"filename,omitempty"`
+	Voice    bool   `yaml:"voice,omitempty"`
+
+	Location *Location `yaml:"location,omitempty"`
+	Contact  *Contact  `yaml:"contact,omitempty"`
+
+	// Reactions and statuses refer to another message
+	MessageID string `yaml:"message_id,omitempty"`
+	Emoji     string `yaml:"emoji,omitempty"`
+
+	Reply *Reply `yaml:"reply,omitempty"` // button, list and quick replies
+	Flow  *Flow  `yaml:"flow,omitempty"`
+	Order *Order `yaml:"order,omitempty"`
+
+	// Status events
+	Status       string `yaml:"status,omitempty"`
+	RecipientID  string `yaml:"recipient_id,omitempty"`
+	ErrorCode    int    `yaml:"error_code,omitempty"`
+	ErrorTitle   string `yaml:"error_title,omitempty"`
+	ErrorMessage string `yaml:"error_message,omitempty"`
+	Category     string `yaml:"category,omitempty"` // conversation and pricing category, defaults to service
+}
+
+// Location is a shared location
+type Location struct {
+	Latitude  float64 `yaml:"latitude"`
+	Longitude float64 `yaml:"longitude"`
+	Name      string  `yaml:"name,omitempty"`
+	Address   string  `yaml:"address,omitempty"`
+}
+
+// Contact is a shared contact card
+type Contact struct {
+	Name  string `yaml:"name"`
+	Phone string `yaml:"phone"`
+}
+
+// Reply is the option a user picked from buttons, a list or a template quick reply
+type Reply struct {
+	ID          string `yaml:"id"` // button or row ID; the payload of a quick reply
+	Title       string `yaml:"title"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// Flow is a completed flow
+type Flow struct {
+	Name     string                 `yaml:"name,omitempty"`
+	Body     string                 `yaml:"body,omitempty"`
+	Token    string                 `yaml:"token,omitempty"`
+	Response map[string]interface{} `yaml:"response,omitempty"`
+}
+
+// Order is an order placed from a catalog
+type Order struct {
+	CatalogID string      `yaml:"catalog_id"`
+	Text      string      `yaml:"text,omitempty"`
+	Items     []OrderItem `yaml:"items"`
+}
+
+// OrderItem is a line item of an order
+type OrderItem struct {
+	ProductRetailerID string  `yaml:"product_retailer_id"`
+	Quantity          int     `yaml:"quantity"`
+	ItemPrice         float64 `yaml:"item_price"`
+	Currency          string  `yaml:"currency"`
+}
+
+// IsStatus reports whether the event is a status update rather than a message
+func (e *Event) IsStatus() bool {
+	return e.Type == EventStatus
+}
+
+// Validate checks that the event has the fields its type needs
+func (e *Event) Validate() error {
+	switch e.Type {
+	case EventStatus:
+		if e.MessageID == "" {
+			return fmt.Errorf("status: message_id is required")
+		}
+		if !contains(Statuses, e.Status) {
+			return fmt.Errorf("status: status must be one of %v", Statuses)
+		}
+		if e.Status == "failed" && e.ErrorCode == 0 {
+			return fmt.Errorf("status: error_code is required for failed statuses")
+		}
+		return nil
+	case "":
+		return fmt.Errorf("event type is required")
+	}
+
+	if e.From == "" {
+		return fmt.Errorf("%s: from is required", e.Type)
+	}
+
+	switch e.Type {
+	case EventText:
+		if e.Text == "" {
+			return fmt.Errorf("text: text is required")
+		}
+	case EventImage, EventVideo, EventAudio, EventDocument, EventSticker:
+		// media_id and mime_type have defaults
+	case EventLocation:
+		if e.Location == nil {
+			return fmt.Errorf("location: location is required")
+		}
+	case EventContacts:
+		if e.Contact == nil || e.Contact.Name == "" || e.Contact.Phone == "" {
+			return fmt.Errorf("contacts: contact name and phone are required")
+		}
+	case EventReaction:
+		if e.MessageID == "" {
+			return fmt.Errorf("reaction: message_id is required")
+		}
+	case EventButton, EventList, EventQuickReply:
+		if e.Reply == nil || e.Reply.ID == "" || e.Reply.Title == "" {
+			return fmt.Errorf("%s: reply id and title are required", e.Type)
+		}
+	case EventFlow:
+		if e.Flow == nil {
+			return fmt.Errorf("flow: flow is required")
+		}
+	case EventOrder:
+		if e.Order == nil || e.Order.CatalogID == "" || len(e.Order.Items) == 0 {
+			return fmt.Errorf("order: catalog_id and at least one item are required")
+		}
+	default:
+		return fmt.Errorf("unknown event type: %s", e.Type)
+	}
+	return nil
+}
+
+// message builds the webhook message object for a message event
+func (e *Event) message(timestamp time.Time) map[string]interface{} {
+	msg := map[string]interface{}{
+		"from":      e.From,
+		"id":        e.ID,
+		"timestamp": strconv.FormatInt(timestamp.Unix(), 10),
+		"type":      e.Type,
+	}
+
+	if e.ReplyTo != "" || e.Forwarded {
+		context := map[string]interface{}{}
+		if e.ReplyTo != "" {
+			context["from"] = e.From
+			context["id"] = e.ReplyTo
+		}
+		if e.Forwarded {
+			context["forwarded"] = true
+		}
+		msg["context"] = context
+	}
+
+	switch e.Type {
+	case EventText:
+		msg["text"] = map[string]interface{}{"body": e.Text}
+
+	case EventImage, EventVideo, EventAudio, EventDocument, EventSticker:
+		media := map[string]interface{}{
+			"id":        defaultString(e.MediaID, randomDigits(15)),
+			"mime_type": defaultString(e.MimeType, defaultMimeTypes[e.Type]),
+			"sha256":    randomToken(32),
+		}
+		if e.Caption != "" && e.Type != EventAudio && e.Type != EventSticker {
+			media["caption"] = e.Caption
+		}
+		if e.Type == EventDocument {
+			media["filename"] = defaultString(e.Filename, "document.pdf")
+		}
+		if e.Type == EventAudio {
+			media["voice"] = e.Voice
+		}
+		if e.Type == EventSticker {
+			media["animated"] = false
+		}
+		msg[e.Type] = media
+
+	case EventLocation:
+		location := map[string]interface{}{
+			"latitude":  e.Location.Latitude,
+			"longitude": e.Location.Longitude,
+		}
+		if e.Location.Name != "" {
+			location["name"] = e.Location.Name
+		}
+		if e.Location.Address != "" {
+			location["address"] = e.Location.Address
+		}
+		msg["location"] = location
+
+	case EventContacts:
+		msg["contacts"] = []map[string]interface{}{{
+			"name":   map[string]interface{}{"formatted_name": e.Contact.Name, "first_name": e.Contact.Name},
+			"phones": []map[string]interface{}{{"phone": e.Contact.Phone, "wa_id": digits(e.Contact.Phone), "type": "CELL"}},
+		}}
+
+	case EventReaction:
+		msg["reaction"] = map[string]interface{}{"message_id": e.MessageID, "emoji": e.Emoji}
+
+	case EventButton:
+		msg["type"] = "interactive"
+		msg["interactive"] = map[string]interface{}{
+			"type":         "button_reply",
+			"button_reply": map[string]interface{}{"id": e.Reply.ID, "title": e.Reply.Title},
+		}
+
+	case EventList:
+		reply := map[string]interface{}{"id": e.Reply.ID, "title": e.Reply.Title}
+		if e.Reply.Description != "" {
+			reply["description"] = e.Reply.Description
+		}
+		msg["type"] = "interactive"
+		msg["interactive"] = map[string]interface{}{
+			"type":       "list_reply",
+			"list_reply": reply,
+		}
+
+	case EventQuickReply:
+		msg["type"] = "button"
+		msg["button"] = map[string]interface{}{"payload": e.Reply.ID, "text": e.Reply.Title}
+
+	case EventFlow:
+		response := map[string]interface{}{}
+		for key, value := range e.Flow.Response {
+			response[key] = value
+		}
+		if e.Flow.Token != "" {
+			response["flow_token"] = e.Flow.Token
+		}
+		responseJSON, _ := jsonString(response)
+		msg["type"] = "interactive"
+		msg["interactive"] = map[string]interface{}{
+			"type": "nfm_reply",
+			"nfm_reply": map[string]interface{}{
+				"name":          defaultString(e.Flow.Name, "flow"),
+				"body":          defaultString(e.Flow.Body, "Sent"),
+				"response_json": responseJSON,
+			},
+		}
+
+	case EventOrder:
+		items := make([]map[string]interface{}, 0, len(e.Order.Items))
+		for _, item := range e.Order.Items {
+			items = append(items, map[string]interface{}{
+				"product_retailer_id": item.ProductRetailerID,
+				"quantity":            item.Quantity,
+				"item_price":          item.ItemPrice,
+				"currency":            item.Currency,
+			})
+		}
+		order := map[string]interface{}{
+			"catalog_id":    e.Order.CatalogID,
+			"product_items": items,
+		}
+		if e.Order.Text != "" {
+			order["text"] = e.Order.Text
+		}
+		msg["order"] = order
+	}
+
+	return msg
+}
+
+// status builds the webhook status object for a status event. Billable
+// statuses carry conversation and pricing details like real webhooks do.
+func (e *Event) status(timestamp time.Time) map[string]interface{} {
+	status := map[string]interface{}{
+		"id":           e.MessageID,
+		"status":       e.Status,
+		"timestamp":    strconv.FormatInt(timestamp.Unix(), 10),
+		"recipient_id": e.RecipientID,
+	}
+
+	switch e.Status {
+	case "failed":
+		title := defaultString(e.ErrorTitle, errorTitles[e.ErrorCode])
+		if title == "" {
+			title = "Message failed to send"
+		}
+		errorEntry := map[string]interface{}{
+			"code":  e.ErrorCode,
+			"title": title,
+		}
+		if e.ErrorMessage != "" {
+			errorEntry["message"] = e.ErrorMessage
+			errorEntry["error_data"] = map[string]interface{}{"details": e.ErrorMessage}
+		}
+		status["errors"] = []map[string]interface{}{errorEntry}
+
+	case "sent", "delivered", "read":
+		category := defaultString(e.Category, "service")
+		conversation := map[string]interface{}{
+			"id":     "conv_" + randomToken(12),
+			"origin": map[string]interface{}{"type": category},
+		}
+		if e.Status == "sent" {
+			conversation["expiration_timestamp"] = strconv.FormatInt(timestamp.Add(24*time.Hour).Unix(), 10)
+		}
+		status["conversation"] = conversation
+		status["pricing"] = map[string]interface{}{
+			"billable":      category != "service",
+			"pricing_model": "CBP",
+			"category":      category,
+		}
+	}
+
+	return status
+}
+
+var defaultMimeTypes = map[string]string{
+	EventImage:    "image/jpeg",
+	EventVideo:    "video/mp4",
+	EventAudio:    "audio/ogg; codecs=opus",
+	EventDocument: "application/pdf",
+	EventSticker:  "image/webp",
+}
+
+// NewMessageID returns a random ID in the format WhatsApp uses for messages
+func NewMessageID() string {
+	return "wamid." + randomToken(36)
+}
+
+func randomToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)[:n]
+}
+
+func randomDigits(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	for i := range b {
+		b[i] = '0' + b[i]%10
+	}
+	if b[0] == '0' {
+		b[0] = '1'
+	}
+	return string(b)
+}
+
+func digits(phone string) string {
+	out := make([]byte, 0, len(phone))
+	for i := 0; i < len(phone); i++ {
+		if phone[i] >= '0' && phone[i] <= '9' {
+			out = append(out, phone[i])
+		}
+	}
+	return string(out)
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
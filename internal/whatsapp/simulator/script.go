@@ -0,0 +1,124 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PreviousMessage can be used as reply_to or message_id in a script step to
+// refer to the message simulated by the step before it
+const PreviousMessage = "$previous"
+
+// Script is a scripted conversation loaded from YAML:
+//
+//	from: "15551234567"
+//	name: Alice
+//	delay: 1s
+//	steps:
+//	  - type: text
+//	    text: Hi, where is my order?
+//	  - type: reaction
+//	    message_id: $previous
+//	    emoji: "👍"
+//	  - type: status
+//	    message_id: wamid.HBgL...
+//	    status: failed
+//	    error_code: 131047
+//
+// Steps inherit from, name and delay from the script when they leave them empty.
+type Script struct {
+	From  string        `yaml:"from"`
+	Name  string        `yaml:"name"`
+	Delay time.Duration `yaml:"delay"`
+	Steps []Event       `yaml:"steps"`
+}
+
+// LoadScript reads and validates a script file
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	return ParseScript(data)
+}
+
+// ParseScript parses and validates a YAML script
+func ParseScript(data []byte) (*Script, error) {
+	var script Script
+	if err := yaml.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("invalid script: %w", err)
+	}
+	if len(script.Steps) == 0 {
+		return nil, fmt.Errorf("script has no steps")
+	}
+
+	for i := range script.Steps {
+		step := &script.Steps[i]
+		if step.From == "" {
+			step.From = script.From
+		}
+		if step.Name == "" {
+			step.Name = script.Name
+		}
+		if step.Delay == 0 {
+			step.Delay = script.Delay
+		}
+		if i == 0 && (step.ReplyTo == PreviousMessage || step.MessageID == PreviousMessage) {
+			return nil, fmt.Errorf("step 1: %s has no previous message to refer to", PreviousMessage)
+		}
+
+		// Validate against a placeholder so references resolved at replay time pass
+		check := *step
+		if check.MessageID == PreviousMessage {
+			check.MessageID = "placeholder"
+		}
+		if err := check.Validate(); err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+
+	return &script, nil
+}
+
+// Replay sends the steps of a script in order, waiting each step's delay
+// before it. onResult is called after every step; replay stops at the first
+// delivery error or when onResult returns an error.
+func (s *Simulator) Replay(ctx context.Context, script *Script, onResult func(step int, event *Event, result *Result) error) error {
+	previous := ""
+
+	for i := range script.Steps {
+		event := script.Steps[i]
+		if event.ReplyTo == PreviousMessage {
+			event.ReplyTo = previous
+		}
+		if event.MessageID == PreviousMessage {
+			event.MessageID = previous
+		}
+
+		if i > 0 && event.Delay > 0 {
+			select {
+			case <-time.After(event.Delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		result, err := s.Send(ctx, &event)
+		if err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, event.Type, err)
+		}
+		previous = result.Webhook.MessageID
+
+		if onResult != nil {
+			if err := onResult(i+1, &event, result); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package simulator crafts realistic WhatsApp webhook payloads for every
+// inbound message type and status transition, signs them like Meta does and
+// posts them to a running instance. It backs the cmd/simulate CLI.
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/pkg/utils"
+)
+
+// SignatureHeader is the header carrying the payload signature
+const SignatureHeader = "X-Hub-Signature-256"
+
+// Simulator builds and delivers webhooks for one business phone number
+type Simulator struct {
+	WebhookURL         string
+	Secret             string // app secret used to sign payloads; unsigned when empty
+	PhoneNumberID      string
+	DisplayPhoneNumber string
+	BusinessAccountID  string
+	InvalidSignature   bool // sign with a wrong secret, to test rejection
+	HTTPClient         *http.Client
+}
+
+// Webhook is a built webhook, ready to be posted
+type Webhook struct {
+	MessageID string // ID of the simulated message, or of the message a status refers to
+	Body      []byte
+	Signature string
+}
+
+// Result is the response of the instance to a posted webhook
+type Result struct {
+	Webhook    *Webhook
+	StatusCode int
+	Body       string
+}
+
+// Build validates an event and renders it as a signed webhook payload
+func (s *Simulator) Build(event *Event) (*Webhook, error) {
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	value := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"metadata": map[string]interface{}{
+			"display_phone_number": digits(s.DisplayPhoneNumber),
+			"phone_number_id":      s.PhoneNumberID,
+		},
+	}
+
+	messageID := event.MessageID
+	if event.IsStatus() {
+		value["statuses"] = []interface{}{event.status(now)}
+	} else {
+		if event.ID == "" {
+			event.ID = NewMessageID()
+		}
+		messageID = event.ID
+		value["contacts"] = []interface{}{map[string]interface{}{
+			"profile": map[string]interface{}{"name": event.Name},
+			"wa_id":   event.From,
+		}}
+		value["messages"] = []interface{}{event.message(now)}
+	}
+
+	payload := map[string]interface{}{
+		"object": "whatsapp_business_account",
+		"entry": []interface{}{map[string]interface{}{
+			"id": s.BusinessAccountID,
+			"changes": []interface{}{map[string]interface{}{
+				"value": value,
+				"field": "messages",
+			}},
+		}},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	return &Webhook{
+		MessageID: messageID,
+		Body:      body,
+		Signature: s.sign(body),
+	}, nil
+}
+
+// Send builds an event and posts it to the webhook URL
+func (s *Simulator) Send(ctx context.Context, event *Event) (*Result, error) {
+	webhook, err := s.Build(event)
+	if err != nil {
+		return nil, err
+	}
+	return s.Post(ctx, webhook)
+}
+
+// Post delivers a built webhook to the webhook URL
+func (s *Simulator) Post(ctx context.Context, webhook *Webhook) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.WebhookURL, bytes.NewReader(webhook.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.Signature != "" {
+		req.Header.Set(SignatureHeader, webhook.Signature)
+	}
+
+	client := s.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	return &Result{
+		Webhook:    webhook,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	}, nil
+}
+
+// sign computes the X-Hub-Signature-256 value for a payload
+func (s *Simulator) sign(body []byte) string {
+	if s.Secret == "" {
+		return ""
+	}
+	secret := s.Secret
+	if s.InvalidSignature {
+		secret += "-invalid"
+	}
+	return utils.ComputeHMAC(body, []byte(secret))
+}
+
+func jsonString(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
@@ -0,0 +1,301 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+)
+
+const testSecret = "test-secret"
+
+func newTestSimulator() *Simulator {
+	return &Simulator{
+		Secret:             testSecret,
+		PhoneNumberID:      "111",
+		DisplayPhoneNumber: "+1 555-010-0001",
+		BusinessAccountID:  "222",
+	}
+}
+
+func TestBuildMessages(t *testing.T) {
+	sim := newTestSimulator()
+
+	events := []*Event{
+		{Type: EventText, From: "15551234567", Name: "Alice", Text: "hello"},
+		{Type: EventImage, From: "15551234567", Caption: "a photo"},
+		{Type: EventVideo, From: "15551234567"},
+		{Type: EventAudio, From: "15551234567", Voice: true},
+		{Type: EventDocument, From: "15551234567", Filename: "invoice.pdf"},
+		{Type: EventSticker, From: "15551234567"},
+		{Type: EventLocation, From: "15551234567", Location: &Location{Latitude: 1.5, Longitude: 2.5, Name: "Office"}},
+		{Type: EventContacts, From: "15551234567", Contact: &Contact{Name: "Bob", Phone: "+1 555 010 0002"}},
+		{Type: EventReaction, From: "15551234567", MessageID: "wamid.out", Emoji: "👍"},
+		{Type: EventButton, From: "15551234567", Reply: &Reply{ID: "yes", Title: "Yes"}},
+		{Type: EventList, From: "15551234567", Reply: &Reply{ID: "row_1", Title: "Row 1", Description: "First"}},
+		{Type: EventQuickReply, From: "15551234567", Reply: &Reply{ID: "STOP", Title: "Stop promotions"}},
+		{Type: EventFlow, From: "15551234567", Flow: &Flow{Token: "flowtok_1", Response: map[string]interface{}{"rating": "5"}}},
+		{Type: EventOrder, From: "15551234567", Order: &Order{CatalogID: "c1", Items: []OrderItem{{ProductRetailerID: "sku", Quantity: 2, ItemPrice: 3, Currency: "USD"}}}},
+	}
+
+	for _, event := range events {
+		t.Run(event.Type, func(t *testing.T) {
+			webhook, err := sim.Build(event)
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+			if !whatsapp.VerifySignature(webhook.Body, webhook.Signature, testSecret) {
+				t.Errorf("Signature does not verify")
+			}
+
+			payload, err := whatsapp.ParseWebhook(webhook.Body)
+			if err != nil {
+				t.Fatalf("ParseWebhook failed: %v", err)
+			}
+			parsed, err := whatsapp.ParseMessageEvent(payload)
+			if err != nil {
+				t.Fatalf("ParseMessageEvent failed: %v", err)
+			}
+			if len(parsed) != 1 {
+				t.Fatalf("Expected 1 message event, got %d", len(parsed))
+			}
+
+			got := parsed[0]
+			if got.MessageID != webhook.MessageID || got.From != event.From {
+				t.Errorf("Unexpected message event: %+v", got)
+			}
+			if got.PhoneNumberID != "111" || got.DisplayPhoneNumber != "15550100001" {
+				t.Errorf("Unexpected metadata: %s %s", got.PhoneNumberID, got.DisplayPhoneNumber)
+			}
+
+			switch event.Type {
+			case EventText:
+				if got.Content != "hello" || got.ContactName != "Alice" {
+					t.Errorf("Unexpected text event: %+v", got)
+				}
+			case EventImage:
+				if got.MediaID == "" || got.MimeType != "image/jpeg" || got.Caption != "a photo" {
+					t.Errorf("Unexpected image event: %+v", got)
+				}
+			case EventDocument:
+				if got.Filename != "invoice.pdf" {
+					t.Errorf("Expected filename invoice.pdf, got %q", got.Filename)
+				}
+			case EventLocation:
+				if got.Location == nil || got.Location.Latitude != 1.5 || got.Content != "Office" {
+					t.Errorf("Unexpected location event: %+v", got)
+				}
+			case EventContacts:
+				if len(got.Contacts) != 1 || got.Contacts[0].Phones[0].WaID != "15550100002" {
+					t.Errorf("Unexpected contacts event: %+v", got.Contacts)
+				}
+			case EventReaction:
+				if got.Reaction == nil || got.ReplyToID != "wamid.out" {
+					t.Errorf("Unexpected reaction event: %+v", got)
+				}
+			case EventSticker:
+				if got.MediaID == "" || got.MimeType != "image/webp" {
+					t.Errorf("Unexpected sticker event: %+v", got)
+				}
+			case EventButton, EventList, EventQuickReply:
+				if got.Reply == nil || got.Reply.ID != event.Reply.ID || got.Reply.Title != event.Reply.Title {
+					t.Errorf("Unexpected reply: %+v", got.Reply)
+				} else if got.Reply.Type != event.Type {
+					t.Errorf("Expected reply type %s, got %s", event.Type, got.Reply.Type)
+				}
+			case EventFlow:
+				if got.FlowReply == nil || got.FlowReply.FlowToken != "flowtok_1" {
+					t.Errorf("Unexpected flow event: %+v", got.FlowReply)
+				}
+			case EventOrder:
+				if got.Order == nil || got.Order.Total() != 6 {
+					t.Errorf("Unexpected order event: %+v", got.Order)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildInteractiveReplies(t *testing.T) {
+	sim := newTestSimulator()
+
+	webhook, err := sim.Build(&Event{Type: EventList, From: "15551234567", Reply: &Reply{ID: "row_1", Title: "Row 1"}})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	var payload struct {
+		Entry []struct {
+			Changes []struct {
+				Value struct {
+					Messages []struct {
+						Type        string `json:"type"`
+						Interactive struct {
+							Type      string `json:"type"`
+							ListReply struct {
+								ID    string `json:"id"`
+								Title string `json:"title"`
+							} `json:"list_reply"`
+						} `json:"interactive"`
+					} `json:"messages"`
+				} `json:"value"`
+			} `json:"changes"`
+		} `json:"entry"`
+	}
+	if err := json.Unmarshal(webhook.Body, &payload); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+
+	msg := payload.Entry[0].Changes[0].Value.Messages[0]
+	if msg.Type != "interactive" || msg.Interactive.Type != "list_reply" || msg.Interactive.ListReply.ID != "row_1" {
+		t.Errorf("Unexpected list reply: %+v", msg)
+	}
+}
+
+func TestBuildStatuses(t *testing.T) {
+	sim := newTestSimulator()
+
+	for _, status := range []string{"sent", "delivered", "read", "failed"} {
+		event := &Event{Type: EventStatus, MessageID: "wamid.out", Status: status, RecipientID: "15551234567"}
+		if status == "failed" {
+			event.ErrorCode = 131047
+		}
+
+		webhook, err := sim.Build(event)
+		if err != nil {
+			t.Fatalf("%s: Build failed: %v", status, err)
+		}
+		payload, err := whatsapp.ParseWebhook(webhook.Body)
+		if err != nil {
+			t.Fatalf("%s: ParseWebhook failed: %v", status, err)
+		}
+		parsed, err := whatsapp.ParseStatusEvent(payload)
+		if err != nil || len(parsed) != 1 {
+			t.Fatalf("%s: expected 1 status event, got %d (%v)", status, len(parsed), err)
+		}
+		if parsed[0].MessageID != "wamid.out" || parsed[0].Status != status {
+			t.Errorf("%s: unexpected status event: %+v", status, parsed[0])
+		}
+		if status == "failed" && (parsed[0].ErrorCode != 131047 || parsed[0].ErrorTitle != "Re-engagement message") {
+			t.Errorf("Unexpected failure details: %+v", parsed[0])
+		}
+		if status == "sent" {
+			if parsed[0].ConversationID == "" || parsed[0].ConversationOrigin != "service" || parsed[0].ConversationExpiresAt == nil {
+				t.Errorf("Unexpected conversation: %+v", parsed[0])
+			}
+			if parsed[0].Pricing == nil || parsed[0].Pricing.Billable || parsed[0].Pricing.Category != "service" {
+				t.Errorf("Unexpected pricing: %+v", parsed[0].Pricing)
+			}
+		}
+	}
+
+	if _, err := sim.Build(&Event{Type: EventStatus, MessageID: "wamid.out", Status: "failed"}); err == nil {
+		t.Errorf("Expected an error for a failed status without an error code")
+	}
+}
+
+func TestSignature(t *testing.T) {
+	sim := newTestSimulator()
+	event := &Event{Type: EventText, From: "15551234567", Text: "hello"}
+
+	sim.InvalidSignature = true
+	webhook, err := sim.Build(event)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if whatsapp.VerifySignature(webhook.Body, webhook.Signature, testSecret) {
+		t.Errorf("Invalid signature should not verify")
+	}
+
+	sim.Secret = ""
+	webhook, err = sim.Build(event)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if webhook.Signature != "" {
+		t.Errorf("Expected no signature without a secret, got %s", webhook.Signature)
+	}
+}
+
+func TestReplayScript(t *testing.T) {
+	script, err := LoadScript("../../../cmd/simulate/examples/conversation.yaml")
+	if err != nil {
+		t.Fatalf("LoadScript failed: %v", err)
+	}
+	for i := range script.Steps {
+		script.Steps[i].Delay = 0
+	}
+
+	var bodies [][]byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !whatsapp.VerifySignature(body, r.Header.Get(SignatureHeader), testSecret) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	sim := newTestSimulator()
+	sim.WebhookURL = receiver.URL
+
+	var ids []string
+	err = sim.Replay(context.Background(), script, func(step int, event *Event, result *Result) error {
+		if result.StatusCode != http.StatusOK {
+			t.Errorf("Step %d: expected 200, got %d", step, result.StatusCode)
+		}
+		ids = append(ids, result.Webhook.MessageID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if len(bodies) != len(script.Steps) {
+		t.Fatalf("Expected %d webhooks, got %d", len(script.Steps), len(bodies))
+	}
+
+	// The image replies to the text, and the reaction targets the list reply
+	events := make([]*whatsapp.MessageEvent, len(bodies))
+	for i, body := range bodies {
+		payload, err := whatsapp.ParseWebhook(body)
+		if err != nil {
+			t.Fatalf("ParseWebhook failed: %v", err)
+		}
+		parsed, err := whatsapp.ParseMessageEvent(payload)
+		if err != nil || len(parsed) != 1 {
+			t.Fatalf("Step %d: expected 1 message event (%v)", i+1, err)
+		}
+		events[i] = parsed[0]
+	}
+	if events[1].ReplyToID != ids[0] {
+		t.Errorf("Expected the image to reply to %s, got %s", ids[0], events[1].ReplyToID)
+	}
+	if events[5].ReplyToID != ids[4] {
+		t.Errorf("Expected the reaction to target %s, got %s", ids[4], events[5].ReplyToID)
+	}
+	if events[0].ContactName != "Alice" {
+		t.Errorf("Expected the script name to apply to steps, got %q", events[0].ContactName)
+	}
+}
+
+func TestParseScriptErrors(t *testing.T) {
+	tests := map[string]string{
+		"no steps":          "from: \"1555\"\n",
+		"unknown type":      "from: \"1555\"\nsteps:\n  - type: carrier_pigeon\n",
+		"missing text":      "from: \"1555\"\nsteps:\n  - type: text\n",
+		"previous on first": "from: \"1555\"\nsteps:\n  - type: reaction\n    message_id: $previous\n",
+		"missing sender":    "steps:\n  - type: text\n    text: hi\n",
+	}
+
+	for name, script := range tests {
+		if _, err := ParseScript([]byte(script)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Template component types used when sending a template
+const (
+	TemplateComponentHeader = "header"
+	TemplateComponentBody   = "body"
+	TemplateComponentButton = "button"
+)
+
+// Template button sub types
+const (
+	TemplateButtonQuickReply = "quick_reply"
+	TemplateButtonURL        = "url"
+	TemplateButtonCopyCode   = "copy_code"
+	TemplateButtonFlow       = "flow"
+)
+
+// Template parameter types
+const (
+	TemplateParamText       = "text"
+	TemplateParamCurrency   = "currency"
+	TemplateParamDateTime   = "date_time"
+	TemplateParamImage      = "image"
+	TemplateParamVideo      = "video"
+	TemplateParamDocument   = "document"
+	TemplateParamLocation   = "location"
+	TemplateParamPayload    = "payload"
+	TemplateParamCouponCode = "coupon_code"
+	TemplateParamAction     = "action"
+)
+
+// TemplateComponent fills in the variables of one component of a template being sent
+type TemplateComponent struct {
+	Type       string              `json:"type"`               // header, body, button
+	SubType    string              `json:"sub_type,omitempty"` // buttons only
+	Index      string              `json:"index,omitempty"`    // buttons only, position of the button
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+}
+
+// TemplateParameter represents a typed template variable
+type TemplateParameter struct {
+	Type          string                 `json:"type"`
+	ParameterName string                 `json:"parameter_name,omitempty"` // for templates using named parameters
+	Text          string                 `json:"text,omitempty"`
+	Payload       string                 `json:"payload,omitempty"`
+	CouponCode    string                 `json:"coupon_code,omitempty"`
+	Currency      *TemplateCurrency      `json:"currency,omitempty"`
+	DateTime      *TemplateDateTime      `json:"date_time,omitempty"`
+	Image         *MediaObject           `json:"image,omitempty"`
+	Video         *MediaObject           `json:"video,omitempty"`
+	Document      *MediaObject           `json:"document,omitempty"`
+	Location      *Location              `json:"location,omitempty"`
+	Action        map[string]interface{} `json:"action,omitempty"` // flow button action data
+}
+
+// TemplateCurrency represents a localised currency amount
+type TemplateCurrency struct {
+	FallbackValue string `json:"fallback_value"`
+	Code          string `json:"code"`        // ISO 4217 currency code
+	Amount1000    int64  `json:"amount_1000"` // amount multiplied by 1000
+}
+
+// TemplateDateTime represents a localised date and time
+type TemplateDateTime struct {
+	FallbackValue string `json:"fallback_value"`
+}
+
+// BodyTextComponents builds a body component from plain text parameters
+func BodyTextComponents(params []string) []TemplateComponent {
+	if len(params) == 0 {
+		return nil
+	}
+
+	parameters := make([]TemplateParameter, len(params))
+	for i, param := range params {
+		parameters[i] = TemplateParameter{Type: TemplateParamText, Text: param}
+	}
+
+	return []TemplateComponent{{Type: TemplateComponentBody, Parameters: parameters}}
+}
+
+// ValidateTemplateComponents checks that components are well formed before sending
+func ValidateTemplateComponents(components []TemplateComponent) error {
+	headers := 0
+	buttons := make(map[string]bool)
+
+	for i, component := range components {
+		switch component.Type {
+		case TemplateComponentHeader:
+			headers++
+			if headers > 1 {
+				return errors.New("only one header component is allowed")
+			}
+			if len(component.Parameters) != 1 {
+				return errors.New("header component requires exactly one parameter")
+			}
+
+		case TemplateComponentBody:
+			if len(component.Parameters) == 0 {
+				return errors.New("body component requires parameters")
+			}
+
+		case TemplateComponentButton:
+			switch component.SubType {
+			case TemplateButtonQuickReply, TemplateButtonURL, TemplateButtonCopyCode, TemplateButtonFlow:
+			default:
+				return fmt.Errorf("component %d: unsupported button sub_type: %s", i+1, component.SubType)
+			}
+			if component.Index == "" {
+				return fmt.Errorf("component %d: button index is required", i+1)
+			}
+			if buttons[component.Index] {
+				return fmt.Errorf("component %d: duplicate button index %s", i+1, component.Index)
+			}
+			buttons[component.Index] = true
+			if len(component.Parameters) != 1 {
+				return fmt.Errorf("component %d: button requires exactly one parameter", i+1)
+			}
+
+		default:
+			return fmt.Errorf("component %d: unsupported component type: %s", i+1, component.Type)
+		}
+
+		for j := range component.Parameters {
+			if err := component.Parameters[j].validate(component); err != nil {
+				return fmt.Errorf("component %d parameter %d: %w", i+1, j+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks that a parameter carries the value for its type and is allowed in its component
+func (p *TemplateParameter) validate(component TemplateComponent) error {
+	switch p.Type {
+	case TemplateParamText:
+		if p.Text == "" {
+			return errors.New("text is required")
+		}
+		if component.Type == TemplateComponentButton && component.SubType != TemplateButtonURL {
+			return fmt.Errorf("text parameters are not allowed on %s buttons", component.SubType)
+		}
+
+	case TemplateParamCurrency:
+		if p.Currency == nil || p.Currency.Code == "" || p.Currency.FallbackValue == "" {
+			return errors.New("currency requires code, amount_1000 and fallback_value")
+		}
+
+	case TemplateParamDateTime:
+		if p.DateTime == nil || p.DateTime.FallbackValue == "" {
+			return errors.New("date_time requires fallback_value")
+		}
+
+	case TemplateParamImage, TemplateParamVideo, TemplateParamDocument:
+		if media := p.HeaderMedia(); media == nil || (media.ID == "" && media.Link == "") {
+			return fmt.Errorf("%s requires an id or link", p.Type)
+		}
+		if component.Type != TemplateComponentHeader {
+			return fmt.Errorf("%s parameters are only allowed in headers", p.Type)
+		}
+
+	case TemplateParamLocation:
+		if p.Location == nil {
+			return errors.New("location is required")
+		}
+		if component.Type != TemplateComponentHeader {
+			return errors.New("location parameters are only allowed in headers")
+		}
+		return p.Location.Validate()
+
+	case TemplateParamPayload:
+		if p.Payload == "" {
+			return errors.New("payload is required")
+		}
+		if component.SubType != TemplateButtonQuickReply {
+			return errors.New("payload parameters are only allowed on quick_reply buttons")
+		}
+
+	case TemplateParamCouponCode:
+		if p.CouponCode == "" {
+			return errors.New("coupon_code is required")
+		}
+		if component.SubType != TemplateButtonCopyCode {
+			return errors.New("coupon_code parameters are only allowed on copy_code buttons")
+		}
+
+	case TemplateParamAction:
+		if component.SubType != TemplateButtonFlow {
+			return errors.New("action parameters are only allowed on flow buttons")
+		}
+
+	default:
+		return fmt.Errorf("unsupported parameter type: %s", p.Type)
+	}
+
+	return nil
+}
+
+// HeaderMedia returns the media object of a media header parameter, if any
+func (p *TemplateParameter) HeaderMedia() *MediaObject {
+	switch p.Type {
+	case TemplateParamImage:
+		return p.Image
+	case TemplateParamVideo:
+		return p.Video
+	case TemplateParamDocument:
+		return p.Document
+	}
+	return nil
+}
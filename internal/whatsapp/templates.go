@@ -0,0 +1,177 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ashok/vibecoded-wa-client/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// templatePageSize is the number of templates requested per page when listing
+const templatePageSize = 100
+
+// TemplateDefinition represents a message template as managed in the WhatsApp Business Account
+type TemplateDefinition struct {
+	ID              string                        `json:"id,omitempty"`
+	Name            string                        `json:"name"`
+	Language        string                        `json:"language"`
+	Category        string                        `json:"category"`
+	Status          string                        `json:"status,omitempty"`
+	RejectedReason  string                        `json:"rejected_reason,omitempty"`
+	QualityScore    *TemplateQualityScore         `json:"quality_score,omitempty"`
+	Components      []TemplateDefinitionComponent `json:"components"`
+	ParameterFormat string                        `json:"parameter_format,omitempty"`
+}
+
+// TemplateDefinitionComponent represents a header, body, footer or buttons component of a template
+type TemplateDefinitionComponent struct {
+	Type    string                     `json:"type"`             // HEADER, BODY, FOOTER, BUTTONS
+	Format  string                     `json:"format,omitempty"` // TEXT, IMAGE, VIDEO, DOCUMENT, LOCATION (headers)
+	Text    string                     `json:"text,omitempty"`
+	Example map[string]interface{}     `json:"example,omitempty"`
+	Buttons []TemplateDefinitionButton `json:"buttons,omitempty"`
+}
+
+// TemplateDefinitionButton represents a button of a template
+type TemplateDefinitionButton struct {
+	Type        string   `json:"type"` // QUICK_REPLY, URL, PHONE_NUMBER, COPY_CODE, OTP
+	Text        string   `json:"text,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	PhoneNumber string   `json:"phone_number,omitempty"`
+	Example     []string `json:"example,omitempty"`
+}
+
+// TemplateQualityScore represents the quality rating Meta assigns to a template
+type TemplateQualityScore struct {
+	Score string `json:"score"` // GREEN, YELLOW, RED, UNKNOWN
+}
+
+// CreateTemplateResponse represents the response to a template submission
+type CreateTemplateResponse struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Category string `json:"category"`
+}
+
+// templateListResponse represents one page of the template list
+type templateListResponse struct {
+	Data   []TemplateDefinition `json:"data"`
+	Paging struct {
+		Cursors struct {
+			After string `json:"after"`
+		} `json:"cursors"`
+		Next string `json:"next"`
+	} `json:"paging"`
+}
+
+// BodyText returns the text of the template's body component
+func (t *TemplateDefinition) BodyText() string {
+	for _, component := range t.Components {
+		if component.Type == "BODY" {
+			return component.Text
+		}
+	}
+	return ""
+}
+
+// CreateTemplate submits a new template to the WhatsApp Business Account for review
+func (c *Client) CreateTemplate(ctx context.Context, template *TemplateDefinition) (*CreateTemplateResponse, error) {
+	if err := c.requireBusinessAccount(); err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{
+		"name":       template.Name,
+		"language":   template.Language,
+		"category":   template.Category,
+		"components": template.Components,
+	}
+	if template.ParameterFormat != "" {
+		payload["parameter_format"] = template.ParameterFormat
+	}
+
+	var result CreateTemplateResponse
+	if err := c.post(ctx, fmt.Sprintf("/%s/message_templates", c.businessAccountID), payload, &result); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Template submitted",
+		zap.String("name", template.Name),
+		zap.String("template_id", result.ID),
+		zap.String("status", result.Status),
+	)
+
+	return &result, nil
+}
+
+// ListTemplates returns every template in the WhatsApp Business Account, following pagination
+func (c *Client) ListTemplates(ctx context.Context) ([]TemplateDefinition, error) {
+	if err := c.requireBusinessAccount(); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/%s/message_templates", c.businessAccountID)
+	query := map[string]string{
+		"fields": "id,name,language,category,status,rejected_reason,quality_score,components,parameter_format",
+		"limit":  strconv.Itoa(templatePageSize),
+	}
+
+	var templates []TemplateDefinition
+	for {
+		var page templateListResponse
+		if err := c.get(ctx, endpoint, query, &page); err != nil {
+			return nil, err
+		}
+		templates = append(templates, page.Data...)
+
+		if page.Paging.Next == "" || page.Paging.Cursors.After == "" {
+			break
+		}
+		query["after"] = page.Paging.Cursors.After
+	}
+
+	return templates, nil
+}
+
+// DeleteTemplate deletes a template from the WhatsApp Business Account.
+// Passing the template ID deletes only that language; otherwise all languages with the name are deleted.
+func (c *Client) DeleteTemplate(ctx context.Context, name, templateID string) error {
+	if err := c.requireBusinessAccount(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	req := c.request(ctx).SetQueryParam("name", name)
+	if templateID != "" {
+		req.SetQueryParam("hsm_id", templateID)
+	}
+
+	resp, err := req.Delete(fmt.Sprintf("/%s/message_templates", c.businessAccountID))
+	if err != nil {
+		c.logger.Error("Failed to delete template", zap.Error(err), zap.String("name", name))
+		return errors.NewWhatsAppError(err)
+	}
+
+	if resp.IsError() {
+		return c.parseError(resp)
+	}
+
+	c.logger.Info("Template deleted", zap.String("name", name), zap.String("template_id", templateID))
+	return nil
+}
+
+// HasBusinessAccount returns true if the client can manage templates
+func (c *Client) HasBusinessAccount() bool {
+	return c.businessAccountID != ""
+}
+
+func (c *Client) requireBusinessAccount() error {
+	if c.businessAccountID == "" {
+		return errors.NewBadRequest("WHATSAPP_BUSINESS_ACCOUNT_ID is not configured")
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package whatsapp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ashok/vibecoded-wa-client/pkg/utils"
@@ -14,6 +16,18 @@ func VerifySignature(body []byte, signature string, secret string) bool {
 	return utils.VerifyHMAC(body, []byte(secret), signature)
 }
 
+// VerifySignatureAny verifies the webhook signature against each of the secrets,
+// so that deliveries signed with the previous app secret are still accepted while
+// it is being rotated. Empty secrets never match.
+func VerifySignatureAny(body []byte, signature string, secrets []string) bool {
+	for _, secret := range secrets {
+		if secret != "" && VerifySignature(body, signature, secret) {
+			return true
+		}
+	}
+	return false
+}
+
 // ParseWebhook parses the webhook payload
 func ParseWebhook(body []byte) (*WebhookPayload, error) {
 	var payload WebhookPayload
@@ -23,23 +37,29 @@ func ParseWebhook(body []byte) (*WebhookPayload, error) {
 	return &payload, nil
 }
 
-// ParseMessageEvent extracts message events from webhook payload
+// ParseMessageEvent extracts message events from webhook payload. Each message is
+// parsed on its own: one that cannot be parsed is left out and its error is
+// joined into the returned error, while the others are still returned.
 func ParseMessageEvent(payload *WebhookPayload) ([]*MessageEvent, error) {
 	var events []*MessageEvent
+	var errs []error
 
 	for _, entry := range payload.Entry {
 		for _, change := range entry.Changes {
 			for _, msg := range change.Value.Messages {
 				event, err := parseMessageValue(&msg, change.Value.Contacts)
 				if err != nil {
-					return nil, err
+					errs = append(errs, fmt.Errorf("message %s: %w", msg.ID, err))
+					continue
 				}
+				event.PhoneNumberID = change.Value.Metadata.PhoneNumberID
+				event.DisplayPhoneNumber = change.Value.Metadata.DisplayPhoneNumber
 				events = append(events, event)
 			}
 		}
 	}
 
-	return events, nil
+	return events, errors.Join(errs...)
 }
 
 // parseMessageValue converts a MessageValue to MessageEvent
@@ -64,6 +84,12 @@ func parseMessageValue(msg *MessageValue, contacts []ContactValue) (*MessageEven
 		}
 	}
 
+	// Extract reply context
+	if msg.Context != nil {
+		event.ReplyToID = msg.Context.ID
+		event.Forwarded = msg.Context.Forwarded || msg.Context.FrequentlyForwarded
+	}
+
 	// Extract content based on message type
 	switch msg.Type {
 	case "text":
@@ -71,55 +97,175 @@ func parseMessageValue(msg *MessageValue, contacts []ContactValue) (*MessageEven
 			event.Content = msg.Text.Body
 		}
 
-	case "image":
-		if msg.Image != nil {
-			event.MediaID = msg.Image.ID
-			event.MimeType = msg.Image.MimeType
-			event.Caption = msg.Image.Caption
+	case "image", "document", "audio", "video", "sticker":
+		if media := msg.media(); media != nil {
+			event.MediaID = media.ID
+			event.MimeType = media.MimeType
+			event.SHA256 = media.SHA256
+			event.Caption = media.Caption
+			event.Filename = media.Filename
+			event.Voice = media.Voice
+			event.Animated = media.Animated
 		}
 
-	case "document":
-		if msg.Document != nil {
-			event.MediaID = msg.Document.ID
-			event.MimeType = msg.Document.MimeType
-			event.Caption = msg.Document.Caption
-			event.Filename = msg.Document.Filename
+	case "location":
+		if msg.Location != nil {
+			event.Location = msg.Location
+			event.Content = msg.Location.Name
+			if event.Content == "" {
+				event.Content = msg.Location.Address
+			}
 		}
 
-	case "audio":
-		if msg.Audio != nil {
-			event.MediaID = msg.Audio.ID
-			event.MimeType = msg.Audio.MimeType
+	case "contacts":
+		if len(msg.Contacts) > 0 {
+			event.Contacts = msg.Contacts
+			names := make([]string, 0, len(msg.Contacts))
+			for _, contact := range msg.Contacts {
+				names = append(names, contact.Name.FormattedName)
+			}
+			event.Content = strings.Join(names, ", ")
 		}
 
-	case "video":
-		if msg.Video != nil {
-			event.MediaID = msg.Video.ID
-			event.MimeType = msg.Video.MimeType
-			event.Caption = msg.Video.Caption
+	case "reaction":
+		if msg.Reaction != nil {
+			event.Reaction = msg.Reaction
+			event.Content = msg.Reaction.Emoji
+			event.ReplyToID = msg.Reaction.MessageID
+		}
+
+	case "interactive":
+		if msg.Interactive == nil {
+			break
+		}
+		switch {
+		case msg.Interactive.NFMReply != nil:
+			reply := parseFlowReply(msg.Interactive.NFMReply)
+			event.FlowReply = reply
+			event.Content = reply.Body
+		case msg.Interactive.ButtonReply != nil:
+			event.Reply = newReply(ReplyTypeButton, msg.Interactive.ButtonReply)
+			event.Content = event.Reply.Title
+		case msg.Interactive.ListReply != nil:
+			event.Reply = newReply(ReplyTypeList, msg.Interactive.ListReply)
+			event.Content = event.Reply.Title
+		default:
+			event.Raw = msg.Raw
+		}
+
+	case "button":
+		if msg.Button != nil {
+			event.Reply = &Reply{
+				Type:  ReplyTypeQuickReply,
+				ID:    msg.Button.Payload,
+				Title: msg.Button.Text,
+			}
+			event.Content = msg.Button.Text
+		}
+
+	case "order":
+		if msg.Order != nil {
+			event.Order = msg.Order
+			event.Content = msg.Order.Text
+		}
+
+	case "system":
+		if msg.System != nil {
+			event.System = msg.System
+			event.Content = msg.System.Body
+		}
+
+	case "unsupported":
+		// Details of unsupported messages are in Errors, set below
+
+	default:
+		// Keep types we do not know yet so they can be handled later
+		event.Raw = msg.Raw
+	}
+
+	// Messages from ads carry the referral; failed messages carry errors
+	event.Referral = msg.Referral
+	if len(msg.Errors) > 0 {
+		event.Errors = msg.Errors
+		if event.Content == "" {
+			event.Content = msg.Errors[0].Title
 		}
 	}
 
 	return event, nil
 }
 
-// ParseStatusEvent extracts status update events from webhook payload
+// media returns the media object of a media message
+func (m *MessageValue) media() *MediaValue {
+	switch m.Type {
+	case "image":
+		return m.Image
+	case "document":
+		return m.Document
+	case "audio":
+		return m.Audio
+	case "video":
+		return m.Video
+	case "sticker":
+		return m.Sticker
+	}
+	return nil
+}
+
+func newReply(replyType string, reply *InteractiveReply) *Reply {
+	return &Reply{
+		Type:        replyType,
+		ID:          reply.ID,
+		Title:       reply.Title,
+		Description: reply.Description,
+	}
+}
+
+// parseFlowReply decodes the response JSON of a completed flow
+func parseFlowReply(reply *NFMReply) *FlowReply {
+	flowReply := &FlowReply{
+		Name: reply.Name,
+		Body: reply.Body,
+	}
+
+	if reply.ResponseJSON != "" {
+		if err := json.Unmarshal([]byte(reply.ResponseJSON), &flowReply.Response); err != nil {
+			// Keep the reply so the rest of the payload is still processed
+			flowReply.Response = nil
+			flowReply.ResponseJSON = reply.ResponseJSON
+			flowReply.ParseError = fmt.Sprintf("invalid flow response_json: %v", err)
+			return flowReply
+		}
+	}
+	if token, ok := flowReply.Response["flow_token"].(string); ok {
+		flowReply.FlowToken = token
+	}
+
+	return flowReply
+}
+
+// ParseStatusEvent extracts status update events from webhook payload. Like
+// ParseMessageEvent, statuses that cannot be parsed are left out and reported in
+// the returned error.
 func ParseStatusEvent(payload *WebhookPayload) ([]*StatusEvent, error) {
 	var events []*StatusEvent
+	var errs []error
 
 	for _, entry := range payload.Entry {
 		for _, change := range entry.Changes {
 			for _, status := range change.Value.Statuses {
 				event, err := parseStatusValue(&status)
 				if err != nil {
-					return nil, err
+					errs = append(errs, fmt.Errorf("status %s of %s: %w", status.Status, status.ID, err))
+					continue
 				}
+				event.PhoneNumberID = change.Value.Metadata.PhoneNumberID
 				events = append(events, event)
 			}
 		}
 	}
 
-	return events, nil
+	return events, errors.Join(errs...)
 }
 
 // parseStatusValue converts a StatusValue to StatusEvent
@@ -141,7 +287,112 @@ func parseStatusValue(status *StatusValue) (*StatusEvent, error) {
 		event.ErrorCode = status.Errors[0].Code
 		event.ErrorTitle = status.Errors[0].Title
 		event.ErrorMsg = status.Errors[0].Message
+		event.ErrorDetails = status.Errors[0].ErrorData.Details
 	}
 
+	if status.Conversation != nil {
+		event.ConversationID = status.Conversation.ID
+		event.ConversationOrigin = status.Conversation.Origin.Type
+		if status.Conversation.ExpirationTimestamp != "" {
+			expiry, err := strconv.ParseInt(status.Conversation.ExpirationTimestamp, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid conversation expiration timestamp: %w", err)
+			}
+			expiresAt := time.Unix(expiry, 0)
+			event.ConversationExpiresAt = &expiresAt
+		}
+	}
+	event.Pricing = status.Pricing
+
 	return event, nil
 }
+
+// ParseChangeErrors extracts the errors reported at the value level of messages
+// changes, such as a delivery WhatsApp could not build for the business number
+func ParseChangeErrors(payload *WebhookPayload) []*ChangeErrorEvent {
+	var events []*ChangeErrorEvent
+
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			if len(change.Value.Errors) == 0 {
+				continue
+			}
+			if change.Field != "" && change.Field != WebhookFieldMessages {
+				continue
+			}
+			events = append(events, &ChangeErrorEvent{
+				PhoneNumberID: change.Value.Metadata.PhoneNumberID,
+				Errors:        change.Value.Errors,
+				Raw:           change.Value.Raw,
+			})
+		}
+	}
+
+	return events
+}
+
+// ParseAccountEvents extracts changes to account-level fields such as template
+// and phone number updates. Changes to the messages field are skipped; fields
+// without a typed value are returned with only Raw set. Each change is decoded
+// on its own: one whose value does not decode is also returned with only Raw
+// set, and DecodeError says why, so it does not hold back the others.
+func ParseAccountEvents(payload *WebhookPayload) []*AccountEvent {
+	var events []*AccountEvent
+
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			if change.Field == "" || change.Field == WebhookFieldMessages {
+				continue
+			}
+
+			event := &AccountEvent{
+				Field:             change.Field,
+				BusinessAccountID: entry.ID,
+				Raw:               change.Value.Raw,
+			}
+			if entry.Time > 0 {
+				event.Timestamp = time.Unix(entry.Time, 0)
+			}
+
+			var target interface{}
+			switch change.Field {
+			case WebhookFieldTemplateStatusUpdate:
+				event.TemplateStatus = &TemplateStatusUpdate{}
+				target = event.TemplateStatus
+			case WebhookFieldTemplateQualityUpdate:
+				event.TemplateQuality = &TemplateQualityUpdate{}
+				target = event.TemplateQuality
+			case WebhookFieldPhoneNumberQualityUpdate:
+				event.PhoneNumberQuality = &PhoneNumberQualityUpdate{}
+				target = event.PhoneNumberQuality
+			case WebhookFieldPhoneNumberNameUpdate:
+				event.PhoneNumberName = &PhoneNumberNameUpdate{}
+				target = event.PhoneNumberName
+			case WebhookFieldAccountUpdate:
+				event.Account = &AccountUpdate{}
+				target = event.Account
+			case WebhookFieldAccountAlerts:
+				event.Alert = &AccountAlert{}
+				target = event.Alert
+			case WebhookFieldBusinessCapabilityUpdate:
+				event.Capability = &BusinessCapabilityUpdate{}
+				target = event.Capability
+			}
+
+			if target != nil && len(change.Value.Raw) > 0 {
+				if err := json.Unmarshal(change.Value.Raw, target); err != nil {
+					event = &AccountEvent{
+						Field:             event.Field,
+						BusinessAccountID: event.BusinessAccountID,
+						Timestamp:         event.Timestamp,
+						Raw:               event.Raw,
+						DecodeError:       fmt.Sprintf("invalid %s value: %v", change.Field, err),
+					}
+				}
+			}
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,533 @@
+package whatsapp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp"
+	"github.com/ashok/vibecoded-wa-client/internal/whatsapp/fake"
+)
+
+const testAppSecret = "test-app-secret"
+
+// delivery is a webhook received from the fake
+type delivery struct {
+	body      []byte
+	signature string
+}
+
+// newWebhookReceiver starts a server that collects webhooks emitted by the fake
+func newWebhookReceiver(t *testing.T, srv *fake.Server) <-chan delivery {
+	t.Helper()
+
+	deliveries := make(chan delivery, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- delivery{body: body, signature: r.Header.Get("X-Hub-Signature-256")}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(receiver.Close)
+
+	srv.SetWebhookURL(receiver.URL)
+	return deliveries
+}
+
+func receive(t *testing.T, deliveries <-chan delivery) delivery {
+	t.Helper()
+	select {
+	case d := <-deliveries:
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for a webhook")
+		return delivery{}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	srv := fake.NewServer(fake.Options{AppSecret: testAppSecret})
+	deliveries := newWebhookReceiver(t, srv)
+
+	if _, err := srv.EmitText(context.Background(), "15551234567", "Alice", "hi"); err != nil {
+		t.Fatalf("EmitText failed: %v", err)
+	}
+	d := receive(t, deliveries)
+
+	if !whatsapp.VerifySignature(d.body, d.signature, testAppSecret) {
+		t.Errorf("Expected signature %q to verify", d.signature)
+	}
+	if whatsapp.VerifySignature(d.body, d.signature, "other-secret") {
+		t.Errorf("Signature should not verify with another secret")
+	}
+	if whatsapp.VerifySignature(append(d.body, ' '), d.signature, testAppSecret) {
+		t.Errorf("Signature should not verify a modified body")
+	}
+
+	if !whatsapp.VerifySignatureAny(d.body, d.signature, []string{"new-secret", testAppSecret}) {
+		t.Errorf("Signature should verify with a previous secret during rotation")
+	}
+	if whatsapp.VerifySignatureAny(d.body, d.signature, []string{"new-secret", ""}) {
+		t.Errorf("Signature should not verify with unrelated or empty secrets")
+	}
+}
+
+func TestParseTextMessage(t *testing.T) {
+	srv := fake.NewServer(fake.Options{DisplayPhoneNumber: "+1 555-010-1234"})
+	deliveries := newWebhookReceiver(t, srv)
+
+	id, err := srv.EmitText(context.Background(), "15551234567", "Alice", "hello there")
+	if err != nil {
+		t.Fatalf("EmitText failed: %v", err)
+	}
+
+	events := parseMessages(t, receive(t, deliveries).body)
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 message event, got %d", len(events))
+	}
+	event := events[0]
+	if event.MessageID != id || event.From != "15551234567" || event.ContactName != "Alice" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if event.Type != "text" || event.Content != "hello there" {
+		t.Errorf("Expected text hello there, got %s %q", event.Type, event.Content)
+	}
+	if event.PhoneNumberID != fake.DefaultPhoneNumberID || event.DisplayPhoneNumber != "+1 555-010-1234" {
+		t.Errorf("Unexpected metadata: %s %s", event.PhoneNumberID, event.DisplayPhoneNumber)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	srv := fake.NewServer(fake.Options{})
+	deliveries := newWebhookReceiver(t, srv)
+
+	status := whatsapp.StatusValue{ID: "wamid.out", Status: "failed", RecipientID: "15551234567"}
+	status.Errors = append(status.Errors, whatsapp.WebhookError{
+		Code:      whatsapp.ErrorCodeReengagementRequired,
+		Title:     "Re-engagement message",
+		ErrorData: whatsapp.ErrorData{Details: "Message failed to send because more than 24 hours have passed"},
+	})
+
+	if err := srv.EmitStatusValue(context.Background(), status); err != nil {
+		t.Fatalf("EmitStatusValue failed: %v", err)
+	}
+
+	payload, err := whatsapp.ParseWebhook(receive(t, deliveries).body)
+	if err != nil {
+		t.Fatalf("ParseWebhook failed: %v", err)
+	}
+	events, err := whatsapp.ParseStatusEvent(payload)
+	if err != nil {
+		t.Fatalf("ParseStatusEvent failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 status event, got %d", len(events))
+	}
+	event := events[0]
+	if event.MessageID != "wamid.out" || event.Status != "failed" || event.ErrorCode != whatsapp.ErrorCodeReengagementRequired {
+		t.Errorf("Unexpected status event: %+v", event)
+	}
+	if event.ErrorDetails == "" {
+		t.Errorf("Expected the error details to be parsed")
+	}
+	if event.PhoneNumberID != fake.DefaultPhoneNumberID {
+		t.Errorf("Expected phone number ID %s, got %s", fake.DefaultPhoneNumberID, event.PhoneNumberID)
+	}
+}
+
+func TestAutoStatuses(t *testing.T) {
+	client, srv := newTestClient(t, fake.Options{AutoStatuses: true})
+	deliveries := newWebhookReceiver(t, srv)
+
+	resp, err := client.SendTextMessage(context.Background(), "15551234567", "hello")
+	if err != nil {
+		t.Fatalf("SendTextMessage failed: %v", err)
+	}
+
+	for _, want := range []string{"sent", "delivered"} {
+		payload, err := whatsapp.ParseWebhook(receive(t, deliveries).body)
+		if err != nil {
+			t.Fatalf("ParseWebhook failed: %v", err)
+		}
+		events, err := whatsapp.ParseStatusEvent(payload)
+		if err != nil || len(events) != 1 {
+			t.Fatalf("Expected 1 status event, got %d (%v)", len(events), err)
+		}
+		if events[0].MessageID != resp.Messages[0].ID || events[0].Status != want {
+			t.Errorf("Expected %s for %s, got %+v", want, resp.Messages[0].ID, events[0])
+		}
+	}
+}
+
+func TestParseFlowReply(t *testing.T) {
+	srv := fake.NewServer(fake.Options{})
+	deliveries := newWebhookReceiver(t, srv)
+
+	response, _ := json.Marshal(map[string]interface{}{
+		"flow_token": "flowtok_123",
+		"rating":     "5",
+	})
+	message := whatsapp.MessageValue{
+		From: "15551234567",
+		Type: "interactive",
+		Interactive: &whatsapp.InboundInteractive{
+			Type: "nfm_reply",
+			NFMReply: &whatsapp.NFMReply{
+				Name:         "flow",
+				Body:         "Sent",
+				ResponseJSON: string(response),
+			},
+		},
+		Context: &whatsapp.MessageContext{ID: "wamid.flow"},
+	}
+	if err := srv.EmitMessage(context.Background(), "Alice", message); err != nil {
+		t.Fatalf("EmitMessage failed: %v", err)
+	}
+
+	events := parseMessages(t, receive(t, deliveries).body)
+	if len(events) != 1 || events[0].FlowReply == nil {
+		t.Fatalf("Expected a flow reply, got %+v", events)
+	}
+	reply := events[0].FlowReply
+	if reply.FlowToken != "flowtok_123" || reply.Response["rating"] != "5" {
+		t.Errorf("Unexpected flow reply: %+v", reply)
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	srv := fake.NewServer(fake.Options{})
+	deliveries := newWebhookReceiver(t, srv)
+
+	message := whatsapp.MessageValue{
+		From: "15551234567",
+		Type: "order",
+		Order: &whatsapp.Order{
+			CatalogID: "catalog_1",
+			Text:      "Please deliver after 5pm",
+			ProductItems: []whatsapp.OrderProductItem{
+				{ProductRetailerID: "sku-1", Quantity: 2, ItemPrice: 10.5, Currency: "USD"},
+				{ProductRetailerID: "sku-2", Quantity: 1, ItemPrice: 4, Currency: "USD"},
+			},
+		},
+	}
+	if err := srv.EmitMessage(context.Background(), "Alice", message); err != nil {
+		t.Fatalf("EmitMessage failed: %v", err)
+	}
+
+	events := parseMessages(t, receive(t, deliveries).body)
+	if len(events) != 1 || events[0].Order == nil {
+		t.Fatalf("Expected an order, got %+v", events)
+	}
+	order := events[0].Order
+	if order.CatalogID != "catalog_1" || len(order.ProductItems) != 2 {
+		t.Errorf("Unexpected order: %+v", order)
+	}
+	if order.Total() != 25 || order.Currency() != "USD" {
+		t.Errorf("Expected total 25 USD, got %v %s", order.Total(), order.Currency())
+	}
+}
+
+func TestParseWebhookInvalidJSON(t *testing.T) {
+	if _, err := whatsapp.ParseWebhook([]byte("{not json")); err == nil {
+		t.Errorf("Expected an error for invalid JSON")
+	}
+}
+
+func parseMessages(t *testing.T, body []byte) []*whatsapp.MessageEvent {
+	t.Helper()
+
+	payload, err := whatsapp.ParseWebhook(body)
+	if err != nil {
+		t.Fatalf("ParseWebhook failed: %v", err)
+	}
+	events, err := whatsapp.ParseMessageEvent(payload)
+	if err != nil {
+		t.Fatalf("ParseMessageEvent failed: %v", err)
+	}
+	return events
+}
+
+func TestParseInboundTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		check   func(t *testing.T, event *whatsapp.MessageEvent)
+	}{
+		{
+			name:    "sticker",
+			message: `{"type":"sticker","sticker":{"id":"media_1","mime_type":"image/webp","sha256":"abc","animated":true}}`,
+			check: func(t *testing.T, event *whatsapp.MessageEvent) {
+				if event.MediaID != "media_1" || event.MimeType != "image/webp" || !event.Animated {
+					t.Errorf("Unexpected sticker event: %+v", event)
+				}
+			},
+		},
+		{
+			name:    "voice note",
+			message: `{"type":"audio","audio":{"id":"media_2","mime_type":"audio/ogg; codecs=opus","sha256":"abc","voice":true}}`,
+			check: func(t *testing.T, event *whatsapp.MessageEvent) {
+				if event.MediaID != "media_2" || !event.Voice {
+					t.Errorf("Unexpected audio event: %+v", event)
+				}
+			},
+		},
+		{
+			name:    "button reply",
+			message: `{"type":"interactive","interactive":{"type":"button_reply","button_reply":{"id":"yes","title":"Yes"}}}`,
+			check: func(t *testing.T, event *whatsapp.MessageEvent) {
+				if event.Reply == nil || event.Reply.Type != whatsapp.ReplyTypeButton || event.Reply.ID != "yes" || event.Content != "Yes" {
+					t.Errorf("Unexpected button reply: %+v", event.Reply)
+				}
+			},
+		},
+		{
+			name:    "flow reply with malformed response",
+			message: `{"type":"interactive","interactive":{"type":"nfm_reply","nfm_reply":{"name":"flow","body":"Sent","response_json":"{not json"}}}`,
+			check: func(t *testing.T, event *whatsapp.MessageEvent) {
+				reply := event.FlowReply
+				if reply == nil || reply.ParseError == "" || reply.ResponseJSON != "{not json" || event.Content != "Sent" {
+					t.Errorf("Expected the flow reply to be kept with its raw response, got %+v", reply)
+				}
+			},
+		},
+		{
+			name:    "list reply",
+			message: `{"type":"interactive","interactive":{"type":"list_reply","list_reply":{"id":"row_1","title":"Row 1","description":"First"}}}`,
+			check: func(t *testing.T, event *whatsapp.MessageEvent) {
+				if event.Reply == nil || event.Reply.Type != whatsapp.ReplyTypeList || event.Reply.Description != "First" {
+					t.Errorf("Unexpected list reply: %+v", event.Reply)
+				}
+			},
+		},
+		{
+			name:    "quick reply",
+			message: `{"type":"button","button":{"payload":"STOP","text":"Stop promotions"},"context":{"from":"15550000000","id":"wamid.template"}}`,
+			check: func(t *testing.T, event *whatsapp.MessageEvent) {
+				if event.Reply == nil || event.Reply.Type != whatsapp.ReplyTypeQuickReply || event.Reply.ID != "STOP" || event.Content != "Stop promotions" {
+					t.Errorf("Unexpected quick reply: %+v", event.Reply)
+				}
+				if event.ReplyToID != "wamid.template" {
+					t.Errorf("Expected the quick reply to quote the template, got %q", event.ReplyToID)
+				}
+			},
+		},
+		{
+			name:    "system",
+			message: `{"type":"system","system":{"body":"User A changed from 15551234567 to 15557654321","new_wa_id":"15557654321","type":"user_changed_number"}}`,
+			check: func(t *testing.T, event *whatsapp.MessageEvent) {
+				if event.System == nil || event.System.NewWaID != "15557654321" || event.Content == "" {
+					t.Errorf("Unexpected system event: %+v", event.System)
+				}
+			},
+		},
+		{
+			name:    "referral",
+			message: `{"type":"text","text":{"body":"I saw your ad"},"referral":{"source_url":"https://fb.me/ad","source_id":"ad_1","source_type":"ad","headline":"Sale","ctwa_clid":"clid_1"}}`,
+			check: func(t *testing.T, event *whatsapp.MessageEvent) {
+				if event.Referral == nil || event.Referral.SourceID != "ad_1" || event.Referral.CtwaClid != "clid_1" || event.Content != "I saw your ad" {
+					t.Errorf("Unexpected referral: %+v", event.Referral)
+				}
+			},
+		},
+		{
+			name:    "unsupported",
+			message: `{"type":"unsupported","errors":[{"code":131051,"title":"Message type unknown","error_data":{"details":"Message type is currently not supported."}}]}`,
+			check: func(t *testing.T, event *whatsapp.MessageEvent) {
+				if len(event.Errors) != 1 || event.Errors[0].Code != 131051 || event.Content != "Message type unknown" {
+					t.Errorf("Unexpected unsupported event: %+v", event.Errors)
+				}
+			},
+		},
+		{
+			name:    "unknown type",
+			message: `{"type":"hologram","hologram":{"id":"h1"}}`,
+			check: func(t *testing.T, event *whatsapp.MessageEvent) {
+				var raw map[string]interface{}
+				if err := json.Unmarshal(event.Raw, &raw); err != nil {
+					t.Fatalf("Expected the raw message to be kept: %v", err)
+				}
+				if raw["hologram"] == nil {
+					t.Errorf("Raw message lost the unknown payload: %s", event.Raw)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var message map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.message), &message); err != nil {
+				t.Fatalf("Invalid test message: %v", err)
+			}
+			message["from"] = "15551234567"
+			message["id"] = "wamid.in"
+			message["timestamp"] = "1700000000"
+
+			body, _ := json.Marshal(map[string]interface{}{
+				"object": "whatsapp_business_account",
+				"entry": []interface{}{map[string]interface{}{
+					"id": "waba",
+					"changes": []interface{}{map[string]interface{}{
+						"field": "messages",
+						"value": map[string]interface{}{
+							"messaging_product": "whatsapp",
+							"metadata":          map[string]interface{}{"display_phone_number": "15550000000", "phone_number_id": "111"},
+							"messages":          []interface{}{message},
+						},
+					}},
+				}},
+			})
+
+			events := parseMessages(t, body)
+			if len(events) != 1 {
+				t.Fatalf("Expected 1 message event, got %d", len(events))
+			}
+			if events[0].Type != message["type"] {
+				t.Errorf("Unexpected event type %q", events[0].Type)
+			}
+			tt.check(t, events[0])
+		})
+	}
+}
+
+func TestParseChangeErrors(t *testing.T) {
+	body := []byte(`{
+		"object": "whatsapp_business_account",
+		"entry": [{
+			"id": "waba",
+			"changes": [{
+				"field": "messages",
+				"value": {
+					"messaging_product": "whatsapp",
+					"metadata": {"display_phone_number": "15550000000", "phone_number_id": "111"},
+					"errors": [{"code": 131000, "title": "Something went wrong", "error_data": {"details": "try again"}}]
+				}
+			}]
+		}]
+	}`)
+
+	payload, err := whatsapp.ParseWebhook(body)
+	if err != nil {
+		t.Fatalf("ParseWebhook failed: %v", err)
+	}
+	events := whatsapp.ParseChangeErrors(payload)
+	if len(events) != 1 || len(events[0].Errors) != 1 {
+		t.Fatalf("Expected 1 change error, got %+v", events)
+	}
+	event := events[0]
+	if event.PhoneNumberID != "111" || event.Errors[0].Code != 131000 || event.Errors[0].ErrorData.Details != "try again" {
+		t.Errorf("Unexpected change error: %+v", event)
+	}
+	if len(event.Raw) == 0 {
+		t.Errorf("Expected the raw change value to be kept")
+	}
+}
+
+func TestParseAccountEvents(t *testing.T) {
+	body := []byte(`{
+		"object": "whatsapp_business_account",
+		"entry": [{
+			"id": "waba-1",
+			"time": 1700000000,
+			"changes": [
+				{"field": "message_template_status_update", "value": {
+					"event": "REJECTED", "message_template_id": 1234567890123456,
+					"message_template_name": "order_update", "message_template_language": "en_US",
+					"reason": "INCORRECT_CATEGORY"}},
+				{"field": "message_template_quality_update", "value": {
+					"previous_quality_score": "GREEN", "new_quality_score": "YELLOW",
+					"message_template_id": 42, "message_template_name": "promo", "message_template_language": "en"}},
+				{"field": "phone_number_quality_update", "value": {
+					"display_phone_number": "15550101234", "event": "UPGRADE", "current_limit": "TIER_10K"}},
+				{"field": "phone_number_name_update", "value": {
+					"display_phone_number": "15550101234", "decision": "APPROVED", "requested_verified_name": "Vibecoded"}},
+				{"field": "account_update", "value": {
+					"phone_number": "15550101234", "event": "ACCOUNT_VIOLATION",
+					"violation_info": {"violation_type": "SCAM"}}},
+				{"field": "account_alerts", "value": {
+					"entity_type": "WABA", "entity_id": "waba-1", "alert_severity": "WARNING",
+					"alert_status": "ACTIVE", "alert_type": "OBA_APPROVED", "alert_description": "Approved"}},
+				{"field": "business_capability_update", "value": {
+					"max_daily_conversation_per_phone": 1000, "max_phone_numbers_per_business": 2}},
+				{"field": "security", "value": {"event": "PIN_CHANGED"}},
+				{"field": "messages", "value": {"messaging_product": "whatsapp", "statuses": []}}
+			]
+		}]
+	}`)
+
+	payload, err := whatsapp.ParseWebhook(body)
+	if err != nil {
+		t.Fatalf("ParseWebhook failed: %v", err)
+	}
+	events := whatsapp.ParseAccountEvents(payload)
+	if len(events) != 8 {
+		t.Fatalf("Expected 8 account events, got %d", len(events))
+	}
+
+	for _, event := range events {
+		if event.BusinessAccountID != "waba-1" || event.Timestamp.Unix() != 1700000000 || len(event.Raw) == 0 {
+			t.Errorf("Unexpected envelope for %s: %+v", event.Field, event)
+		}
+	}
+
+	if s := events[0].TemplateStatus; s == nil || s.Event != "REJECTED" ||
+		s.MessageTemplateID.String() != "1234567890123456" || s.Reason != "INCORRECT_CATEGORY" {
+		t.Errorf("Unexpected template status update: %+v", s)
+	}
+	if q := events[1].TemplateQuality; q == nil || q.NewQualityScore != "YELLOW" || q.MessageTemplateName != "promo" {
+		t.Errorf("Unexpected template quality update: %+v", q)
+	}
+	if q := events[2].PhoneNumberQuality; q == nil || q.Event != "UPGRADE" || q.CurrentLimit != "TIER_10K" {
+		t.Errorf("Unexpected phone number quality update: %+v", q)
+	}
+	if n := events[3].PhoneNumberName; n == nil || n.Decision != "APPROVED" || n.RequestedVerifiedName != "Vibecoded" {
+		t.Errorf("Unexpected phone number name update: %+v", n)
+	}
+	if a := events[4].Account; a == nil || a.Event != "ACCOUNT_VIOLATION" || !bytes.Contains(events[4].Raw, []byte("SCAM")) {
+		t.Errorf("Unexpected account update: %+v", a)
+	}
+	if a := events[5].Alert; a == nil || a.AlertSeverity != "WARNING" || a.AlertType != "OBA_APPROVED" {
+		t.Errorf("Unexpected account alert: %+v", a)
+	}
+	if c := events[6].Capability; c == nil || c.MaxDailyConversationPerPhone != 1000 || c.MaxPhoneNumbersPerBusiness != 2 {
+		t.Errorf("Unexpected capability update: %+v", c)
+	}
+	if e := events[7]; e.Field != "security" || e.TemplateStatus != nil || e.Account != nil {
+		t.Errorf("Unsupported field should only carry raw JSON: %+v", e)
+	}
+}
+
+func TestParseAccountEventsUndecodableChange(t *testing.T) {
+	body := []byte(`{
+		"object": "whatsapp_business_account",
+		"entry": [{
+			"id": "waba-1",
+			"time": 1700000000,
+			"changes": [
+				{"field": "message_template_status_update", "value": {"event": ["APPROVED"]}},
+				{"field": "phone_number_quality_update", "value": {
+					"display_phone_number": "15550101234", "event": "UPGRADE", "current_limit": "TIER_10K"}}
+			]
+		}]
+	}`)
+
+	payload, err := whatsapp.ParseWebhook(body)
+	if err != nil {
+		t.Fatalf("ParseWebhook failed: %v", err)
+	}
+	events := whatsapp.ParseAccountEvents(payload)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 account events, got %d", len(events))
+	}
+
+	if e := events[0]; e.DecodeError == "" || e.TemplateStatus != nil || len(e.Raw) == 0 || e.BusinessAccountID != "waba-1" {
+		t.Errorf("Undecodable change should only carry raw JSON and the error: %+v", e)
+	}
+	if e := events[1]; e.DecodeError != "" || e.PhoneNumberQuality == nil || e.PhoneNumberQuality.Event != "UPGRADE" {
+		t.Errorf("Expected the other change to be decoded: %+v", e)
+	}
+}
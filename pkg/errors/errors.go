@@ -17,12 +17,21 @@ const (
 	ErrInvalidMessageType = "invalid_message_type"
 	ErrWhatsAppAPI        = "whatsapp_api_error"
 	ErrRateLimitExceeded  = "rate_limit_exceeded"
+	ErrPayloadTooLarge    = "payload_too_large"
 	ErrValidationFailed   = "validation_failed"
 	ErrDatabaseError      = "database_error"
 	ErrMediaUploadFailed  = "media_upload_failed"
 	ErrTemplateNotFound   = "template_not_found"
 	ErrAPIKeyExpired      = "api_key_expired"
 	ErrAPIKeyInvalid      = "api_key_invalid"
+
+	// WhatsApp Graph API failures, classified by how clients should react
+	ErrWhatsAppRateLimited    = "whatsapp_rate_limited"
+	ErrWhatsAppAuth           = "whatsapp_auth_failed"
+	ErrWhatsAppTemplate       = "whatsapp_template_error"
+	ErrWhatsAppInvalidRequest = "whatsapp_invalid_request"
+	ErrReengagementRequired   = "reengagement_required"
+	ErrRecipientUnavailable   = "recipient_unavailable"
 )
 
 // AppError represents an application error with additional context
@@ -138,6 +147,16 @@ func NewWhatsAppError(err error) *AppError {
 	return appErr
 }
 
+// NewMediaUploadError creates an error for failed media uploads
+func NewMediaUploadError(err error) *AppError {
+	appErr := NewAppError(ErrMediaUploadFailed, "Media upload failed", http.StatusBadGateway)
+	if err != nil {
+		appErr.Err = err
+		appErr.WithDetail("upload_error", err.Error())
+	}
+	return appErr
+}
+
 // NewValidationError creates a validation error with field details
 func NewValidationError(validationErrors map[string]string) *AppError {
 	err := NewAppError(ErrValidationFailed, "Validation failed", http.StatusBadRequest)
@@ -153,6 +172,11 @@ func NewRateLimitError() *AppError {
 	return NewAppError(ErrRateLimitExceeded, "Rate limit exceeded", http.StatusTooManyRequests)
 }
 
+// NewPayloadTooLarge creates a request body too large error
+func NewPayloadTooLarge(limit int64) *AppError {
+	return NewAppError(ErrPayloadTooLarge, fmt.Sprintf("Request body exceeds %d bytes", limit), http.StatusRequestEntityTooLarge)
+}
+
 // NewInvalidPhoneNumberError creates an invalid phone number error
 func NewInvalidPhoneNumberError(phone string) *AppError {
 	return NewAppError(
@@ -40,7 +40,7 @@ func ValidateURL(urlStr string) error {
 
 // ValidateMessageType validates a message type
 func ValidateMessageType(msgType string) error {
-	validTypes := []string{"text", "image", "video", "audio", "document", "location", "template"}
+	validTypes := []string{"text", "image", "video", "audio", "document", "location", "contacts", "template", "interactive", "reaction"}
 	for _, validType := range validTypes {
 		if msgType == validType {
 			return nil